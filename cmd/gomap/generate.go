@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fbarikzehi/gomap/internal/codegen"
+)
+
+// listFlag collects repeated or comma-separated flag values.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// runGenerate implements `gomap generate`, emitting reflection-free mapping
+// functions for a source/destination struct pair.
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gomap generate -src pkg.Type -dst pkg.Type [-o file] [flags]")
+		fs.PrintDefaults()
+	}
+
+	var (
+		ignore     listFlag
+		converters listFlag
+	)
+	src := fs.String("src", "", "Source struct type (pkg.Type)")
+	dst := fs.String("dst", "", "Destination struct type (pkg.Type)")
+	out := fs.String("o", "", "Output file (default: stdout)")
+	dir := fs.String("dir", ".", "Directory of the package declaring both types")
+	tag := fs.String("tag", "mapper", "Struct tag key used for field renaming")
+	jsonTag := fs.Bool("json", false, "Use json tags for field renaming")
+	yamlTag := fs.Bool("yaml", false, "Use yaml tags for field renaming")
+	bsonTag := fs.Bool("bson", false, "Use bson tags for field renaming")
+	caseInsensitive := fs.Bool("case-insensitive", false, "Match field names case-insensitively")
+	fs.Var(&ignore, "ignore", "Field names to skip (repeatable, comma-separated)")
+	fs.Var(&converters, "converter", "Converter as SrcType=func or SrcType->DstType=func (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *src == "" || *dst == "" {
+		fs.Usage()
+		return fmt.Errorf("both -src and -dst are required")
+	}
+
+	convs := make(map[string]string, len(converters))
+	for _, c := range converters {
+		typ, fn, ok := strings.Cut(c, "=")
+		if !ok || typ == "" || fn == "" {
+			return fmt.Errorf("invalid -converter %q, expected SrcType=func or SrcType->DstType=func", c)
+		}
+		convs[typ] = fn
+	}
+
+	code, err := codegen.Generate(codegen.Options{
+		Dir:             *dir,
+		Src:             *src,
+		Dst:             *dst,
+		TagName:         *tag,
+		UseJSONTag:      *jsonTag,
+		UseYAMLTag:      *yamlTag,
+		UseBSONTag:      *bsonTag,
+		CaseInsensitive: *caseInsensitive,
+		Ignore:          ignore,
+		Converters:      convs,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(*out, code, 0o644)
+}
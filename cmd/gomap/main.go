@@ -17,6 +17,22 @@ var (
 )
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := runGenerate(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "gomap generate: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "repl" {
+		if err := runRepl(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "gomap repl: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Command-line flags
 	showVersion := flag.Bool("version", false, "Show gomap version")
 	flag.Parse()
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fbarikzehi/gomap/internal/codegen"
+	"github.com/fbarikzehi/gomap/mapper"
+)
+
+const replHelp = `Commands:
+  types                     list the struct types of the package
+  src Type, dst Type        pick the source and destination types
+  set json on|off           rename fields via json tags
+  set yaml on|off           rename fields via yaml tags
+  set bson on|off           rename fields via bson tags
+  set case on|off           match field names case-insensitively
+  set tag key               struct tag key used for renaming
+  ignore Field,...          toggle ignoring fields
+  converter SrcType=func    add a converter (SrcType->DstType=func too)
+  converter -SrcType        remove a converter
+  sample Field value        set the sample value of a source field
+  show                      show field pairings and sample conversions
+  gen                       print the generated mapping code
+  reload                    re-read the package from disk
+  help, quit`
+
+// basicTypes maps builtin type names to their reflect types, for sample
+// conversions.
+var basicTypes = map[string]reflect.Type{
+	"bool":    reflect.TypeOf(false),
+	"string":  reflect.TypeOf(""),
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"rune":    reflect.TypeOf(rune(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"byte":    reflect.TypeOf(byte(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"uintptr": reflect.TypeOf(uintptr(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
+
+// samplePresets are the sample values of source fields without one.
+var samplePresets = map[reflect.Kind]string{
+	reflect.Bool:   "true",
+	reflect.String: "sample",
+}
+
+// repl holds the state of an interactive `gomap repl` session.
+type repl struct {
+	out     io.Writer
+	opts    codegen.Options
+	samples map[string]string
+
+	// parser turns sample strings into values of the source field types
+	parser *mapper.Mapper
+}
+
+// runRepl implements `gomap repl`, an interactive session for prototyping
+// a mapping between two struct types of a package: the field pairings and
+// sample conversions are shown again after every change.
+func runRepl(args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("repl", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gomap repl [-dir dir]")
+		fs.PrintDefaults()
+	}
+	dir := fs.String("dir", ".", "Directory of the package declaring the types")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	types, err := codegen.Structs(*dir)
+	if err != nil {
+		return err
+	}
+
+	r := &repl{
+		out:     out,
+		opts:    codegen.Options{Dir: *dir, TagName: "mapper", Converters: make(map[string]string)},
+		samples: make(map[string]string),
+		parser:  mapper.NewMapper(mapper.WithStringConversion(true)),
+	}
+	fmt.Fprintf(out, "gomap repl: %d struct types in %s; type help for commands\n", len(types), *dir)
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if cmd == "quit" || cmd == "exit" {
+			return nil
+		}
+		if err := r.exec(cmd, strings.TrimSpace(arg)); err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+}
+
+// exec runs one command.
+func (r *repl) exec(cmd, arg string) error {
+	switch cmd {
+	case "":
+		return nil
+	case "help":
+		fmt.Fprintln(r.out, replHelp)
+		return nil
+	case "types":
+		types, err := codegen.Structs(r.opts.Dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(r.out, strings.Join(types, " "))
+		return nil
+	case "show", "reload":
+		return r.show()
+	case "gen":
+		if err := r.ready(); err != nil {
+			return err
+		}
+		code, err := codegen.Generate(r.opts)
+		if err != nil {
+			return err
+		}
+		_, err = r.out.Write(code)
+		return err
+	}
+
+	if arg == "" {
+		return fmt.Errorf("%s needs an argument; type help for commands", cmd)
+	}
+	switch cmd {
+	case "src":
+		r.opts.Src = arg
+	case "dst":
+		r.opts.Dst = arg
+	case "set":
+		if err := r.set(arg); err != nil {
+			return err
+		}
+	case "ignore":
+		for _, name := range strings.Split(arg, ",") {
+			r.toggleIgnore(strings.TrimSpace(name))
+		}
+	case "converter":
+		if typ, ok := strings.CutPrefix(arg, "-"); ok {
+			delete(r.opts.Converters, typ)
+			break
+		}
+		typ, fn, ok := strings.Cut(arg, "=")
+		if !ok || typ == "" || fn == "" {
+			return fmt.Errorf("invalid converter %q, expected SrcType=func or SrcType->DstType=func", arg)
+		}
+		r.opts.Converters[typ] = fn
+	case "sample":
+		name, value, _ := strings.Cut(arg, " ")
+		r.samples[name] = strings.TrimSpace(value)
+	default:
+		return fmt.Errorf("unknown command %q; type help for commands", cmd)
+	}
+
+	if r.opts.Src == "" || r.opts.Dst == "" {
+		return nil
+	}
+	return r.show()
+}
+
+// set changes a matching option.
+func (r *repl) set(arg string) error {
+	name, value, _ := strings.Cut(arg, " ")
+	value = strings.TrimSpace(value)
+	if name == "tag" {
+		if value == "" {
+			return fmt.Errorf("set tag needs a tag key")
+		}
+		r.opts.TagName = value
+		return nil
+	}
+
+	var on bool
+	switch value {
+	case "on":
+		on = true
+	case "off":
+	default:
+		return fmt.Errorf("set %s expects on or off", name)
+	}
+	switch name {
+	case "json":
+		r.opts.UseJSONTag = on
+	case "yaml":
+		r.opts.UseYAMLTag = on
+	case "bson":
+		r.opts.UseBSONTag = on
+	case "case":
+		r.opts.CaseInsensitive = on
+	default:
+		return fmt.Errorf("unknown option %q, expected json, yaml, bson, case or tag", name)
+	}
+	return nil
+}
+
+// toggleIgnore adds name to the ignored fields, or removes it if present.
+func (r *repl) toggleIgnore(name string) {
+	for i, ignored := range r.opts.Ignore {
+		if ignored == name {
+			r.opts.Ignore = append(r.opts.Ignore[:i], r.opts.Ignore[i+1:]...)
+			return
+		}
+	}
+	r.opts.Ignore = append(r.opts.Ignore, name)
+}
+
+func (r *repl) ready() error {
+	if r.opts.Src == "" || r.opts.Dst == "" {
+		return fmt.Errorf("pick the types first with src and dst")
+	}
+	return nil
+}
+
+// show prints the field pairings of the current types along with sample
+// conversions, re-reading the package so edits to it show up.
+func (r *repl) show() error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	pairings, err := codegen.Pairings(r.opts)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(r.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t\t%s\t\n", r.opts.Src, r.opts.Dst)
+	for _, p := range pairings {
+		switch {
+		case p.Destination == "":
+			fmt.Fprintf(w, "%s %s\t\t(unmatched)\t\n", p.Source, p.SourceType)
+		case p.Code == "":
+			fmt.Fprintf(w, "%s %s\t->\t%s %s\t(no mapping)\n", p.Source, p.SourceType, p.Destination, p.DestinationType)
+		default:
+			fmt.Fprintf(w, "%s %s\t->\t%s %s\t%s\n", p.Source, p.SourceType, p.Destination, p.DestinationType, r.sample(p))
+		}
+	}
+	if len(r.opts.Ignore) > 0 {
+		ignored := append([]string(nil), r.opts.Ignore...)
+		sort.Strings(ignored)
+		fmt.Fprintf(w, "ignored: %s\t\t\t\n", strings.Join(ignored, ", "))
+	}
+	return w.Flush()
+}
+
+// sample describes the conversion of the sample value of a pairing of
+// basic types with the runtime mapper. Other pairings are described by
+// their converter, if any.
+func (r *repl) sample(p codegen.Pairing) string {
+	if fn, ok := r.opts.Converters[p.SourceType+"->"+p.DestinationType]; ok {
+		return "via " + fn
+	}
+	if fn, ok := r.opts.Converters[p.SourceType]; ok {
+		return "via " + fn
+	}
+
+	srcType, dstType := basicTypes[p.SourceBasic], basicTypes[p.DestinationBasic]
+	if srcType == nil || dstType == nil {
+		return ""
+	}
+
+	text, ok := r.samples[p.Source]
+	if !ok {
+		text, ok = samplePresets[srcType.Kind()]
+	}
+	if !ok {
+		text = "42"
+	}
+
+	src := reflect.New(srcType)
+	if err := r.parser.Map(src.Interface(), text); err != nil {
+		return "sample: " + err.Error()
+	}
+	dst := reflect.New(dstType)
+	if err := mapper.Copy(dst.Interface(), src.Elem().Interface()); err != nil {
+		return formatSample(src.Elem()) + ": " + err.Error()
+	}
+	return formatSample(src.Elem()) + " => " + formatSample(dst.Elem())
+}
+
+// formatSample formats a sample value along with its type.
+func formatSample(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return strconv.Quote(v.String())
+	}
+	return fmt.Sprintf("%s(%v)", v.Type(), v)
+}
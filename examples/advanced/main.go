@@ -213,11 +213,11 @@ func reusableMapper() {
 		Tags []string
 	}
 
-	m := mapper.NewMapper(
+	opts := []mapper.Option{
 		mapper.WithMaxDepth(10),
 		mapper.WithIgnoreUnexported(true),
 		mapper.WithDeepCopy(true),
-	)
+	}
 
 	sources := []Record{
 		{ID: 1, Name: "Record 1", Tags: []string{"tag1", "tag2"}},
@@ -225,12 +225,9 @@ func reusableMapper() {
 		{ID: 3, Name: "Record 3", Tags: []string{"tag5", "tag6"}},
 	}
 
-	destinations := make([]Record, len(sources))
-
-	for i, src := range sources {
-		if err := m.Map(&destinations[i], src); err != nil {
-			log.Fatal(err)
-		}
+	destinations, err := mapper.MapSlice[Record, Record](sources, opts...)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	fmt.Printf("Mapped %d records successfully\n", len(destinations))
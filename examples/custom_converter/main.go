@@ -25,6 +25,9 @@ func main() {
 
 	// Example 4: Complex type conversion
 	complexTypeConversion()
+
+	// Example 5: Enum lookup tables
+	enumMapping()
 }
 
 func timeFormatting() {
@@ -85,15 +88,13 @@ func priceConversion() {
 	}
 
 	priceConverter := func(v reflect.Value) (reflect.Value, error) {
-		if v.Kind() == reflect.Int {
-			return reflect.ValueOf(float64(v.Int()) / 100.0), nil
-		}
-		return v, nil
+		return reflect.ValueOf(float64(v.Int()) / 100.0), nil
 	}
 
+	// Only Product.Price holds cents; other int fields map unchanged
 	var dst ProductDTO
 	if err := mapper.Copy(&dst, src,
-		mapper.WithCustomConverter(reflect.TypeOf(0), priceConverter),
+		mapper.WithFieldConverter("Product.Price", priceConverter),
 	); err != nil {
 		log.Fatal(err)
 	}
@@ -187,3 +188,38 @@ func complexTypeConversion() {
 	fmt.Printf("Source: %+v\n", src)
 	fmt.Printf("DTO: %+v\n", dst)
 }
+
+func enumMapping() {
+	fmt.Println("\n5. Enum Lookup Tables:")
+
+	type OrderStatus int
+
+	type Order struct {
+		OrderID int
+		Status  OrderStatus
+	}
+
+	type OrderDTO struct {
+		OrderID int
+		Status  string
+	}
+
+	// One table maps OrderStatus to string and back
+	statuses := mapper.WithEnumMapping(reflect.TypeOf(OrderStatus(0)), reflect.TypeOf(""), map[any]any{
+		0: "pending",
+		1: "processing",
+		2: "completed",
+	})
+
+	var dst OrderDTO
+	if err := mapper.Copy(&dst, Order{OrderID: 12345, Status: 1}, statuses); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("DTO: %+v\n", dst)
+
+	var back Order
+	if err := mapper.Copy(&back, OrderDTO{OrderID: 12345, Status: "completed"}, statuses); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Order: %+v\n", back)
+}
@@ -0,0 +1,644 @@
+// Package codegen generates reflection-free Go mapping functions between
+// struct types, following the same field matching rules as the runtime
+// mapper (tags, JSON tags, case sensitivity, ignore rules and converters).
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// Options configures a code generation run.
+type Options struct {
+	// Dir is the directory of the package declaring the source and
+	// destination types. The generated code belongs to the same package.
+	Dir string
+
+	// Src and Dst name the source and destination struct types. An optional
+	// package qualifier ("models.User") must match the package name.
+	Src string
+	Dst string
+
+	// TagName is the struct tag key used for field renaming ("mapper").
+	TagName string
+
+	// UseJSONTag enables renaming via `json` tags when no mapper tag is set.
+	UseJSONTag bool
+
+	// UseYAMLTag enables renaming via `yaml` tags when no mapper or json
+	// tag is set.
+	UseYAMLTag bool
+
+	// UseBSONTag enables renaming via `bson` tags when no mapper, json or
+	// yaml tag is set.
+	UseBSONTag bool
+
+	// CaseInsensitive enables case-insensitive field name matching.
+	CaseInsensitive bool
+
+	// Ignore lists source or destination field names that are never mapped.
+	Ignore []string
+
+	// Converters maps "SrcType" or "SrcType->DstType" to the name of a
+	// conversion function, e.g. "time.Time->string" => "formatTime".
+	// Functions may return either (D) or (D, error).
+	Converters map[string]string
+}
+
+// field is a struct field as declared in source.
+type field struct {
+	name string
+	typ  ast.Expr
+	tag  reflect.StructTag
+}
+
+// pkgInfo holds the declarations of the loaded package.
+type pkgInfo struct {
+	name    string
+	structs map[string][]field
+	basics  map[string]string // named basic types → underlying builtin
+	funcs   map[string]*ast.FuncDecl
+	imports map[string]string // import name → path
+}
+
+// generator emits mapping functions for a queue of type pairs.
+type generator struct {
+	opts    Options
+	pkg     *pkgInfo
+	buf     bytes.Buffer
+	queue   [][2]string
+	done    map[[2]string]bool
+	imports map[string]string
+	tmp     int
+}
+
+// Generate loads the package in opts.Dir and returns the formatted source
+// of a Go file declaring Map<Src>To<Dst> and any nested helper functions.
+func Generate(opts Options) ([]byte, error) {
+	g, src, dst, err := newGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	pkg := g.pkg
+
+	g.enqueue(src, dst)
+	for len(g.queue) > 0 {
+		pair := g.queue[0]
+		g.queue = g.queue[1:]
+		g.emitFunc(pair[0], pair[1])
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by gomap generate; DO NOT EDIT.\n\npackage %s\n\n", pkg.name)
+	if len(g.imports) > 0 {
+		names := make([]string, 0, len(g.imports))
+		for name := range g.imports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out.WriteString("import (\n")
+		for _, name := range names {
+			fmt.Fprintf(&out, "\t%s %q\n", name, g.imports[name])
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: formatting generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// Pairing is a source field and the destination field it is mapped to.
+type Pairing struct {
+	// Source and Destination are the field names. Destination is empty
+	// when no destination field matches.
+	Source      string
+	Destination string
+
+	// SourceType and DestinationType are the declared field types.
+	SourceType      string
+	DestinationType string
+
+	// SourceBasic and DestinationBasic name the builtin type underlying
+	// the field types, or are empty when it is not a basic type.
+	SourceBasic      string
+	DestinationBasic string
+
+	// Code is the generated assignment, or empty when no reflection-free
+	// mapping between the field types is known.
+	Code string
+}
+
+// Pairings loads the package in opts.Dir and returns how the exported,
+// non-ignored fields of opts.Src pair with the fields of opts.Dst, in
+// source field order.
+func Pairings(opts Options) ([]Pairing, error) {
+	g, src, dst, err := newGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	return g.pairings(src, dst), nil
+}
+
+// Structs returns the sorted names of the struct types declared in the
+// package in dir.
+func Structs(dir string) ([]string, error) {
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(pkg.structs))
+	for name := range pkg.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// newGenerator applies the defaults of opts, loads the package and
+// resolves the source and destination types.
+func newGenerator(opts Options) (*generator, string, string, error) {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.TagName == "" {
+		opts.TagName = "mapper"
+	}
+
+	pkg, err := loadPackage(opts.Dir)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	src, err := pkg.resolve(opts.Src)
+	if err != nil {
+		return nil, "", "", err
+	}
+	dst, err := pkg.resolve(opts.Dst)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	g := &generator{
+		opts:    opts,
+		pkg:     pkg,
+		done:    make(map[[2]string]bool),
+		imports: make(map[string]string),
+	}
+	return g, src, dst, nil
+}
+
+// FuncName returns the name of the generated function mapping src to dst.
+func FuncName(src, dst string) string {
+	return "Map" + src + "To" + dst
+}
+
+// loadPackage parses the non-test Go files of dir.
+func loadPackage(dir string) (*pkgInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("codegen: %w", err)
+	}
+
+	pkg := &pkgInfo{
+		structs: make(map[string][]field),
+		basics:  make(map[string]string),
+		funcs:   make(map[string]*ast.FuncDecl),
+		imports: make(map[string]string),
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("codegen: %w", err)
+		}
+		if pkg.name == "" {
+			pkg.name = file.Name.Name
+		}
+		pkg.collect(file)
+	}
+
+	if pkg.name == "" {
+		return nil, fmt.Errorf("codegen: no Go files in %s", dir)
+	}
+	return pkg, nil
+}
+
+// collect records the type, function and import declarations of a file.
+func (p *pkgInfo) collect(file *ast.File) {
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		p.imports[name] = path
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				p.funcs[d.Name.Name] = d
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.TypeParams != nil {
+					continue
+				}
+				switch t := ts.Type.(type) {
+				case *ast.StructType:
+					p.structs[ts.Name.Name] = structFields(t)
+				case *ast.Ident:
+					p.basics[ts.Name.Name] = t.Name
+				}
+			}
+		}
+	}
+}
+
+// structFields flattens the field list of a struct type.
+func structFields(st *ast.StructType) []field {
+	var fields []field
+	for _, f := range st.Fields.List {
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			raw, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflect.StructTag(raw)
+		}
+
+		if len(f.Names) == 0 {
+			// Embedded field: named after its type
+			name := exprString(f.Type)
+			name = strings.TrimPrefix(name, "*")
+			name = name[strings.LastIndex(name, ".")+1:]
+			fields = append(fields, field{name: name, typ: f.Type, tag: tag})
+			continue
+		}
+		for _, n := range f.Names {
+			fields = append(fields, field{name: n.Name, typ: f.Type, tag: tag})
+		}
+	}
+	return fields
+}
+
+// resolve validates a possibly package-qualified struct type name.
+func (p *pkgInfo) resolve(name string) (string, error) {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		if qualifier := name[:i]; qualifier != p.name {
+			return "", fmt.Errorf("codegen: type %s is not in package %s", name, p.name)
+		}
+		name = name[i+1:]
+	}
+	if _, ok := p.structs[name]; !ok {
+		return "", fmt.Errorf("codegen: struct type %s not found in package %s", name, p.name)
+	}
+	return name, nil
+}
+
+func (g *generator) enqueue(src, dst string) string {
+	pair := [2]string{src, dst}
+	if !g.done[pair] {
+		g.done[pair] = true
+		g.queue = append(g.queue, pair)
+	}
+	return FuncName(src, dst)
+}
+
+// emitFunc writes the mapping function for one struct type pair.
+func (g *generator) emitFunc(src, dst string) {
+	name := FuncName(src, dst)
+	fmt.Fprintf(&g.buf, "// %s maps %s into a new %s.\n", name, src, dst)
+	fmt.Fprintf(&g.buf, "func %s(src %s) (%s, error) {\n\tvar dst %s\n", name, src, dst, dst)
+
+	for _, p := range g.pairings(src, dst) {
+		switch {
+		case p.Destination == "":
+		case p.Code == "":
+			fmt.Fprintf(&g.buf, "\t// %s: no mapping from %s to %s\n", p.Source, p.SourceType, p.DestinationType)
+		default:
+			g.buf.WriteString(p.Code)
+		}
+	}
+
+	g.buf.WriteString("\treturn dst, nil\n}\n\n")
+}
+
+// pairings matches the fields of one struct type pair.
+func (g *generator) pairings(src, dst string) []Pairing {
+	var pairings []Pairing
+	dstFields := g.pkg.structs[dst]
+	for _, sf := range g.pkg.structs[src] {
+		if !ast.IsExported(sf.name) || g.ignored(sf) {
+			continue
+		}
+
+		p := Pairing{
+			Source:      sf.name,
+			SourceType:  exprString(sf.typ),
+			SourceBasic: g.basic(sf.typ),
+		}
+		if df, ok := g.matchField(g.destName(sf), dstFields); ok {
+			p.Destination = df.name
+			p.DestinationType = exprString(df.typ)
+			p.DestinationBasic = g.basic(df.typ)
+			p.Code, _ = g.assign("dst."+df.name, "src."+sf.name, df.typ, sf.typ)
+		}
+		pairings = append(pairings, p)
+	}
+	return pairings
+}
+
+// basic returns the builtin type underlying t, or "" if it is not basic.
+func (g *generator) basic(t ast.Expr) string {
+	if u, ok := g.underlying(exprString(t)); ok {
+		return u
+	}
+	return ""
+}
+
+func (g *generator) ignored(f field) bool {
+	if f.tag.Get(g.opts.TagName) == "-" {
+		return true
+	}
+	for _, name := range g.opts.Ignore {
+		if name == f.name {
+			return true
+		}
+	}
+	return false
+}
+
+// destName mirrors the runtime mapper's destination name resolution.
+func (g *generator) destName(f field) string {
+	if tag, _, _ := strings.Cut(f.tag.Get(g.opts.TagName), ","); tag != "" && tag != "-" {
+		return tag
+	}
+	if tag := g.encodingTag(f); tag != "" {
+		return tag
+	}
+	return f.name
+}
+
+// encodingTag returns the json, yaml or bson tag name of f, as enabled by
+// the options, or "".
+func (g *generator) encodingTag(f field) string {
+	keys := []struct {
+		key     string
+		enabled bool
+	}{
+		{"json", g.opts.UseJSONTag},
+		{"yaml", g.opts.UseYAMLTag},
+		{"bson", g.opts.UseBSONTag},
+	}
+	for _, k := range keys {
+		if !k.enabled {
+			continue
+		}
+		if tag, _, _ := strings.Cut(f.tag.Get(k.key), ","); tag != "" && tag != "-" {
+			return tag
+		}
+	}
+	return ""
+}
+
+func (g *generator) matchField(name string, fields []field) (field, bool) {
+	for _, f := range fields {
+		if f.name == name && ast.IsExported(f.name) && !g.ignored(f) {
+			return f, true
+		}
+	}
+	if g.opts.UseJSONTag || g.opts.UseYAMLTag || g.opts.UseBSONTag {
+		for _, f := range fields {
+			tag := g.encodingTag(f)
+			if tag != "" && (tag == name || (g.opts.CaseInsensitive && reflectutil.EqualFold(tag, name))) &&
+				ast.IsExported(f.name) && !g.ignored(f) {
+				return f, true
+			}
+		}
+	}
+	if g.opts.CaseInsensitive {
+		for _, f := range fields {
+			if reflectutil.EqualFold(f.name, name) && ast.IsExported(f.name) && !g.ignored(f) {
+				return f, true
+			}
+		}
+	}
+	return field{}, false
+}
+
+// assign returns the statements assigning srcExpr (of type st) to dstExpr
+// (of type dt), or false if no reflection-free mapping is known.
+func (g *generator) assign(dstExpr, srcExpr string, dt, st ast.Expr) (string, bool) {
+	sName, dName := exprString(st), exprString(dt)
+
+	if fn, ok := g.converter(sName, dName); ok {
+		return g.call(dstExpr, fn, srcExpr, g.returnsError(fn)), true
+	}
+
+	if sName == dName {
+		return g.copyValue(dstExpr, srcExpr, st), true
+	}
+
+	_, sStruct := g.pkg.structs[sName]
+	_, dStruct := g.pkg.structs[dName]
+	if sStruct && dStruct {
+		return g.call(dstExpr, g.enqueue(sName, dName), srcExpr, true), true
+	}
+
+	sp, sPtr := st.(*ast.StarExpr)
+	dp, dPtr := dt.(*ast.StarExpr)
+	switch {
+	case sPtr && dPtr:
+		v := g.temp()
+		inner, ok := g.assign(v, "(*"+srcExpr+")", dp.X, sp.X)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n\t\tvar %s %s\n%s\t\t%s = &%s\n\t}\n",
+			srcExpr, v, exprString(dp.X), inner, dstExpr, v), true
+	case sPtr:
+		inner, ok := g.assign(dstExpr, "(*"+srcExpr+")", dt, sp.X)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n%s\t}\n", srcExpr, inner), true
+	case dPtr:
+		v := g.temp()
+		inner, ok := g.assign(v, srcExpr, dp.X, st)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\t{\n\t\tvar %s %s\n%s\t\t%s = &%s\n\t}\n", v, exprString(dp.X), inner, dstExpr, v), true
+	}
+
+	sa, sSlice := st.(*ast.ArrayType)
+	da, dSlice := dt.(*ast.ArrayType)
+	if sSlice && dSlice && sa.Len == nil && da.Len == nil {
+		i := g.temp()
+		inner, ok := g.assign(dstExpr+"["+i+"]", srcExpr+"["+i+"]", da.Elt, sa.Elt)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor %s := range %s {\n%s\t\t}\n\t}\n",
+			srcExpr, dstExpr, dName, srcExpr, i, srcExpr, inner), true
+	}
+
+	if g.convertible(sName, dName) {
+		return fmt.Sprintf("\t%s = %s(%s)\n", dstExpr, dName, srcExpr), true
+	}
+
+	return "", false
+}
+
+// copyValue copies a value between fields of identical type, duplicating
+// slices and maps so the destination does not share their storage.
+func (g *generator) copyValue(dstExpr, srcExpr string, t ast.Expr) string {
+	switch tt := t.(type) {
+	case *ast.ArrayType:
+		if tt.Len == nil {
+			return fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tcopy(%s, %s)\n\t}\n",
+				srcExpr, dstExpr, exprString(t), srcExpr, dstExpr, srcExpr)
+		}
+	case *ast.MapType:
+		k, v := g.temp(), g.temp()
+		return fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor %s, %s := range %s {\n\t\t\t%s[%s] = %s\n\t\t}\n\t}\n",
+			srcExpr, dstExpr, exprString(t), srcExpr, k, v, srcExpr, dstExpr, k, v)
+	}
+	return fmt.Sprintf("\t%s = %s\n", dstExpr, srcExpr)
+}
+
+// converter looks up a user converter by type pair, then by source type.
+func (g *generator) converter(src, dst string) (string, bool) {
+	if fn, ok := g.opts.Converters[src+"->"+dst]; ok {
+		return fn, true
+	}
+	fn, ok := g.opts.Converters[src]
+	return fn, ok
+}
+
+// call emits a call to a mapping or converter function, propagating the
+// error when the function returns one.
+func (g *generator) call(dstExpr, fn, srcExpr string, withErr bool) string {
+	if qualifier, _, ok := strings.Cut(fn, "."); ok {
+		if path, known := g.pkg.imports[qualifier]; known {
+			g.imports[qualifier] = path
+		}
+	}
+
+	if !withErr {
+		return fmt.Sprintf("\t%s = %s(%s)\n", dstExpr, fn, srcExpr)
+	}
+
+	v := g.temp()
+	return fmt.Sprintf("\t{\n\t\t%s, err := %s(%s)\n\t\tif err != nil {\n\t\t\treturn dst, err\n\t\t}\n\t\t%s = %s\n\t}\n",
+		v, fn, srcExpr, dstExpr, v)
+}
+
+// returnsError reports whether a converter declared in the package
+// returns an error as its last result.
+func (g *generator) returnsError(fn string) bool {
+	decl, ok := g.pkg.funcs[fn]
+	if !ok || decl.Type.Results == nil {
+		return false
+	}
+	results := decl.Type.Results.List
+	return len(results) > 0 && exprString(results[len(results)-1].Type) == "error" &&
+		(len(results) > 1 || len(results[0].Names) > 1)
+}
+
+// convertible reports whether a Go conversion between two (possibly named)
+// basic types is valid and value preserving; integer to string conversions
+// are excluded because they produce runes, and narrowing numeric
+// conversions, which the runtime mapper reports as lossy, because the
+// generated code would truncate silently.
+func (g *generator) convertible(src, dst string) bool {
+	su, sok := g.underlying(src)
+	du, dok := g.underlying(dst)
+	if !sok || !dok {
+		return false
+	}
+	sk, dk := basicKind(su), basicKind(du)
+	if sk != dk {
+		return false
+	}
+	if sk == reflect.Int {
+		return !reflectutil.IsNarrowingConversion(numericTypes[su], numericTypes[du])
+	}
+	return su == du
+}
+
+func (g *generator) underlying(name string) (string, bool) {
+	if u, ok := g.pkg.basics[name]; ok {
+		name = u
+	}
+	return name, basicKind(name) != reflect.Invalid
+}
+
+func (g *generator) temp() string {
+	g.tmp++
+	return "v" + strconv.Itoa(g.tmp)
+}
+
+// basicKind classifies a builtin type name as bool, numeric (reported as
+// reflect.Int) or string.
+func basicKind(name string) reflect.Kind {
+	switch name {
+	case "bool":
+		return reflect.Bool
+	case "int", "int8", "int16", "int32", "int64", "rune",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte",
+		"float32", "float64":
+		return reflect.Int
+	case "string":
+		return reflect.String
+	}
+	return reflect.Invalid
+}
+
+// numericTypes maps the builtin numeric type names to their types.
+var numericTypes = map[string]reflect.Type{
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"rune":    reflect.TypeOf(rune(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"uintptr": reflect.TypeOf(uintptr(0)),
+	"byte":    reflect.TypeOf(byte(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
+
+func exprString(e ast.Expr) string {
+	var buf bytes.Buffer
+	_ = format.Node(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
@@ -86,43 +86,51 @@ func GetFieldTag(field reflect.StructField, tagName string) (string, bool) {
 	return tag, true
 }
 
-// IsZeroValue checks if a value is zero
+// IsZeroValue checks if a value is zero. Invalid values are zero.
 func IsZeroValue(v reflect.Value) bool {
-	if !v.IsValid() {
-		return true
-	}
+	return !v.IsValid() || v.IsZero()
+}
 
-	switch v.Kind() {
-	case reflect.Bool:
-		return !v.Bool()
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return v.Int() == 0
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return v.Uint() == 0
-	case reflect.Float32, reflect.Float64:
-		return v.Float() == 0
-	case reflect.Complex64, reflect.Complex128:
-		return v.Complex() == 0
-	case reflect.String:
-		return v.String() == ""
-	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map,
-		reflect.Ptr, reflect.Slice:
-		return v.IsNil()
-	case reflect.Array:
-		for i := 0; i < v.Len(); i++ {
-			if !IsZeroValue(v.Index(i)) {
-				return false
-			}
-		}
+// IsNarrowingConversion reports whether converting a value of type src to
+// type dst via reflect.Value.Convert can lose information.
+func IsNarrowingConversion(src, dst reflect.Type) bool {
+	sk, dk := src.Kind(), dst.Kind()
+
+	switch {
+	case isInt(sk) && isInt(dk):
+		return dst.Bits() < src.Bits()
+	case isUint(sk) && isUint(dk):
+		return dst.Bits() < src.Bits()
+	case isInt(sk) && isUint(dk):
+		return true
+	case isUint(sk) && isInt(dk):
+		return dst.Bits() <= src.Bits()
+	case isFloat(sk) && (isInt(dk) || isUint(dk)):
 		return true
-	case reflect.Struct:
-		for i := 0; i < v.NumField(); i++ {
-			if !IsZeroValue(v.Field(i)) {
-				return false
-			}
+	case isFloat(sk) && isFloat(dk):
+		return dst.Bits() < src.Bits()
+	case (isInt(sk) || isUint(sk)) && isFloat(dk):
+		mantissa := 53
+		if dk == reflect.Float32 {
+			mantissa = 24
 		}
+		return src.Bits() > mantissa
+	case (isInt(sk) || isUint(sk)) && dk == reflect.String:
+		// Integer to string conversions yield a rune, not the decimal form.
 		return true
 	}
 
 	return false
 }
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
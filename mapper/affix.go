@@ -0,0 +1,42 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements prefix and suffix stripping for field matching.
+package mapper
+
+import (
+	"strings"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// hasAffixes reports whether any name prefixes or suffixes are configured.
+func (c *Config) hasAffixes() bool {
+	return len(c.SourcePrefixes) > 0 || len(c.SourceSuffixes) > 0 ||
+		len(c.DestPrefixes) > 0 || len(c.DestSuffixes) > 0
+}
+
+// stripAffixes removes the first matching prefix and the first matching
+// suffix from name. Affixes are only removed if a non-empty name remains.
+func stripAffixes(name string, prefixes, suffixes []string) string {
+	for _, prefix := range prefixes {
+		if len(name) > len(prefix) && strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+			break
+		}
+	}
+	for _, suffix := range suffixes {
+		if len(name) > len(suffix) && strings.HasSuffix(name, suffix) {
+			name = name[:len(name)-len(suffix)]
+			break
+		}
+	}
+	return name
+}
+
+// affixMatch reports whether the source field name and the destination
+// field name are equal once their configured affixes are stripped.
+func (ctx *context) affixMatch(srcName, dstName string) bool {
+	cfg := ctx.config
+	src := stripAffixes(srcName, cfg.SourcePrefixes, cfg.SourceSuffixes)
+	dst := stripAffixes(dstName, cfg.DestPrefixes, cfg.DestSuffixes)
+	return src == dst || (!cfg.CaseSensitive && reflectutil.EqualFold(src, dst))
+}
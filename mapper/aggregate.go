@@ -0,0 +1,267 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements folding a slice of sources into one destination.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AggregateOp selects how the values of a destination field are folded
+// across the sources passed to Aggregate.
+type AggregateOp string
+
+const (
+	// AggregateFirst keeps the value mapped from the first source.
+	AggregateFirst AggregateOp = "first"
+
+	// AggregateLast keeps the value mapped from the last source.
+	AggregateLast AggregateOp = "last"
+
+	// AggregateSum adds up numeric values.
+	AggregateSum AggregateOp = "sum"
+
+	// AggregateAvg averages numeric values. Integer fields truncate.
+	AggregateAvg AggregateOp = "avg"
+
+	// AggregateMin keeps the smallest numeric value.
+	AggregateMin AggregateOp = "min"
+
+	// AggregateMax keeps the largest numeric value.
+	AggregateMax AggregateOp = "max"
+
+	// AggregateCount counts the non-nil values into an integer field.
+	AggregateCount AggregateOp = "count"
+)
+
+// AggregateRules assigns aggregate operations to destination field names.
+// Rules take precedence over aggregate tag options.
+type AggregateRules map[string]AggregateOp
+
+// Aggregate folds srcs into the destination struct pointed to by dst.
+// Each source is mapped with the regular matching rules, then each
+// destination field is folded with the operation from rules or its tag
+// option (e.g. `mapper:",sum"`); fields without one keep the first value.
+//
+// Nil pointer values are skipped, as in SQL aggregates: they neither add
+// to a sum nor count towards an average, and a pointer field whose values
+// are all nil is left untouched. Destination fields are also left untouched
+// when srcs is empty.
+//
+// Example:
+//
+//	type Totals struct {
+//	    Region  string
+//	    Revenue float64 `mapper:",sum"`
+//	    Orders  int     `mapper:",count"`
+//	    Largest *int64  `mapper:",max"`
+//	}
+//	err := mapper.Aggregate(&totals, rows, nil)
+func Aggregate[T any](dst interface{}, srcs []T, rules AggregateRules, opts ...Option) error {
+	dstVal := reflect.ValueOf(dst)
+	if !dstVal.IsValid() || dstVal.Kind() != reflect.Ptr || dstVal.IsNil() || dstVal.Elem().Kind() != reflect.Struct {
+		return ErrInvalidDestination
+	}
+	dstVal = dstVal.Elem()
+
+	m := NewMapper(opts...)
+	folds, err := aggregateFolds(&context{config: m.config}, dstVal.Type(), rules)
+	if err != nil {
+		return err
+	}
+
+	for _, src := range srcs {
+		mapped := reflect.New(dstVal.Type())
+		if err := m.Map(mapped.Interface(), src); err != nil {
+			return err
+		}
+		for i := range folds {
+			folds[i].add(mapped.Elem().Field(folds[i].index))
+		}
+	}
+
+	for i := range folds {
+		folds[i].store(dstVal.Field(folds[i].index))
+	}
+	return nil
+}
+
+// aggregateFold accumulates the values of one destination field.
+type aggregateFold struct {
+	index int
+	op    AggregateOp
+	count int
+	sum   float64
+	value reflect.Value
+}
+
+// aggregateFolds resolves the aggregate operation of every settable field
+// of the destination struct type.
+func aggregateFolds(ctx *context, t reflect.Type, rules AggregateRules) ([]aggregateFold, error) {
+	var folds []aggregateFold
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		op, ok := rules[field.Name]
+		if !ok {
+			op = AggregateFirst
+			for _, candidate := range []AggregateOp{AggregateLast, AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateCount} {
+				if ctx.hasTagOption(field, string(candidate)) {
+					op = candidate
+					break
+				}
+			}
+		}
+
+		base := field.Type
+		if base.Kind() == reflect.Ptr {
+			base = base.Elem()
+		}
+		switch op {
+		case AggregateFirst, AggregateLast:
+		case AggregateSum, AggregateAvg, AggregateMin, AggregateMax:
+			if !isNumericKind(base.Kind()) {
+				return nil, fmt.Errorf("%w: cannot %s non-numeric field %s", ErrTypeMismatch, op, field.Name)
+			}
+		case AggregateCount:
+			if !isIntegerKind(base.Kind()) {
+				return nil, fmt.Errorf("%w: cannot count into non-integer field %s", ErrTypeMismatch, field.Name)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown aggregate operation %q for field %s", ErrUnsupportedType, op, field.Name)
+		}
+
+		folds = append(folds, aggregateFold{index: i, op: op})
+	}
+	return folds, nil
+}
+
+// add folds one mapped field value into the accumulator.
+func (f *aggregateFold) add(v reflect.Value) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	f.count++
+
+	switch f.op {
+	case AggregateCount:
+		return
+	case AggregateFirst:
+		if f.count == 1 {
+			f.value = v
+		}
+		return
+	case AggregateLast:
+		f.value = v
+		return
+	case AggregateAvg:
+		f.sum += numericFloat(v)
+		return
+	}
+
+	if f.count == 1 {
+		f.value = reflect.New(v.Type()).Elem()
+		f.value.Set(v)
+		return
+	}
+
+	switch f.op {
+	case AggregateSum:
+		switch {
+		case v.CanInt():
+			f.value.SetInt(f.value.Int() + v.Int())
+		case v.CanUint():
+			f.value.SetUint(f.value.Uint() + v.Uint())
+		default:
+			f.value.SetFloat(f.value.Float() + v.Float())
+		}
+	case AggregateMin:
+		if numericLess(v, f.value) {
+			f.value.Set(v)
+		}
+	case AggregateMax:
+		if numericLess(f.value, v) {
+			f.value.Set(v)
+		}
+	}
+}
+
+// store writes the folded result into the destination field, allocating
+// pointer fields. Fields that received no values are left untouched.
+func (f *aggregateFold) store(dst reflect.Value) {
+	if f.count == 0 {
+		return
+	}
+
+	base := dst.Type()
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	result := reflect.New(base).Elem()
+
+	switch f.op {
+	case AggregateCount:
+		if result.CanInt() {
+			result.SetInt(int64(f.count))
+		} else {
+			result.SetUint(uint64(f.count))
+		}
+	case AggregateAvg:
+		avg := f.sum / float64(f.count)
+		switch {
+		case result.CanInt():
+			result.SetInt(int64(avg))
+		case result.CanUint():
+			result.SetUint(uint64(avg))
+		default:
+			result.SetFloat(avg)
+		}
+	default:
+		result.Set(f.value)
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		ptr := reflect.New(base)
+		ptr.Elem().Set(result)
+		result = ptr
+	}
+	dst.Set(result)
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uintptr
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return isIntegerKind(k) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// numericFloat returns a numeric value as float64.
+func numericFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+// numericLess reports whether a < b for two numeric values of one type.
+func numericLess(a, b reflect.Value) bool {
+	switch {
+	case a.CanInt():
+		return a.Int() < b.Int()
+	case a.CanUint():
+		return a.Uint() < b.Uint()
+	default:
+		return a.Float() < b.Float()
+	}
+}
@@ -0,0 +1,166 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements atomic access to fields tagged `mapper:",atomic"`.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// AtomicTagOption marks a struct field that must be read (as a source) or
+// written (as a destination) atomically, e.g. `mapper:",atomic"`.
+//
+// Supported field types are the sync/atomic types (atomic.Int64,
+// atomic.Bool, atomic.Value, atomic.Pointer[T], ...), accessed through their
+// Load and Store methods, and int32, int64, uint32, uint64, uintptr and
+// unsafe.Pointer fields (including named types based on them), accessed
+// through the sync/atomic functions. unsafe.Pointer fields are not supported
+// in builds with the gomap_safe tag or with WithNoUnsafe.
+const AtomicTagOption = "atomic"
+
+var (
+	int32PtrType   = reflect.TypeOf((*int32)(nil))
+	int64PtrType   = reflect.TypeOf((*int64)(nil))
+	uint32PtrType  = reflect.TypeOf((*uint32)(nil))
+	uint64PtrType  = reflect.TypeOf((*uint64)(nil))
+	uintptrPtrType = reflect.TypeOf((*uintptr)(nil))
+)
+
+// addressable returns an addressable value holding v. Values that are not
+// addressable (e.g. fields of a struct passed by value) are already private
+// copies, so copying them again is race-free.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	return cp
+}
+
+// atomicLoad reads v atomically and returns the loaded value.
+func atomicLoad(v reflect.Value) (reflect.Value, error) {
+	v = addressable(v)
+	ptr := v.Addr()
+
+	if load := ptr.MethodByName("Load"); load.IsValid() && load.Type().NumIn() == 0 && load.Type().NumOut() == 1 {
+		return load.Call(nil)[0], nil
+	}
+
+	var loaded interface{}
+	switch v.Kind() {
+	case reflect.Int32:
+		loaded = atomic.LoadInt32(ptr.Convert(int32PtrType).Interface().(*int32))
+	case reflect.Int64:
+		loaded = atomic.LoadInt64(ptr.Convert(int64PtrType).Interface().(*int64))
+	case reflect.Uint32:
+		loaded = atomic.LoadUint32(ptr.Convert(uint32PtrType).Interface().(*uint32))
+	case reflect.Uint64:
+		loaded = atomic.LoadUint64(ptr.Convert(uint64PtrType).Interface().(*uint64))
+	case reflect.Uintptr:
+		loaded = atomic.LoadUintptr(ptr.Convert(uintptrPtrType).Interface().(*uintptr))
+	case reflect.UnsafePointer:
+		return atomicLoadPointer(v)
+	default:
+		return reflect.Value{}, fmt.Errorf("%w: %s cannot be loaded atomically", ErrUnsupportedType, v.Type())
+	}
+	return reflect.ValueOf(loaded).Convert(v.Type()), nil
+}
+
+// atomicValueType returns the type of the values stored in an atomic field:
+// the result type of its Load method, or the field type itself.
+func atomicValueType(t reflect.Type) reflect.Type {
+	if load, ok := reflect.PointerTo(t).MethodByName("Load"); ok && load.Type.NumIn() == 1 && load.Type.NumOut() == 1 {
+		return load.Type.Out(0)
+	}
+	return t
+}
+
+// atomicStore writes value into the addressable field dst atomically.
+func atomicStore(dst, value reflect.Value) error {
+	if !dst.CanAddr() {
+		return nil
+	}
+	ptr := dst.Addr()
+
+	if store := ptr.MethodByName("Store"); store.IsValid() && store.Type().NumIn() == 1 {
+		if err := checkAtomicStore(ptr, value); err != nil || (value.Kind() == reflect.Interface && value.IsNil()) {
+			// An unset atomic.Value loads as nil, which cannot be stored
+			return err
+		}
+		store.Call([]reflect.Value{value})
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Int32:
+		atomic.StoreInt32(ptr.Convert(int32PtrType).Interface().(*int32), int32(value.Int()))
+	case reflect.Int64:
+		atomic.StoreInt64(ptr.Convert(int64PtrType).Interface().(*int64), value.Int())
+	case reflect.Uint32:
+		atomic.StoreUint32(ptr.Convert(uint32PtrType).Interface().(*uint32), uint32(value.Uint()))
+	case reflect.Uint64:
+		atomic.StoreUint64(ptr.Convert(uint64PtrType).Interface().(*uint64), value.Uint())
+	case reflect.Uintptr:
+		atomic.StoreUintptr(ptr.Convert(uintptrPtrType).Interface().(*uintptr), uintptr(value.Uint()))
+	case reflect.UnsafePointer:
+		return atomicStorePointer(dst, value)
+	default:
+		return fmt.Errorf("%w: %s cannot be stored atomically", ErrUnsupportedType, dst.Type())
+	}
+	return nil
+}
+
+// checkAtomicStore reports an error when storing the interface value into
+// the field ptr points to would panic, as atomic.Value does for values of a
+// different concrete type than the one it already holds.
+func checkAtomicStore(ptr, value reflect.Value) error {
+	if value.Kind() != reflect.Interface || value.IsNil() {
+		return nil
+	}
+	load := ptr.MethodByName("Load")
+	if !load.IsValid() || load.Type().NumIn() != 0 || load.Type().NumOut() != 1 {
+		return nil
+	}
+	current := load.Call(nil)[0]
+	if current.Kind() != reflect.Interface || current.IsNil() || current.Elem().Type() == value.Elem().Type() {
+		return nil
+	}
+	return fmt.Errorf("%w: cannot store %s into %s holding %s",
+		ErrTypeMismatch, value.Elem().Type(), ptr.Elem().Type(), current.Elem().Type())
+}
+
+// loadAtomicField reads the atomic source field v, honoring NoUnsafe.
+func (ctx *context) loadAtomicField(v reflect.Value) (reflect.Value, error) {
+	if v.Kind() == reflect.UnsafePointer {
+		if err := ctx.config.requireUnsafe("atomic unsafe.Pointer fields"); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return atomicLoad(v)
+}
+
+// storeAtomicField maps srcValue into the atomic destination field dstValue
+// through a temporary of the field's value type, then stores it atomically.
+func (ctx *context) storeAtomicField(dstValue, srcValue reflect.Value) error {
+	if dstValue.Kind() == reflect.UnsafePointer {
+		if err := ctx.config.requireUnsafe("atomic unsafe.Pointer fields"); err != nil {
+			return err
+		}
+	}
+	tmp := reflect.New(atomicValueType(dstValue.Type())).Elem()
+	if err := ctx.mapValue(tmp, srcValue); err != nil {
+		return err
+	}
+	return atomicStore(dstValue, tmp)
+}
+
+// requireUnsafe returns an ErrUnsafeDisabled error naming feature when the
+// mapper may not use package unsafe.
+func (c *Config) requireUnsafe(feature string) error {
+	if !UnsafeEnabled || c.NoUnsafe {
+		return fmt.Errorf("%w: %s", ErrUnsafeDisabled, feature)
+	}
+	return nil
+}
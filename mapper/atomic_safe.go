@@ -0,0 +1,24 @@
+//go:build gomap_safe
+
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file stubs the unsafe-based atomic access of gomap_safe builds.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// UnsafeEnabled reports whether this build of the mapper may use package
+// unsafe. It is false in builds with the gomap_safe tag.
+const UnsafeEnabled = false
+
+// atomicLoadPointer fails: unsafe.Pointer fields need package unsafe.
+func atomicLoadPointer(v reflect.Value) (reflect.Value, error) {
+	return reflect.Value{}, fmt.Errorf("%w: %s cannot be loaded atomically in gomap_safe builds", ErrUnsafeDisabled, v.Type())
+}
+
+// atomicStorePointer fails: unsafe.Pointer fields need package unsafe.
+func atomicStorePointer(dst, value reflect.Value) error {
+	return fmt.Errorf("%w: %s cannot be stored atomically in gomap_safe builds", ErrUnsafeDisabled, dst.Type())
+}
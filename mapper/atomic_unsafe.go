@@ -0,0 +1,28 @@
+//go:build !gomap_safe
+
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements atomic access to unsafe.Pointer fields.
+package mapper
+
+import (
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// UnsafeEnabled reports whether this build of the mapper may use package
+// unsafe. It is false in builds with the gomap_safe tag.
+const UnsafeEnabled = true
+
+// atomicLoadPointer atomically loads the addressable unsafe.Pointer field v.
+func atomicLoadPointer(v reflect.Value) (reflect.Value, error) {
+	loaded := atomic.LoadPointer((*unsafe.Pointer)(v.Addr().UnsafePointer()))
+	return reflect.ValueOf(loaded).Convert(v.Type()), nil
+}
+
+// atomicStorePointer atomically stores value into the addressable
+// unsafe.Pointer field dst.
+func atomicStorePointer(dst, value reflect.Value) error {
+	atomic.StorePointer((*unsafe.Pointer)(dst.Addr().UnsafePointer()), value.UnsafePointer())
+	return nil
+}
@@ -0,0 +1,57 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the sampled audit of implicit type conversions.
+package mapper
+
+import (
+	"math/rand/v2"
+	"reflect"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// ConversionEvent describes an implicit conversion performed by the mapper
+// between two different types: basic conversions (e.g. int64 → int32), and
+// the string, unit, money, civil and time layout conversions enabled in the
+// configuration.
+type ConversionEvent struct {
+	// SrcType and DstType are the types involved in the conversion.
+	SrcType reflect.Type
+	DstType reflect.Type
+
+	// Narrowing reports whether the conversion can lose information,
+	// such as integer truncation, float → int, int → string (rune)
+	// conversions, rounding of decimals, or dropping the time of day.
+	Narrowing bool
+}
+
+// ConversionHookFunc receives sampled conversion events when conversion
+// auditing is enabled. It may be called concurrently from multiple mappings
+// and must therefore be safe for concurrent use.
+type ConversionHookFunc func(event ConversionEvent)
+
+// auditConversion reports an implicit conversion to the configured hook,
+// honoring the configured sample rate. Callers tell whether the conversion
+// can lose information; basic conversions use auditBasicConversion.
+func (ctx *context) auditConversion(srcType, dstType reflect.Type, narrowing bool) {
+	hook := ctx.config.ConversionHook
+	if hook == nil {
+		return
+	}
+
+	rate := ctx.config.ConversionSampleRate
+	if rate <= 0 || (rate < 1 && rand.Float64() >= rate) {
+		return
+	}
+
+	hook(ConversionEvent{
+		SrcType:   srcType,
+		DstType:   dstType,
+		Narrowing: narrowing,
+	})
+}
+
+// auditBasicConversion reports a reflect.Value.Convert conversion between
+// basic types.
+func (ctx *context) auditBasicConversion(srcType, dstType reflect.Type) {
+	ctx.auditConversion(srcType, dstType, reflectutil.IsNarrowingConversion(srcType, dstType))
+}
@@ -0,0 +1,102 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements bidirectional mappers with derived reverse mappings.
+package mapper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Bidirectional maps values of type A into B and back. The B→A direction
+// reverses the field pairs resolved for A→B, so renamed fields (through
+// tags, field mappings or a name mapper) only need to be configured once.
+//
+// A Bidirectional is safe for concurrent use.
+type Bidirectional[A, B any] struct {
+	AToB *TypedMapper[A, B]
+	BToA *TypedMapper[B, A]
+}
+
+// NewBidirectional creates a Bidirectional mapper. The options configure
+// both directions; options wrapped in WithAToB or WithBToA only apply to
+// one of them, e.g. to register the converter of each direction.
+//
+// The reverse direction maps each destination field of A→B back onto the
+// source field it came from. Since those pairs already encode the names
+// chosen by tags, the reverse direction does not consult TagName or JSON
+// tags of its own, unless they are set again through WithBToA.
+//
+// Example:
+//
+//	users := mapper.NewBidirectional[UserEntity, UserDTO](
+//	    mapper.WithTagName("dto"),
+//	    mapper.WithAToB(mapper.WithCustomConverter(timeType, formatTime)),
+//	    mapper.WithBToA(mapper.WithCustomConverter(stringType, parseTime)),
+//	)
+//	dto, err := users.AToB.Map(entity)
+//	entity, err = users.BToA.Map(dto)
+func NewBidirectional[A, B any](opts ...Option) *Bidirectional[A, B] {
+	base := &Config{}
+	for _, opt := range opts {
+		opt(base)
+	}
+
+	forward := New[A, B](append(opts[:len(opts):len(opts)], base.aToB...)...)
+
+	reverse := append(opts[:len(opts):len(opts)], func(c *Config) {
+		c.TagName = ""
+		c.UseJSONTag = false
+		c.UseYAMLTag = false
+		c.UseBSONTag = false
+		c.SourcePrefixes, c.DestPrefixes = c.DestPrefixes, c.SourcePrefixes
+		c.SourceSuffixes, c.DestSuffixes = c.DestSuffixes, c.SourceSuffixes
+		c.FieldNameMapper = nil
+		c.FieldMappings = reverseFieldMappings(forward.mapper, reflect.TypeOf((*A)(nil)).Elem(), reflect.TypeOf((*B)(nil)).Elem())
+	})
+
+	return &Bidirectional[A, B]{
+		AToB: forward,
+		BToA: New[B, A](append(reverse, base.bToA...)...),
+	}
+}
+
+// WithAToB applies options only to the A→B direction of a Bidirectional
+// mapper. Other mappers ignore it.
+func WithAToB(opts ...Option) Option {
+	return func(c *Config) {
+		c.aToB = append(c.aToB, opts...)
+	}
+}
+
+// WithBToA applies options only to the B→A direction of a Bidirectional
+// mapper. Other mappers ignore it.
+func WithBToA(opts ...Option) Option {
+	return func(c *Config) {
+		c.bToA = append(c.bToA, opts...)
+	}
+}
+
+// reverseFieldMappings inverts the field pairs of the plan from a onto b,
+// returning field mappings from b's field names to a's.
+func reverseFieldMappings(m *Mapper, a, b reflect.Type) map[string]string {
+	plan, err := m.Plan(a, b)
+	if err != nil {
+		return nil
+	}
+
+	mappings := make(map[string]string)
+	for _, field := range plan.Fields {
+		switch field.Converter {
+		case "split", "combine", "computed", "overflow":
+			continue
+		}
+		// Field mappings apply at every level, so nested pairs are keyed
+		// by their leaf names
+		src, dst := strings.Split(field.Source, "."), strings.Split(field.Destination, ".")
+		if len(src) != len(dst) {
+			continue
+		}
+		mappings[dst[len(dst)-1]] = src[len(src)-1]
+	}
+	return mappings
+}
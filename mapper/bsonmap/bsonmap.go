@@ -0,0 +1,112 @@
+// Package bsonmap maps between MongoDB documents and plain Go structs. It
+// registers converters for the driver's ObjectID and DateTime types, so
+// repository layers can map documents onto domain types, which hold IDs as
+// strings and times as time.Time.
+//
+// bsonmap is a separate Go module: importing the core mapper never pulls
+// go.mongodb.org/mongo-driver into a build.
+//
+// Example:
+//
+//	m := mapper.NewMapper(
+//	    mapper.WithBSONTag(true),
+//	    bsonmap.WithMongoTypes(),
+//	)
+//	var user User
+//	err := m.Map(&user, doc)
+package bsonmap
+
+import (
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/fbarikzehi/gomap/mapper"
+)
+
+var (
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+	dateTimeType = reflect.TypeOf(primitive.DateTime(0))
+	stringType   = reflect.TypeOf("")
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// WithMongoTypes registers converters between the MongoDB driver's types
+// and their Go counterparts:
+//
+//   - primitive.ObjectID ↔ string, as the hex encoding; the nil ObjectID
+//     and the empty string convert to each other
+//   - primitive.DateTime ↔ time.Time
+//
+// Pointers to either side convert as well, a nil pointer being nil.
+//
+// The converters are pair converters, so they only apply between these
+// types: other string and time.Time fields keep their regular mapping and
+// any converters registered for them. Strings that are not valid ObjectIDs
+// fail the field.
+//
+// Example:
+//
+//	var user User // ID string, CreatedAt time.Time
+//	err := mapper.Copy(&user, doc, mapper.WithBSONTag(true), bsonmap.WithMongoTypes())
+func WithMongoTypes() mapper.Option {
+	opts := []mapper.Option{
+		withPair(objectIDType, stringType, fromObjectID),
+		withPair(stringType, objectIDType, toObjectID),
+		withPair(dateTimeType, timeType, fromDateTime),
+		withPair(timeType, dateTimeType, toDateTime),
+	}
+
+	return func(c *mapper.Config) {
+		for _, opt := range opts {
+			opt(c)
+		}
+	}
+}
+
+// withPair registers converter from src to dst, and to pointers to dst,
+// which receive the converted value through a new pointer.
+func withPair(src, dst reflect.Type, converter mapper.ConverterFunc) mapper.Option {
+	addressed := func(v reflect.Value) (reflect.Value, error) {
+		converted, err := converter(v)
+		if err != nil {
+			return v, err
+		}
+		p := reflect.New(converted.Type())
+		p.Elem().Set(converted)
+		return p, nil
+	}
+
+	return func(c *mapper.Config) {
+		mapper.WithPairConverter(src, dst, converter)(c)
+		mapper.WithPairConverter(src, reflect.PointerTo(dst), addressed)(c)
+	}
+}
+
+func fromObjectID(v reflect.Value) (reflect.Value, error) {
+	id := v.Interface().(primitive.ObjectID)
+	if id.IsZero() {
+		return reflect.ValueOf(""), nil
+	}
+	return reflect.ValueOf(id.Hex()), nil
+}
+
+func toObjectID(v reflect.Value) (reflect.Value, error) {
+	if v.String() == "" {
+		return reflect.ValueOf(primitive.NilObjectID), nil
+	}
+	id, err := primitive.ObjectIDFromHex(v.String())
+	if err != nil {
+		return v, err
+	}
+	return reflect.ValueOf(id), nil
+}
+
+func fromDateTime(v reflect.Value) (reflect.Value, error) {
+	return reflect.ValueOf(v.Interface().(primitive.DateTime).Time().UTC()), nil
+}
+
+func toDateTime(v reflect.Value) (reflect.Value, error) {
+	return reflect.ValueOf(primitive.NewDateTimeFromTime(v.Interface().(time.Time))), nil
+}
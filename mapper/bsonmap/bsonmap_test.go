@@ -0,0 +1,99 @@
+package bsonmap_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/fbarikzehi/gomap/mapper"
+	"github.com/fbarikzehi/gomap/mapper/bsonmap"
+)
+
+type userDocument struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty"`
+	Name      string              `bson:"display_name"`
+	ManagerID *primitive.ObjectID `bson:"manager_id,omitempty"`
+	CreatedAt primitive.DateTime  `bson:"created_at"`
+	DeletedAt *primitive.DateTime `bson:"deleted_at,omitempty"`
+}
+
+type user struct {
+	ID          string     `bson:"_id"`
+	DisplayName string     `bson:"display_name"`
+	ManagerID   *string    `bson:"manager_id"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	DeletedAt   *time.Time `bson:"deleted_at"`
+}
+
+func TestMongoTypes(t *testing.T) {
+	id := primitive.NewObjectID()
+	manager := primitive.NewObjectID()
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	deleted := primitive.NewDateTimeFromTime(created.Add(time.Hour))
+	doc := userDocument{
+		ID:        id,
+		Name:      "Ada",
+		ManagerID: &manager,
+		CreatedAt: primitive.NewDateTimeFromTime(created),
+		DeletedAt: &deleted,
+	}
+
+	m := mapper.NewMapper(mapper.WithBSONTag(true), bsonmap.WithMongoTypes())
+
+	var u user
+	require.NoError(t, m.Map(&u, doc))
+	assert.Equal(t, id.Hex(), u.ID)
+	assert.Equal(t, "Ada", u.DisplayName)
+	require.NotNil(t, u.ManagerID)
+	assert.Equal(t, manager.Hex(), *u.ManagerID)
+	assert.True(t, created.Equal(u.CreatedAt))
+	require.NotNil(t, u.DeletedAt)
+	assert.True(t, created.Add(time.Hour).Equal(*u.DeletedAt))
+
+	var back userDocument
+	require.NoError(t, m.Map(&back, u))
+	assert.Equal(t, doc, back)
+
+	// The nil ObjectID and the empty string convert to each other
+	u = user{}
+	require.NoError(t, m.Map(&u, userDocument{Name: "Grace"}))
+	assert.Empty(t, u.ID)
+	assert.Nil(t, u.ManagerID)
+	assert.Nil(t, u.DeletedAt)
+	back = userDocument{}
+	require.NoError(t, m.Map(&back, u))
+	assert.True(t, back.ID.IsZero())
+
+	// Other string fields map as usual
+	assert.Equal(t, "Grace", back.Name)
+
+	err := m.Map(&back, user{ID: "not-an-object-id"})
+	assert.Error(t, err)
+}
+
+func TestMongoTypesKeepOtherConverters(t *testing.T) {
+	trim := func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(strings.TrimSpace(v.String())), nil
+	}
+	m := mapper.NewMapper(
+		mapper.WithBSONTag(true),
+		mapper.WithCustomConverter(reflect.TypeOf(""), trim),
+		bsonmap.WithMongoTypes(),
+	)
+
+	id := primitive.NewObjectID()
+	var u user
+	require.NoError(t, m.Map(&u, userDocument{ID: id, Name: "  Ada  "}))
+	assert.Equal(t, id.Hex(), u.ID)
+	assert.Equal(t, "Ada", u.DisplayName)
+
+	var back userDocument
+	require.NoError(t, m.Map(&back, user{ID: id.Hex(), DisplayName: "  Grace  "}))
+	assert.Equal(t, id, back.ID)
+	assert.Equal(t, "Grace", back.Name)
+}
@@ -0,0 +1,17 @@
+module github.com/fbarikzehi/gomap/mapper/bsonmap
+
+go 1.24.9
+
+require (
+	github.com/fbarikzehi/gomap v0.0.0
+	github.com/stretchr/testify v1.11.1
+	go.mongodb.org/mongo-driver v1.17.6
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/fbarikzehi/gomap => ../..
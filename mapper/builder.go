@@ -0,0 +1,104 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the fluent builder for typed mappers with computed fields.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ProjectionBuilder declares a TypedMapper whose destination fields are
+// mapped automatically, except for the computed fields declared with
+// ForField. It is created with Builder.
+type ProjectionBuilder[S, D any] struct {
+	opts []Option
+	err  error
+}
+
+// Builder starts a fluent declaration of a TypedMapper from S to D,
+// configured with the provided options.
+//
+// Example:
+//
+//	users, err := mapper.Builder[User, UserDTO]().
+//	    ForField("DisplayName", func(u User) (string, error) {
+//	        return u.First + " " + u.Last, nil
+//	    }).
+//	    Build()
+func Builder[S, D any](opts ...Option) *ProjectionBuilder[S, D] {
+	return &ProjectionBuilder[S, D]{opts: opts}
+}
+
+// ForField computes the destination field target, which may be a dotted
+// path, from the whole source value. fn must be a func taking an S and
+// returning a value, optionally followed by an error. The returned value is
+// mapped onto the field with the regular rules, after the fields mapped by
+// name. An invalid fn is reported by Build.
+func (b *ProjectionBuilder[S, D]) ForField(target string, fn interface{}) *ProjectionBuilder[S, D] {
+	if b.err != nil {
+		return b
+	}
+	field, err := computedFieldOf(reflect.TypeOf((*S)(nil)).Elem(), target, fn)
+	if err != nil {
+		b.err = fmt.Errorf("mapper: ForField %s: %w", target, err)
+		return b
+	}
+	b.opts = append(b.opts, func(c *Config) {
+		c.computed = append(c.computed, field)
+	})
+	return b
+}
+
+// With adds options to the mapper being built.
+func (b *ProjectionBuilder[S, D]) With(opts ...Option) *ProjectionBuilder[S, D] {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Build returns the declared TypedMapper, or the first error of an invalid
+// declaration.
+func (b *ProjectionBuilder[S, D]) Build() (*TypedMapper[S, D], error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return New[S, D](b.opts...), nil
+}
+
+// computedFieldOf validates fn as a compute function for source type
+// srcType. Pointer source types are computed from their struct, so fn
+// receives a pointer to a copy when the mapped source is not addressable.
+func computedFieldOf(srcType reflect.Type, target string, fn interface{}) (computedField, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return computedField{}, fmt.Errorf("%w: want func(%s) (T[, error]), got %T", ErrTypeMismatch, srcType, fn)
+	}
+	ft := fv.Type()
+	if ft.NumIn() != 1 || ft.In(0) != srcType ||
+		ft.NumOut() < 1 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return computedField{}, fmt.Errorf("%w: want func(%s) (T[, error]), got %T", ErrTypeMismatch, srcType, fn)
+	}
+
+	structType, byPointer := srcType, srcType.Kind() == reflect.Ptr
+	if byPointer {
+		structType = srcType.Elem()
+	}
+	return computedField{
+		srcType: structType,
+		target:  target,
+		compute: func(src reflect.Value) (interface{}, error) {
+			if byPointer {
+				if !src.CanAddr() {
+					copied := reflect.New(structType).Elem()
+					copied.Set(src)
+					src = copied
+				}
+				src = src.Addr()
+			}
+			out := fv.Call([]reflect.Value{src})
+			if len(out) == 2 && !out[1].IsNil() {
+				return nil, out[1].Interface().(error)
+			}
+			return out[0].Interface(), nil
+		},
+	}, nil
+}
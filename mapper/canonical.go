@@ -0,0 +1,150 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements stable canonical encoding of values.
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Canonical returns a deterministic canonical JSON encoding of v for
+// signing and diffing. It follows the mapper's traversal, tag and
+// exclusion rules: struct fields are encoded under their Go names in
+// sorted order, map keys are sorted, and times are normalized to UTC in
+// RFC 3339 format with nanoseconds. Equal values always encode to the same
+// bytes, independent of map iteration order or time zones.
+//
+// Returns ErrCircularReference for cyclic values and ErrDoNotMap for
+// values of DoNotMapper types.
+//
+// Example:
+//
+//	var dto OrderDTO
+//	_ = mapper.Copy(&dto, order)
+//	payload, err := mapper.Canonical(dto)
+//	signature := hmac.New(sha256.New, key).Sum(payload)
+func Canonical(v interface{}, opts ...Option) ([]byte, error) {
+	return NewMapper(opts...).Canonical(v)
+}
+
+// Canonical returns the deterministic canonical JSON encoding of v under
+// the mapper's configuration. See the package-level Canonical.
+func (m *Mapper) Canonical(v interface{}) ([]byte, error) {
+	ctx := &context{
+		visited: make(map[visitKey]reflect.Value),
+		config:  m.config,
+		plans:   m.plans,
+	}
+
+	tree, err := ctx.canonicalValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	// encoding/json sorts map keys, which makes the tree deterministic
+	return json.Marshal(tree)
+}
+
+// canonicalValue converts v into a tree of JSON-encodable values with
+// struct fields and map entries as maps keyed by name.
+func (ctx *context) canonicalValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if ctx.config.MaxDepth != NoDepthLimit && ctx.depth > ctx.config.MaxDepth {
+		return nil, ErrMaxDepthExceeded
+	}
+	ctx.depth++
+	defer func() { ctx.depth-- }()
+
+	if err := checkDoNotMap(v); err != nil {
+		return nil, err
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Map {
+		key, cyclic := ctx.checkCircular(v, reflect.Value{})
+		if cyclic {
+			return nil, fmt.Errorf("%w: cannot encode %s canonically", ErrCircularReference, v.Type())
+		}
+		defer ctx.leave(key)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Ptr, reflect.Interface:
+		return ctx.canonicalValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 && v.Kind() == reflect.Slice {
+			return v.Bytes(), nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			item, err := ctx.canonicalValue(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			items[i] = item
+		}
+		return items, nil
+	case reflect.Map:
+		entries := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, ok := formatBasic(iter.Key())
+			if iter.Key().Kind() == reflect.String {
+				key, ok = iter.Key().String(), true
+			}
+			if !ok {
+				key = fmt.Sprint(iter.Key().Interface())
+			}
+			value, err := ctx.canonicalValue(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("[%s]: %w", key, err)
+			}
+			entries[key] = value
+		}
+		return entries, nil
+	case reflect.Struct:
+		if v.Type() == timeType && v.CanInterface() {
+			return v.Interface().(time.Time).UTC().Format(time.RFC3339Nano), nil
+		}
+		fields := make(map[string]interface{})
+		for _, field := range ctx.structPlan(v.Type(), v.Type()).fields {
+			fv, err := v.FieldByIndexErr(field.srcIndex)
+			if err != nil {
+				continue
+			}
+			if field.srcAtomic {
+				if fv, err = atomicLoad(fv); err != nil {
+					return nil, err
+				}
+			}
+			value, err := ctx.canonicalValue(fv)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.srcName, err)
+			}
+			fields[field.srcName] = value
+		}
+		return fields, nil
+	default:
+		return nil, fmt.Errorf("%w: cannot encode %s canonically", ErrUnsupportedType, v.Type())
+	}
+}
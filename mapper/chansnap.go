@@ -0,0 +1,26 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements snapshots of channels into slices.
+package mapper
+
+import "reflect"
+
+// mapChanSnapshot drains up to ChannelSnapshotLimit items already buffered
+// in a source channel, without blocking, and maps them into a destination
+// slice. The items are consumed from the channel. It reports whether the
+// value was handled; channels onto other destinations keep the regular
+// behavior (channels of the same type are shared, others left untouched).
+func (ctx *context) mapChanSnapshot(dst, src reflect.Value) (bool, error) {
+	if src.Kind() != reflect.Chan || src.Type().ChanDir()&reflect.RecvDir == 0 || dst.Kind() != reflect.Slice {
+		return false, nil
+	}
+
+	items := reflect.MakeSlice(reflect.SliceOf(src.Type().Elem()), 0, min(src.Len(), ctx.config.ChannelSnapshotLimit))
+	for items.Len() < ctx.config.ChannelSnapshotLimit {
+		item, ok := src.TryRecv()
+		if !ok {
+			break
+		}
+		items = reflect.Append(items, item)
+	}
+	return true, ctx.mapSlice(dst, items)
+}
@@ -0,0 +1,207 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements conversions between time.Time, civil date/time
+// component structs, and ISO 8601 date/time strings.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ISO 8601 layouts used for civil date and time-of-day strings. Times of
+// day carry seconds only when the civil struct has a Second field.
+const (
+	civilDateLayout       = "2006-01-02"
+	civilTimeLayout       = "15:04:05"
+	civilMinuteLayout     = "15:04"
+	civilDateTimeLayout   = "2006-01-02T15:04:05"
+	civilDateMinuteLayout = "2006-01-02T15:04"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Date is a civil calendar date without a time of day or location.
+// Any struct with integer Year, Month and Day fields is treated the same
+// way when civil time mapping is enabled; Date is provided for convenience.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// String returns the date in ISO 8601 format (YYYY-MM-DD).
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// TimeOfDay is a civil wall-clock time without a date or location.
+// Any struct with integer Hour and Minute (and optionally Second) fields is
+// treated the same way when civil time mapping is enabled.
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+	Second int
+}
+
+// String returns the time of day in ISO 8601 format (hh:mm:ss).
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+}
+
+// civilShape describes which civil components a struct type carries.
+type civilShape struct {
+	date    bool
+	time    bool
+	seconds bool
+}
+
+// civilShapeOf inspects a struct type for civil date/time component fields.
+func civilShapeOf(t reflect.Type) civilShape {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return civilShape{}
+	}
+	return civilShape{
+		date:    hasIntField(t, "Year") && hasIntField(t, "Month") && hasIntField(t, "Day"),
+		time:    hasIntField(t, "Hour") && hasIntField(t, "Minute"),
+		seconds: hasIntField(t, "Second"),
+	}
+}
+
+func (s civilShape) any() bool {
+	return s.date || s.time
+}
+
+// layout returns the layout formatting values of the shape.
+func (s civilShape) layout() string {
+	withSeconds, withoutSeconds := s.layouts()
+	if s.seconds {
+		return withSeconds
+	}
+	return withoutSeconds
+}
+
+// layouts returns the layouts of values of the shape with and without
+// seconds.
+func (s civilShape) layouts() (string, string) {
+	switch {
+	case s.date && s.time:
+		return civilDateTimeLayout, civilDateMinuteLayout
+	case s.date:
+		return civilDateLayout, civilDateLayout
+	default:
+		return civilTimeLayout, civilMinuteLayout
+	}
+}
+
+// parse parses an ISO string of the shape, with or without seconds.
+func (s civilShape) parse(text string) (time.Time, error) {
+	withSeconds, withoutSeconds := s.layouts()
+	t, err := time.Parse(withSeconds, text)
+	if err == nil {
+		return t, nil
+	}
+	if t, minuteErr := time.Parse(withoutSeconds, text); minuteErr == nil {
+		return t, nil
+	}
+	return t, err
+}
+
+func hasIntField(t reflect.Type, name string) bool {
+	f, ok := t.FieldByName(name)
+	if !ok || f.PkgPath != "" {
+		return false
+	}
+	switch f.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// mapCivil handles conversions between time.Time, civil component structs
+// and ISO strings. It reports whether the pair of values was handled.
+func (ctx *context) mapCivil(dst, src reflect.Value) (bool, error) {
+	srcShape := civilShapeOf(src.Type())
+	dstShape := civilShapeOf(dst.Type())
+
+	// Like time layouts, empty strings, zero times and zero civil values
+	// map to each other; t stays zero for them
+	var t time.Time
+	switch {
+	case src.Type() == timeType && dstShape.any():
+		t = src.Interface().(time.Time)
+	case srcShape.any() && (dst.Type() == timeType || dst.Kind() == reflect.String):
+		if !src.IsZero() {
+			t = civilToTime(src, srcShape)
+		}
+	case src.Kind() == reflect.String && dstShape.any():
+		if src.String() == "" {
+			break
+		}
+		parsed, err := dstShape.parse(src.String())
+		if err != nil {
+			return true, invalidValue(fmt.Errorf("%w: %v", ErrTypeMismatch, err))
+		}
+		t = parsed
+	default:
+		return false, nil
+	}
+
+	if !dst.CanSet() {
+		return true, nil
+	}
+
+	switch {
+	case dst.Type() == timeType:
+		dst.Set(reflect.ValueOf(t))
+	case t.IsZero():
+		dst.SetZero()
+	case dst.Kind() == reflect.String:
+		dst.SetString(t.Format(srcShape.layout()))
+	default:
+		timeToCivil(dst, dstShape, t)
+	}
+
+	// Civil values drop the location, sub-second precision, and the date
+	// or time of day they lack
+	ctx.auditConversion(src.Type(), dst.Type(), src.Type() == timeType)
+	return true, nil
+}
+
+// civilToTime builds a UTC time.Time from a civil component struct.
+func civilToTime(v reflect.Value, shape civilShape) time.Time {
+	year, month, day := 0, 1, 1
+	if shape.date {
+		year = int(v.FieldByName("Year").Int())
+		month = int(v.FieldByName("Month").Int())
+		day = int(v.FieldByName("Day").Int())
+	}
+
+	var hour, minute, second int
+	if shape.time {
+		hour = int(v.FieldByName("Hour").Int())
+		minute = int(v.FieldByName("Minute").Int())
+		if shape.seconds {
+			second = int(v.FieldByName("Second").Int())
+		}
+	}
+
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
+}
+
+// timeToCivil populates the civil component fields of v from t.
+func timeToCivil(v reflect.Value, shape civilShape, t time.Time) {
+	if shape.date {
+		v.FieldByName("Year").SetInt(int64(t.Year()))
+		v.FieldByName("Month").SetInt(int64(t.Month()))
+		v.FieldByName("Day").SetInt(int64(t.Day()))
+	}
+	if shape.time {
+		v.FieldByName("Hour").SetInt(int64(t.Hour()))
+		v.FieldByName("Minute").SetInt(int64(t.Minute()))
+		if shape.seconds {
+			v.FieldByName("Second").SetInt(int64(t.Second()))
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the injectable clock.
+package mapper
+
+import "time"
+
+// DefaultNow is the value of the default tag option that fills zero
+// destination time fields with the current time of the mapper's clock,
+// e.g. `mapper:",default=now"`.
+const DefaultNow = "now"
+
+// Now returns the current time of the mapper's clock (see WithClock). It
+// lets AfterMap hooks stamp values consistently with the mapping.
+//
+// Example:
+//
+//	var m *mapper.Mapper
+//	m = mapper.NewMapper(mapper.WithAfterMap(func(dst, src any) error {
+//	    dst.(*Order).UpdatedAt = m.Now()
+//	    return nil
+//	}))
+func (m *Mapper) Now() time.Time {
+	return (&context{config: m.config}).now()
+}
+
+// now returns the current time of the configured clock.
+func (ctx *context) now() time.Time {
+	if ctx.config.Clock != nil {
+		return ctx.config.Clock()
+	}
+	return time.Now()
+}
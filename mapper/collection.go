@@ -0,0 +1,166 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements typed helpers mapping whole slices, maps and channels.
+package mapper
+
+import (
+	gocontext "context"
+	"fmt"
+	"sync"
+)
+
+// parallelMinItems is the slice length below which MapSlice stays
+// sequential regardless of the configured parallelism, since spawning
+// workers costs more than mapping a few items.
+const parallelMinItems = 256
+
+// MapSlice maps every item of src into a new D, configured with the
+// provided options. The result is allocated once with len(src) items; with
+// WithParallelism, large slices are mapped by several goroutines.
+//
+// Example:
+//
+//	dtos, err := mapper.MapSlice[User, UserDTO](users)
+func MapSlice[S, D any](src []S, opts ...Option) ([]D, error) {
+	return New[S, D](opts...).MapSlice(src)
+}
+
+// MapMap maps every value of src into a new D under the same key,
+// configured with the provided options.
+//
+// Example:
+//
+//	byID, err := mapper.MapMap[int64, User, UserDTO](usersByID)
+func MapMap[K comparable, S, D any](src map[K]S, opts ...Option) (map[K]D, error) {
+	return MapMapWith[K](New[S, D](opts...), src)
+}
+
+// MapChan maps the values received from src into a new D each and sends
+// them on the returned channel, configured with the provided options. See
+// TypedMapper.MapChan.
+func MapChan[S, D any](goctx gocontext.Context, src <-chan S, opts ...Option) (<-chan D, <-chan error) {
+	return New[S, D](opts...).MapChan(goctx, src)
+}
+
+// MapSlice maps every item of src into a new D. A nil src yields a nil
+// slice. The error of the first failing item is returned, prefixed with its
+// index.
+func (t *TypedMapper[S, D]) MapSlice(src []S) ([]D, error) {
+	if src == nil {
+		return nil, nil
+	}
+	dst := make([]D, len(src))
+
+	workers := t.mapper.config.Parallelism
+	if workers < 2 || len(src) < parallelMinItems {
+		for i := range src {
+			if err := t.mapper.Map(&dst[i], src[i]); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		return dst, nil
+	}
+
+	// Each worker maps a contiguous chunk and records its first failure,
+	// so the reported error is the one with the lowest index, as in the
+	// sequential case.
+	chunk := (len(src) + workers - 1) / workers
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*chunk, min((w+1)*chunk, len(src))
+		if start >= end {
+			break
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				if err := t.mapper.Map(&dst[i], src[i]); err != nil {
+					errs[w] = fmt.Errorf("item %d: %w", i, err)
+					return
+				}
+			}
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// MapMapWith maps every value of src into a new D under the same key, using
+// t. It is the TypedMapper form of MapMap; Go methods cannot introduce the
+// key type parameter.
+func MapMapWith[K comparable, S, D any](t *TypedMapper[S, D], src map[K]S) (map[K]D, error) {
+	if src == nil {
+		return nil, nil
+	}
+	dst := make(map[K]D, len(src))
+	for key, value := range src {
+		mapped, err := t.Map(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %v: %w", key, err)
+		}
+		dst[key] = mapped
+	}
+	return dst, nil
+}
+
+// MapChan maps the values received from src into a new D each and sends
+// them, in order, on the returned channel. The channel is closed once src
+// is closed, goctx is done or an item fails; the failure (or the context
+// error) is then delivered on the error channel, which is closed as well.
+//
+// Example:
+//
+//	dtos, errs := users.MapChan(ctx, rows)
+//	for dto := range dtos {
+//	    ...
+//	}
+//	if err := <-errs; err != nil {
+//	    ...
+//	}
+func (t *TypedMapper[S, D]) MapChan(goctx gocontext.Context, src <-chan S) (<-chan D, <-chan error) {
+	out := make(chan D)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(out)
+
+		for i := 0; ; i++ {
+			var (
+				item S
+				ok   bool
+			)
+			select {
+			case item, ok = <-src:
+				if !ok {
+					return
+				}
+			case <-goctx.Done():
+				errc <- goctx.Err()
+				return
+			}
+
+			var dst D
+			if err := t.mapper.MapContext(goctx, &dst, item); err != nil {
+				errc <- fmt.Errorf("item %d: %w", i, err)
+				return
+			}
+
+			select {
+			case out <- dst:
+			case <-goctx.Done():
+				errc <- goctx.Err()
+				return
+			}
+		}
+	}()
+
+	return out, errc
+}
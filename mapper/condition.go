@@ -0,0 +1,73 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements conditional field mapping.
+package mapper
+
+import "reflect"
+
+// FieldConditionFunc reports whether a source field value is mapped.
+type FieldConditionFunc func(srcVal reflect.Value) bool
+
+// fieldCondition reports whether the field value of the source struct src
+// is mapped.
+type fieldCondition func(src, value reflect.Value) bool
+
+// WithCondition maps the source field at path only when cond returns true
+// for the source struct holding it, e.g. to copy a field only for callers
+// allowed to see it. Paths are matched like WithFieldConverter paths;
+// several conditions on one field must all pass.
+//
+// Example:
+//
+//	mapper.Copy(&dto, user,
+//	    mapper.WithCondition("Email", func(src any) bool {
+//	        return src.(User).EmailPublic
+//	    }))
+func WithCondition(path string, cond func(src any) bool) Option {
+	return withCondition(path, func(src, _ reflect.Value) bool {
+		var v interface{}
+		if src.CanInterface() {
+			v = src.Interface()
+		}
+		return cond(v)
+	})
+}
+
+// WithFieldCondition maps the source field at path only when cond returns
+// true for its value. Paths are matched like WithFieldConverter paths;
+// several conditions on one field must all pass.
+//
+// Example:
+//
+//	mapper.Copy(&dto, order,
+//	    mapper.WithFieldCondition("Order.Items.Price", func(v reflect.Value) bool {
+//	        return v.Int() >= 0
+//	    }))
+func WithFieldCondition(path string, cond FieldConditionFunc) Option {
+	return withCondition(path, func(_, value reflect.Value) bool {
+		return cond(value)
+	})
+}
+
+func withCondition(path string, cond fieldCondition) Option {
+	return func(c *Config) {
+		if c.conditions == nil {
+			c.conditions = make(map[string][]fieldCondition)
+		}
+		c.conditions[path] = append(c.conditions[path], cond)
+	}
+}
+
+// conditionsPass reports whether the conditions registered for the field
+// being mapped accept its value.
+func (ctx *context) conditionsPass(src, value reflect.Value) bool {
+	conds, ok := matchFieldPath(ctx, ctx.config.conditions)
+	if !ok {
+		return true
+	}
+	for _, cond := range conds {
+		if !cond(src, value) {
+			return false
+		}
+	}
+	return true
+}
@@ -3,7 +3,9 @@
 package mapper
 
 import (
+	gocontext "context"
 	"reflect"
+	"time"
 )
 
 // Configuration constants define mapper defaults and limits.
@@ -47,52 +49,302 @@ type Config struct {
 	IgnoreUnexported bool
 
 	// DeepCopy enables deep copying of struct fields and nested types.
+	// When false, pointers, slices and maps of identical types are shared.
 	DeepCopy bool
 
+	// PruneEmpty sets struct pointer fields whose pointees are zero back
+	// to nil once mapped.
+	PruneEmpty bool
+
+	// EmptyCollectionPolicy selects whether PruneEmpty also sets empty
+	// maps and slices to nil.
+	EmptyCollectionPolicy EmptyCollectionPolicy
+
+	// DeepCopyDepth, if positive, is the nesting depth from which values
+	// of identical source and destination types are shared by reference
+	// instead of copied.
+	DeepCopyDepth int
+
 	// ZeroFields sets destination fields to their zero value
 	// when the corresponding source field is zero.
 	ZeroFields bool
 
+	// ZeroCheckers overrides zero-value detection for specific types.
+	// Zero detection is shared by ZeroFields and every other zero-dependent
+	// behavior, so all of them agree on what "zero" means.
+	ZeroCheckers map[reflect.Type]ZeroCheckerFunc
+
 	// IgnoreNilFields skips mapping of nil pointer fields from the source.
 	IgnoreNilFields bool
 
 	// CaseSensitive enables case-sensitive field name matching.
 	CaseSensitive bool
 
+	// TypedNilPolicy selects how source interfaces holding typed nils are
+	// mapped.
+	TypedNilPolicy TypedNilPolicy
+
+	// ErrorValuePolicy selects how source error values are mapped onto
+	// string and error destinations.
+	ErrorValuePolicy ErrorValuePolicy
+
+	// SkipZeroStructs skips nested source struct fields that are entirely
+	// zero, leaving the destination field untouched.
+	SkipZeroStructs bool
+
+	// MergeMode controls whether mapped fields overwrite existing
+	// destination values. Defaults to MergeOverwrite.
+	MergeMode MergeMode
+
+	// FlattenEmbedded maps the promoted fields of embedded source structs
+	// onto destination fields when the destination has no field matching
+	// the embedded struct itself.
+	FlattenEmbedded bool
+
 	// UseJSONTag allows JSON tag parsing (e.g., `json:"name"`) for field mapping.
 	UseJSONTag bool
 
+	// UseYAMLTag allows YAML tag parsing (e.g., `yaml:"name"`) for field
+	// mapping, after JSON tags when both are enabled.
+	UseYAMLTag bool
+
+	// UseBSONTag allows BSON tag parsing (e.g., `bson:"_id"`) for field
+	// mapping, after JSON and YAML tags when they are enabled.
+	UseBSONTag bool
+
 	// SkipCircularCheck disables circular reference detection.
 	// Only disable this if you are certain your data has no circular references.
 	SkipCircularCheck bool
 
+	// CyclePolicy selects how detected circular references are handled.
+	// The zero value (CycleError) aborts with ErrCircularReference.
+	CyclePolicy CyclePolicy
+
 	// CustomConverters defines per-type converter functions used
 	// to transform values before assignment.
 	CustomConverters map[reflect.Type]ConverterFunc
 
+	// ContextConverters defines converters for specific types that receive
+	// the context.Context passed to MapContext. They take precedence over
+	// CustomConverters for the same type, which still apply to values they
+	// decline with ErrSkipConversion.
+	ContextConverters map[reflect.Type]ContextConverterFunc
+
+	// PairConverters defines converters for specific source and
+	// destination type pairs. They take precedence over ContextConverters
+	// and CustomConverters, and leave other pairs of the same types alone.
+	PairConverters map[TypePair]ConverterFunc
+
+	// Constructors build new destination values of specific types before
+	// the source is mapped onto them.
+	Constructors map[reflect.Type]ConstructorFunc
+
+	// EnumUnknownPolicy selects how values missing from an enum mapping
+	// table, and unnamed flags (see WithFlags), are mapped.
+	EnumUnknownPolicy EnumUnknownPolicy
+
+	// FieldConverters defines converters for specific source field paths
+	// ("Order.Total"), applied before per-type CustomConverters.
+	FieldConverters map[string]ConverterFunc
+
+	// NamedConverters holds the converters that fields select by name with
+	// the converter tag option, applied before FieldConverters.
+	NamedConverters map[string]ConverterFunc
+
+	// SourceLockers holds lock hooks acquired while copying source values
+	// of the registered types.
+	SourceLockers map[reflect.Type]SourceLocker
+
+	// FieldMappings maps source field names to destination field names,
+	// taking precedence over tags and FieldNameMapper.
+	FieldMappings map[string]string
+
+	// Visibility lists the roles of the caller. Fields tagged with
+	// RolesTagOption are only mapped for callers holding a listed role.
+	Visibility []string
+
+	// IgnoreFields lists field names and dotted path patterns that are
+	// never mapped. Patterns use path.Match syntax per segment.
+	IgnoreFields []string
+
+	// IgnoreTypes lists field types that are never mapped.
+	IgnoreTypes map[reflect.Type]bool
+
+	// FieldSplits feed several destination fields from one source field.
+	FieldSplits []FieldSplit
+
+	// FieldCombines feed one destination field from several source fields.
+	FieldCombines []FieldCombine
+
+	// MatchBy selects whether source or destination fields drive field
+	// matching. Defaults to MatchBySource.
+	MatchBy MatchBy
+
 	// FieldNameMapper transforms field names between source and destination structs.
 	FieldNameMapper FieldNameMapperFunc
 
+	// SourcePrefixes and SourceSuffixes are stripped from source field
+	// names, and DestPrefixes and DestSuffixes from destination field
+	// names, when fields do not match by name as they are.
+	SourcePrefixes []string
+	SourceSuffixes []string
+	DestPrefixes   []string
+	DestSuffixes   []string
+
+	// BeforeMap is called before each Map call; an error aborts the mapping.
+	BeforeMap MapHookFunc
+
+	// AfterMap is called after each successful Map call.
+	AfterMap MapHookFunc
+
+	// Validator validates the destination of each Map call once mapped.
+	Validator ValidatorFunc
+
+	// WarningHandler receives non-fatal mapping warnings.
+	WarningHandler WarningHandlerFunc
+
 	// ErrorHandler defines how errors encountered during mapping are handled.
 	// Return nil to continue mapping despite the error.
 	ErrorHandler ErrorHandlerFunc
 
+	// FieldErrorHandler is an ErrorHandler receiving the full field
+	// context. It takes precedence over ErrorHandler.
+	FieldErrorHandler FieldErrorHandlerFunc
+
+	// StringConversion converts between strings and numeric or bool values
+	// using strconv (e.g. "42" → 42, 12.5 → "12.5").
+	StringConversion bool
+
 	// TimeLayout specifies the layout string used for time.Time conversions.
+	// Setting it (or TimeZone) enables built-in conversions between
+	// time.Time, strings and integer Unix timestamps.
 	TimeLayout string
 
+	// Translator localizes the values of fields tagged with I18nTagOption.
+	Translator TranslatorFunc
+
+	// DefaultLocale is the locale passed to Translator when the context
+	// of the mapping carries none.
+	DefaultLocale string
+
+	// Clock returns the current time for time-related defaults. Nil means
+	// time.Now.
+	Clock func() time.Time
+
+	// TimeZone is the location time.Time values are converted into, and in
+	// which strings and Unix timestamps are interpreted. Defaults to UTC.
+	TimeZone *time.Location
+
+	// InternMaxLen enables interning of destination strings of up to that
+	// many bytes, so repeated values share one instance. Zero disables it.
+	InternMaxLen int
+
+	// ChannelSnapshotLimit enables draining up to that many buffered items
+	// of source channels into destination slices. Zero disables it.
+	ChannelSnapshotLimit int
+
 	// MaxSliceCapacity limits the maximum capacity allocated for slices.
-	// Protects against excessive memory allocation.
+	// Protects against excessive memory allocation. Zero disables it.
 	MaxSliceCapacity int
 
-	// AllowPrivateFields enables copying of private/unexported fields via reflection.
+	// SliceOverflowPolicy selects whether slices longer than
+	// MaxSliceCapacity fail or are truncated.
+	SliceOverflowPolicy SliceOverflowPolicy
+
+	// AllowPrivateFields enables copying of private/unexported fields via
+	// reflection and package unsafe.
 	// ⚠️ Use with caution — this breaks encapsulation.
 	AllowPrivateFields bool
+
+	// NoUnsafe guarantees the mapper never uses package unsafe. Features
+	// that need it fail with ErrUnsafeDisabled instead.
+	NoUnsafe bool
+
+	// ConversionHook receives sampled events for every implicit basic-type
+	// conversion (e.g. int64 → int32) performed during mapping.
+	ConversionHook ConversionHookFunc
+
+	// ConversionSampleRate is the fraction (0..1] of conversions reported
+	// to ConversionHook. A value of 1 reports every conversion.
+	ConversionSampleRate float64
+
+	// CivilTime enables conversions between time.Time, civil date/time
+	// component structs ({Year, Month, Day} / {Hour, Minute, Second})
+	// and ISO 8601 date/time strings.
+	CivilTime bool
+
+	// TextMarshaling converts values implementing encoding.TextMarshaler
+	// into strings, and strings into values implementing
+	// encoding.TextUnmarshaler.
+	TextMarshaling bool
+
+	// StringerFallback renders values implementing fmt.Stringer into
+	// string destinations no built-in conversion handles.
+	StringerFallback bool
+
+	// JSONBridge maps JSON blobs (json.RawMessage, []byte) onto structs
+	// and maps, and back, through encoding/json.
+	JSONBridge bool
+
+	// NullableSupport enables conversions between the database/sql
+	// nullable types (sql.NullString, sql.Null[T], ...) and plain or
+	// pointer values.
+	NullableSupport bool
+
+	// MoneySupport enables conversions between {Amount, Currency} money
+	// structs (amounts in minor units), decimal strings and float fields.
+	MoneySupport bool
+
+	// MoneyScale is the number of minor-unit digits used by money conversions.
+	MoneyScale int
+
+	// MoneyRounding selects how excess precision is rounded when converting
+	// decimal strings or floats into minor units.
+	MoneyRounding RoundingMode
+
+	// Parallelism is the number of goroutines MapSlice uses for large
+	// slices. Values below 2 map sequentially.
+	Parallelism int
+
+	// IsolateSource snapshots the source with a structural deep copy before
+	// mapping, so later stages (converters, field matching) work on a private
+	// copy of maps and slices shared by reference with the caller.
+	IsolateSource bool
+
+	// enums holds the enum lookup tables registered with WithEnumMapping,
+	// keyed by source and destination type
+	enums map[[2]reflect.Type]enumTable
+
+	// flags holds the flag names registered with WithFlags, keyed by flag
+	// type
+	flags map[reflect.Type]flagSet
+
+	// conditions holds the field conditions registered with WithCondition
+	// and WithFieldCondition, keyed by field path
+	conditions map[string][]fieldCondition
+
+	// computed holds the computed fields registered by Builder
+	computed []computedField
+
+	// aToB and bToA hold the per-direction options of a Bidirectional
+	// mapper
+	aToB []Option
+	bToA []Option
 }
 
 // ConverterFunc defines a custom conversion function that transforms
 // a reflected value into another reflected value (potentially of a different type).
+//
+// Returning ErrSkipConversion declines the value and falls through to the
+// default mapping.
 type ConverterFunc func(src reflect.Value) (reflect.Value, error)
 
+// ContextConverterFunc is a ConverterFunc that also receives the
+// context.Context of the mapping, for deadline-aware or request-scoped
+// conversions such as locale lookups. Mappings started with Map receive
+// context.Background().
+type ContextConverterFunc func(ctx gocontext.Context, src reflect.Value) (reflect.Value, error)
+
 // FieldNameMapperFunc defines a function that transforms field names during mapping,
 // allowing for case normalization, prefix/suffix handling, etc.
 type FieldNameMapperFunc func(fieldName string) string
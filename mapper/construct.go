@@ -0,0 +1,53 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements context-aware destination constructors.
+package mapper
+
+import (
+	gocontext "context"
+	"fmt"
+	"reflect"
+)
+
+// ConstructorFunc builds a new destination value, receiving the
+// context.Context of the mapping (see MapContext) so request-scoped values
+// such as the tenant or locale can be applied. It returns a value of the
+// registered type or a pointer to one.
+type ConstructorFunc func(goctx gocontext.Context) (interface{}, error)
+
+// construct initializes a zero destination of a type with a registered
+// constructor, or allocates a nil pointer to one, before the source is
+// mapped onto it. Destinations already holding a value are left alone.
+func (ctx *context) construct(dst reflect.Value) error {
+	if len(ctx.config.Constructors) == 0 || !dst.CanSet() || !dst.IsZero() {
+		return nil
+	}
+
+	target, alloc := dst, dst.Kind() == reflect.Ptr
+	if alloc {
+		target = reflect.New(dst.Type().Elem()).Elem()
+	}
+	constructor, ok := ctx.config.Constructors[target.Type()]
+	if !ok {
+		return nil
+	}
+
+	built, err := constructor(ctx.converterContext(target, reflect.Value{}))
+	if err != nil {
+		return callbackError(err)
+	}
+	v := reflect.ValueOf(built)
+	if v.Kind() == reflect.Ptr && v.Type().Elem() == target.Type() {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || !v.Type().AssignableTo(target.Type()) {
+		return fmt.Errorf("%w: constructor for %s returned %T", ErrTypeMismatch, target.Type(), built)
+	}
+	target.Set(v)
+	if alloc {
+		dst.Set(target.Addr())
+	}
+	return nil
+}
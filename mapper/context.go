@@ -3,6 +3,7 @@
 package mapper
 
 import (
+	gocontext "context"
 	"reflect"
 	"sync"
 
@@ -19,48 +20,96 @@ import (
 // mapping paths within a single operation, but it is not intended for
 // sharing between independent Copy() calls.
 type context struct {
-	// visited tracks visited pointers to detect circular references
-	visited map[uintptr]reflect.Value
+	// visited tracks the pointer-like values on the current mapping path,
+	// along with the destination each one is being mapped into
+	visited map[visitKey]reflect.Value
+
+	// mapped remembers the destinations of the pointer-like values mapped
+	// so far under CycleReuse, or is nil
+	mapped map[visitKey]reflect.Value
 
 	// depth represents the current recursion depth
 	depth int
 
+	// path holds the segments of the source path being mapped, starting
+	// with the root source type name
+	path []pathSegment
+
 	// config holds the active mapping configuration
 	config *Config
 
+	// plans caches compiled struct plans of the owning Mapper, or is nil
+	// for internal contexts that do not cache
+	plans *planCache
+
+	// interner shares the instances of short destination strings, or is
+	// nil
+	interner *interner
+
 	// errors accumulates errors encountered during mapping
 	errors []error
 
-	// mu protects concurrent access to visited and errors
+	// locked tracks the addresses of sources whose lock hooks are held,
+	// so a value reached again (e.g. through its own pointer) is not
+	// locked recursively
+	locked map[uintptr]bool
+
+	// goctx is the caller's context.Context, or nil outside MapContext
+	goctx gocontext.Context
+
+	// report collects warnings for MapWithReport, or is nil
+	report *Report
+
+	// structural marks plain structural copies (source snapshots), which
+	// skip per-struct callbacks
+	structural bool
+
+	// mu protects concurrent access to visited, mapped and errors
 	mu sync.RWMutex
 }
 
-// checkCircular detects circular references by tracking visited pointers.
-// It returns ErrCircularReference if the given value has been seen before.
+// visitKey identifies a pointer-like source value. The type is part of the
+// key because a struct and its first field share the same address.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// checkCircular detects circular references by tracking the pointer-like
+// values on the current mapping path. If v is already on the path it
+// returns its key and true. Otherwise it marks v as visited, remembering
+// dst as the destination it is being mapped into, and returns false; the
+// caller must then call leave once v has been fully mapped.
 //
 // Non-pointer values and invalid reflect.Values are ignored.
-func (ctx *context) checkCircular(v reflect.Value) error {
+func (ctx *context) checkCircular(v, dst reflect.Value) (visitKey, bool) {
 	if !v.IsValid() || !reflectutil.IsPointerLike(v.Kind()) {
-		return nil
+		return visitKey{}, false
 	}
 
-	ptr := v.Pointer()
-	if ptr == 0 {
-		return nil
+	key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+	if key.ptr == 0 {
+		return visitKey{}, false
 	}
 
-	ctx.mu.RLock()
-	_, exists := ctx.visited[ptr]
-	ctx.mu.RUnlock()
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
 
-	if exists {
-		return ErrCircularReference
+	if _, exists := ctx.visited[key]; exists {
+		return key, true
 	}
+	ctx.visited[key] = dst
+	return key, false
+}
 
+// leave removes a value marked by checkCircular from the current path.
+func (ctx *context) leave(key visitKey) {
+	if key.ptr == 0 {
+		return
+	}
 	ctx.mu.Lock()
-	ctx.visited[ptr] = v
+	delete(ctx.visited, key)
 	ctx.mu.Unlock()
-	return nil
 }
 
 // addError appends an error to the context's error list.
@@ -73,3 +122,26 @@ func (ctx *context) addError(err error) {
 	ctx.errors = append(ctx.errors, err)
 	ctx.mu.Unlock()
 }
+
+// context returns the caller's context.Context, or context.Background()
+// for mappings started without one.
+func (ctx *context) context() gocontext.Context {
+	if ctx.goctx == nil {
+		return gocontext.Background()
+	}
+	return ctx.goctx
+}
+
+// canceled returns the caller's context error once it is done. It does not
+// block and costs nothing for contexts that can never be canceled.
+func (ctx *context) canceled() error {
+	if ctx.goctx == nil || ctx.goctx.Done() == nil {
+		return nil
+	}
+	select {
+	case <-ctx.goctx.Done():
+		return ctx.goctx.Err()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,152 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file dispatches the opt-in built-in conversions.
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// mapBuiltin applies Optional unwrapping and the built-in conversions
+// enabled in the configuration.
+// It reports whether one of them handled the pair of values, in which case
+// the regular kind-based mapping is skipped.
+func (ctx *context) mapBuiltin(dst, src reflect.Value) (bool, error) {
+	// Optional wrappers are always unwrapped
+	if handled, err := ctx.mapOptional(dst, src); handled {
+		return true, err
+	}
+
+	if handled, err := ctx.mapEnum(dst, src); handled {
+		return true, err
+	}
+
+	if handled, err := ctx.mapFlags(dst, src); handled {
+		return true, err
+	}
+
+	if handled, err := ctx.mapJSONNumber(dst, src); handled {
+		return true, err
+	}
+
+	// Error values are never copied field by field
+	if handled, err := ctx.mapErrorValue(dst, src); handled {
+		return true, err
+	}
+
+	if ctx.config.ChannelSnapshotLimit > 0 {
+		if handled, err := ctx.mapChanSnapshot(dst, src); handled {
+			return true, err
+		}
+	}
+
+	if ctx.config.JSONBridge {
+		if handled, err := ctx.mapJSONBridge(dst, src); handled {
+			return true, err
+		}
+	}
+
+	if ctx.config.NullableSupport {
+		if handled, err := ctx.mapNullable(dst, src); handled {
+			return true, err
+		}
+	}
+
+	if ctx.config.CivilTime {
+		if handled, err := ctx.mapCivil(dst, src); handled {
+			return true, err
+		}
+	}
+
+	if ctx.config.MoneySupport {
+		if handled, err := ctx.mapMoney(dst, src); handled {
+			return true, err
+		}
+	}
+
+	if ctx.timeConversions() && dst.CanSet() {
+		if handled, err := ctx.mapTime(dst, src); handled {
+			return true, err
+		}
+	}
+
+	if ctx.config.TextMarshaling {
+		if handled, err := ctx.mapText(dst, src); handled {
+			return true, err
+		}
+	}
+
+	return false, nil
+}
+
+// applyConverter runs a converter on src and stores its output into dst.
+// It reports false (with a nil error) when the converter declined the value
+// with ErrSkipConversion, in which case the caller continues with the
+// default mapping.
+func (ctx *context) applyConverter(converter ConverterFunc, dst, src reflect.Value) (bool, error) {
+	converted, err := converter(src)
+	switch {
+	case errors.Is(err, ErrSkipConversion):
+		return false, nil
+	case err != nil:
+		return true, callbackError(err)
+	}
+	return ctx.assignConverted(dst, src, converted)
+}
+
+// assignConverted stores the output of a custom converter into dst. Values
+// assignable to dst are set directly; other values continue through the
+// regular conversion pipeline (convertibility, nested mapping), including
+// outputs of the source type, which may have been transformed. It returns
+// ErrTypeMismatch when the output cannot be mapped onto dst at all.
+func (ctx *context) assignConverted(dst, src, converted reflect.Value) (bool, error) {
+	if !dst.CanSet() {
+		return true, nil
+	}
+
+	if !converted.IsValid() {
+		dst.Set(reflect.Zero(dst.Type()))
+		return true, nil
+	}
+
+	if converted.Type().AssignableTo(dst.Type()) {
+		dst.Set(converted)
+		return true, nil
+	}
+
+	// Outputs of the source type map like the source would have
+	if converted.Type() != src.Type() && !kindsCompatible(converted.Type(), dst.Type()) {
+		return true, fmt.Errorf("%w: converter for %s produced %s, destination is %s",
+			ErrTypeMismatch, src.Type(), converted.Type(), dst.Type())
+	}
+
+	return true, ctx.mapKind(dst, converted)
+}
+
+// kindsCompatible reports whether a value of type src can be mapped onto
+// type dst by the regular mapping rules.
+func kindsCompatible(src, dst reflect.Type) bool {
+	for src.Kind() == reflect.Ptr {
+		src = src.Elem()
+	}
+	for dst.Kind() == reflect.Ptr {
+		dst = dst.Elem()
+	}
+
+	if src.ConvertibleTo(dst) {
+		return true
+	}
+
+	switch src.Kind() {
+	case reflect.Struct:
+		return dst.Kind() == reflect.Struct
+	case reflect.Map:
+		return dst.Kind() == reflect.Map
+	case reflect.Slice, reflect.Array:
+		return dst.Kind() == reflect.Slice || dst.Kind() == reflect.Array
+	case reflect.Interface:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the pluggable circular reference policies.
+package mapper
+
+import (
+	"reflect"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+type cycleKind int
+
+const (
+	cycleError cycleKind = iota
+	cycleSkipField
+	cycleAlias
+	cycleTruncate
+	cycleReuse
+	cycleNilOut
+)
+
+// CyclePolicy selects how the mapper handles a circular reference, i.e. a
+// pointer, map or slice that is reached again while it is still being mapped.
+//
+// The zero value is CycleError.
+type CyclePolicy struct {
+	kind  cycleKind
+	depth int
+}
+
+var (
+	// CycleError aborts the mapping with ErrCircularReference.
+	CycleError = CyclePolicy{kind: cycleError}
+
+	// CycleSkipField leaves the destination of the cyclic reference untouched.
+	CycleSkipField = CyclePolicy{kind: cycleSkipField}
+
+	// CycleAlias points the destination of the cyclic reference at the
+	// destination value already being built for it, reproducing the cycle
+	// in the destination graph. If the destination types differ, the field
+	// is skipped as with CycleSkipField.
+	CycleAlias = CyclePolicy{kind: cycleAlias}
+
+	// CycleReuse handles cycles like CycleAlias, and also re-links shared
+	// references: a pointer, map or slice reached again after it has been
+	// mapped is not copied a second time, its destination referring to the
+	// destination already built for it. The destination graph thus keeps
+	// the aliasing of the source graph.
+	CycleReuse = CyclePolicy{kind: cycleReuse}
+
+	// CycleNilOut sets the destination of the cyclic reference to its zero
+	// value, e.g. a nil pointer, discarding any value it held.
+	CycleNilOut = CyclePolicy{kind: cycleNilOut}
+)
+
+// CycleTruncateAtDepth unrolls circular references until the mapping
+// reaches the given depth, then leaves the remaining cyclic references
+// untouched. It is useful for bounded, cycle-free serializations of graphs.
+func CycleTruncateAtDepth(depth int) CyclePolicy {
+	return CyclePolicy{kind: cycleTruncate, depth: depth}
+}
+
+// handleCycle applies the configured cycle policy to a cyclic reference
+// identified by key. It reports whether mapping of the value should proceed.
+func (ctx *context) handleCycle(dst reflect.Value, key visitKey) (bool, error) {
+	policy := ctx.config.CyclePolicy
+
+	switch policy.kind {
+	case cycleSkipField:
+		return false, nil
+	case cycleAlias, cycleReuse:
+		ctx.mu.RLock()
+		target := ctx.visited[key]
+		ctx.mu.RUnlock()
+		ctx.alias(dst, target)
+		return false, nil
+	case cycleTruncate:
+		return ctx.depth < policy.depth, nil
+	case cycleNilOut:
+		if dst.CanSet() {
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+		return false, nil
+	default:
+		return false, ErrCircularReference
+	}
+}
+
+// reuse links dst to the destination already mapped from the shared
+// reference src under CycleReuse. It reports whether dst was linked; values
+// mapped onto a different destination type are mapped again.
+func (ctx *context) reuse(dst, src reflect.Value) bool {
+	if ctx.config.CyclePolicy.kind != cycleReuse || !reusable(src.Kind()) {
+		return false
+	}
+
+	ctx.mu.RLock()
+	target, ok := ctx.mapped[visitKey{ptr: src.Pointer(), typ: src.Type()}]
+	ctx.mu.RUnlock()
+	return ok && ctx.alias(dst, target)
+}
+
+// done removes a value marked by checkCircular from the current path once
+// it has been mapped into dst, remembering the resulting reference for
+// reuse under CycleReuse.
+func (ctx *context) done(key visitKey, dst reflect.Value) {
+	if key.ptr == 0 {
+		return
+	}
+	if ctx.config.CyclePolicy.kind == cycleReuse && reusable(key.typ.Kind()) {
+		if target, ok := mappedReference(dst); ok {
+			ctx.mu.Lock()
+			if ctx.mapped == nil {
+				ctx.mapped = make(map[visitKey]reflect.Value)
+			}
+			ctx.mapped[key] = target
+			ctx.mu.Unlock()
+		}
+	}
+	ctx.leave(key)
+}
+
+// mappedReference returns a copy of the pointer or map stored in dst, so it
+// stays valid when dst is a temporary slot (a map value or interface being
+// built) that is cleared or overwritten afterwards. Other destinations are
+// not remembered.
+func mappedReference(dst reflect.Value) (reflect.Value, bool) {
+	if !dst.CanInterface() {
+		return reflect.Value{}, false
+	}
+	switch dst.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface:
+		if dst.IsNil() {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(dst.Interface()), true
+	}
+	return reflect.Value{}, false
+}
+
+// reusable reports whether values of kind k are shared by CycleReuse.
+// Slices are not: slices of one backing array may differ in length.
+func reusable(k reflect.Kind) bool {
+	return k == reflect.Ptr || k == reflect.Map
+}
+
+// alias makes dst refer to the destination value target, either directly
+// or through its address. It reports whether dst was set.
+func (ctx *context) alias(dst, target reflect.Value) bool {
+	if !dst.CanSet() || !target.IsValid() {
+		return false
+	}
+
+	switch {
+	case target.Type().AssignableTo(dst.Type()) && reflectutil.IsNillable(target.Kind()):
+		dst.Set(target)
+	case target.CanAddr() && target.Addr().Type().AssignableTo(dst.Type()):
+		dst.Set(target.Addr())
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the process-wide default mapper.
+package mapper
+
+import "sync/atomic"
+
+// defaults is the configuration installed by SetDefault.
+type defaults struct {
+	mapper *Mapper
+	opts   []Option
+}
+
+var defaultMapper atomic.Pointer[defaults]
+
+func init() {
+	SetDefault()
+}
+
+// SetDefault configures the process-wide default mapper used by Copy and
+// Tee. Without per-call options they reuse it, along with its compiled
+// plans, instead of building a new Mapper on every call; per-call options
+// are applied on top of opts in a one-off mapper. The default mapper is
+// frozen, so it can be shared freely. SetDefault is safe for concurrent
+// use, but is meant to be called once at startup.
+//
+// Example:
+//
+//	func main() {
+//	    mapper.SetDefault(mapper.WithCaseSensitive(false), mapper.WithTimeZone(time.UTC))
+//	    ...
+//	}
+func SetDefault(opts ...Option) {
+	m := NewMapper(opts...)
+	m.Freeze()
+	defaultMapper.Store(&defaults{mapper: m, opts: opts})
+}
+
+// Default returns the process-wide default mapper configured with
+// SetDefault.
+func Default() *Mapper {
+	return defaultMapper.Load().mapper
+}
+
+// withDefaults returns the default mapper, or a new mapper configured with
+// the default options followed by opts.
+func withDefaults(opts []Option) *Mapper {
+	d := defaultMapper.Load()
+	if len(opts) == 0 {
+		return d.mapper
+	}
+	return NewMapper(append(append([]Option(nil), d.opts...), opts...)...)
+}
@@ -0,0 +1,112 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements declarative enum mapping tables.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// EnumUnknownPolicy selects how values missing from an enum mapping table
+// are mapped.
+//
+// The zero value is EnumUnknownError.
+type EnumUnknownPolicy int
+
+const (
+	// EnumUnknownError fails the field with ErrUnknownEnumValue.
+	EnumUnknownError EnumUnknownPolicy = iota
+
+	// EnumUnknownZero sets the destination to its zero value.
+	EnumUnknownZero
+
+	// EnumUnknownPassthrough maps the value with the regular rules, e.g.
+	// converting between numeric types.
+	EnumUnknownPassthrough
+)
+
+// enumTable is the lookup table of one enum type pair, keyed by source
+// values.
+type enumTable map[interface{}]reflect.Value
+
+// WithEnumMapping registers a lookup table mapping values of srcType onto
+// values of dstType, along with the reverse table for dstType onto srcType.
+// Keys and values are converted to srcType and dstType, so untyped
+// constants can be used; entries that do not convert are ignored, as are
+// reverse entries of values shared by several keys. Values missing from the
+// table are handled by the policy set with WithEnumUnknownPolicy.
+//
+// Example:
+//
+//	mapper.Copy(&dto, order, mapper.WithEnumMapping(
+//	    reflect.TypeOf(OrderStatus(0)), reflect.TypeOf(""),
+//	    map[any]any{0: "pending", 1: "processing", 2: "completed"},
+//	))
+func WithEnumMapping(srcType, dstType reflect.Type, table map[any]any) Option {
+	forward, reverse := make(enumTable, len(table)), make(enumTable, len(table))
+	shared := make(map[interface{}]bool)
+	for k, v := range table {
+		key, ok := convertEnumValue(k, srcType)
+		if !ok {
+			continue
+		}
+		value, ok := convertEnumValue(v, dstType)
+		if !ok {
+			continue
+		}
+		forward[key.Interface()] = value
+
+		if _, seen := reverse[value.Interface()]; seen {
+			shared[value.Interface()] = true
+		}
+		reverse[value.Interface()] = key
+	}
+	for value := range shared {
+		delete(reverse, value)
+	}
+
+	return func(c *Config) {
+		if c.enums == nil {
+			c.enums = make(map[[2]reflect.Type]enumTable)
+		}
+		c.enums[[2]reflect.Type{srcType, dstType}] = forward
+		c.enums[[2]reflect.Type{dstType, srcType}] = reverse
+	}
+}
+
+// convertEnumValue converts a table entry to t, reporting whether it is
+// usable as a key.
+func convertEnumValue(v interface{}, t reflect.Type) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !rv.Type().ConvertibleTo(t) || !t.Comparable() {
+		return reflect.Value{}, false
+	}
+	// Integers convert to strings as runes, which is never meant here
+	if t.Kind() == reflect.String && rv.Kind() != reflect.String {
+		return reflect.Value{}, false
+	}
+	return rv.Convert(t), true
+}
+
+// mapEnum maps src through the enum table registered for the type pair.
+// It reports whether the value was handled.
+func (ctx *context) mapEnum(dst, src reflect.Value) (bool, error) {
+	table, ok := ctx.config.enums[[2]reflect.Type{src.Type(), dst.Type()}]
+	if !ok || !dst.CanSet() || !src.CanInterface() {
+		return false, nil
+	}
+
+	if value, ok := table[src.Interface()]; ok {
+		dst.Set(value)
+		return true, nil
+	}
+
+	switch ctx.config.EnumUnknownPolicy {
+	case EnumUnknownZero:
+		dst.SetZero()
+		return true, nil
+	case EnumUnknownPassthrough:
+		return false, nil
+	}
+	return true, fmt.Errorf("%w: %v has no %s mapping", ErrUnknownEnumValue, src.Interface(), dst.Type())
+}
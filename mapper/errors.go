@@ -8,6 +8,7 @@ package mapper
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Common sentinel errors returned by Mapper operations.
@@ -39,6 +40,50 @@ var (
 	// ErrCircularReference indicates that a circular reference
 	// was detected in the source object graph during deep copy.
 	ErrCircularReference = errors.New("mapper: circular reference detected")
+
+	// ErrDoNotMap indicates that the source contains a value of a type
+	// marked as not copyable, via the DoNotMapper interface or a
+	// `mapper:"-"` blank marker field.
+	ErrDoNotMap = errors.New("mapper: type must not be mapped")
+
+	// ErrTypedNil indicates that a source interface holds a typed nil
+	// and the TypedNilPolicy is TypedNilError.
+	ErrTypedNil = errors.New("mapper: interface holds a typed nil")
+
+	// ErrUnsafeDisabled indicates that a requested feature needs package
+	// unsafe, which the mapper may not use: it was built with the
+	// gomap_safe tag or configured with WithNoUnsafe.
+	ErrUnsafeDisabled = errors.New("mapper: feature requires package unsafe")
+
+	// ErrUnknownEnumValue indicates that a value is missing from the enum
+	// mapping table of its type and the EnumUnknownPolicy is
+	// EnumUnknownError.
+	ErrUnknownEnumValue = errors.New("mapper: unknown enum value")
+
+	// ErrRequiredField indicates that a field tagged required is zero or
+	// has no counterpart in the source.
+	ErrRequiredField = errors.New("mapper: required field is missing or zero")
+
+	// ErrSliceTooLarge indicates a source slice longer than the limit set
+	// with WithMaxSliceCapacity.
+	ErrSliceTooLarge = errors.New("mapper: slice exceeds maximum capacity")
+
+	// ErrUnknownProfile indicates that no profile of a Registry is
+	// registered under the requested name.
+	ErrUnknownProfile = errors.New("mapper: unknown mapping profile")
+
+	// ErrManifestMismatch indicates that the running mapping configuration
+	// differs from an approved Manifest.
+	ErrManifestMismatch = errors.New("mapper: mapping manifest mismatch")
+
+	// ErrFrozen indicates an attempt to change the configuration of a
+	// mapper after Freeze.
+	ErrFrozen = errors.New("mapper: mapper is frozen")
+
+	// ErrSkipConversion can be returned by a ConverterFunc to decline a
+	// value, in which case the mapper falls through to its default mapping
+	// for that value instead of failing.
+	ErrSkipConversion = errors.New("mapper: skip conversion")
 )
 
 // MapError represents a detailed mapping failure, providing contextual
@@ -74,11 +119,18 @@ type MapError struct {
 	// Operation provides a short description of the failed mapping operation,
 	// e.g., "mapStruct", "mapSlice", etc.
 	Operation string
+
+	// Path is the full source path of the failing value, including slice
+	// indexes and map keys, e.g. "Order.Items[3].Price".
+	Path string
 }
 
 // Error implements the error interface and returns a formatted string
 // describing the mapping failure in detail.
 func (e *MapError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("mapper: failed to map %s (%s → %s): %v", e.Path, e.SrcType, e.DstType, e.Err)
+	}
 	if e.SrcField != "" && e.DstField != "" {
 		return fmt.Sprintf(
 			"mapper: failed to map %s.%s → %s.%s: %v",
@@ -104,3 +156,157 @@ func (e *MapError) Unwrap() error {
 func (e *MapError) Is(target error) bool {
 	return errors.Is(e.Err, target)
 }
+
+// MappingErrors collects every field failure of a mapping that continued
+// past them. It is returned by Map and Copy when one or more fields failed,
+// and unwraps to all of them, so errors.Is and errors.As inspect every
+// failure rather than only the first one.
+//
+// Example:
+//
+//	var errs *mapper.MappingErrors
+//	if errors.As(err, &errs) {
+//	    for _, e := range errs.Errors() {
+//	        log.Println(e)
+//	    }
+//	}
+type MappingErrors struct {
+	errs []error
+}
+
+// Errors returns the collected errors in the order they occurred.
+func (e *MappingErrors) Errors() []error {
+	return e.errs
+}
+
+// Error implements the error interface, listing every collected error.
+func (e *MappingErrors) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("mapping completed with %d errors: %s", len(e.errs), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the collected errors for errors.Is and errors.As.
+func (e *MappingErrors) Unwrap() []error {
+	return e.errs
+}
+
+// CategorizedError is implemented by the categorized field errors of a
+// mapping: ConversionError, MatchError, LimitError, ConverterError and
+// ValidationError. Each wraps the MapError describing the failure, so
+// middleware can choose a response by category and still report the
+// failing path.
+//
+// Example:
+//
+//	var conv *mapper.ConversionError
+//	var limit *mapper.LimitError
+//	switch {
+//	case errors.As(err, &conv), errors.As(err, &limit):
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	case err != nil:
+//	    http.Error(w, "internal error", http.StatusInternalServerError)
+//	}
+type CategorizedError interface {
+	error
+
+	// Path returns the full source path of the failing value.
+	Path() string
+
+	// Unwrap returns the MapError describing the failure.
+	Unwrap() error
+}
+
+// categorized implements CategorizedError for the error categories.
+type categorized struct {
+	*MapError
+}
+
+// Path returns the full source path of the failing value.
+func (e categorized) Path() string {
+	return e.MapError.Path
+}
+
+// Unwrap returns the MapError describing the failure.
+func (e categorized) Unwrap() error {
+	return e.MapError
+}
+
+// ConversionError reports a value that could not be converted to its
+// destination type, e.g. an unparsable string or an unknown enum value.
+// It usually stems from invalid input.
+type ConversionError struct{ categorized }
+
+// MatchError reports source and destination types that cannot be mapped
+// onto each other (ErrTypeMismatch, ErrUnsupportedType, ErrDoNotMap). It
+// usually stems from the mapping configuration or type definitions.
+type MatchError struct{ categorized }
+
+// LimitError reports a mapping stopped by a safety limit
+// (ErrMaxDepthExceeded, ErrCircularReference, ErrSliceTooLarge).
+type LimitError struct{ categorized }
+
+// ConverterError reports an error returned by a user callback: a
+// converter, constructor, translator or member rule.
+type ConverterError struct{ categorized }
+
+// ValidationError reports a mapped destination rejected by its Validate
+// method or the ValidatorFunc set with WithValidator. Like a
+// ConversionError, it usually stems from invalid input.
+type ValidationError struct{ categorized }
+
+// callbackFailure marks errors returned by user callbacks until they are
+// categorized.
+type callbackFailure struct {
+	err error
+}
+
+func (e *callbackFailure) Error() string { return e.err.Error() }
+func (e *callbackFailure) Unwrap() error { return e.err }
+
+// invalidInput marks errors caused by a source value the destination type
+// cannot hold, such as an unparsable string, until they are categorized.
+type invalidInput struct {
+	err error
+}
+
+func (e *invalidInput) Error() string { return e.err.Error() }
+func (e *invalidInput) Unwrap() error { return e.err }
+
+// invalidValue marks an error caused by an invalid source value.
+func invalidValue(err error) error {
+	return &invalidInput{err: err}
+}
+
+// callbackError marks a non-nil error returned by a user callback.
+func callbackError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &callbackFailure{err: err}
+}
+
+// categorize wraps a MapError in the error type of its category.
+func categorize(e *MapError) error {
+	var callback *callbackFailure
+	var invalid *invalidInput
+	var validation *validationFailure
+	switch {
+	case errors.As(e.Err, &callback):
+		e.Err = callback.err
+		return &ConverterError{categorized{e}}
+	case errors.As(e.Err, &invalid):
+		e.Err = invalid.err
+		return &ConversionError{categorized{e}}
+	case errors.As(e.Err, &validation):
+		e.Err = validation.err
+		return &ValidationError{categorized{e}}
+	case errors.Is(e.Err, ErrMaxDepthExceeded), errors.Is(e.Err, ErrCircularReference), errors.Is(e.Err, ErrSliceTooLarge):
+		return &LimitError{categorized{e}}
+	case errors.Is(e.Err, ErrTypeMismatch), errors.Is(e.Err, ErrUnsupportedType), errors.Is(e.Err, ErrDoNotMap):
+		return &MatchError{categorized{e}}
+	}
+	return &ConversionError{categorized{e}}
+}
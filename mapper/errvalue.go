@@ -0,0 +1,58 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the mapping of error values.
+package mapper
+
+import "reflect"
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ErrorValuePolicy selects how source values implementing error are mapped
+// onto string and error destinations.
+//
+// The zero value is ErrorValueConvert.
+type ErrorValuePolicy int
+
+const (
+	// ErrorValueConvert copies the error message into string destinations
+	// and preserves the error value itself in error destinations.
+	ErrorValueConvert ErrorValuePolicy = iota
+
+	// ErrorValueSkip leaves string and error destinations of error values
+	// untouched.
+	ErrorValueSkip
+)
+
+// mapErrorValue maps a source error onto a string or error destination
+// according to the ErrorValuePolicy. Errors are not copied field by field,
+// since their state is usually unexported. It reports whether the value was
+// handled; other destinations use the regular mapping.
+func (ctx *context) mapErrorValue(dst, src reflect.Value) (bool, error) {
+	if !src.Type().Implements(errorType) || isTypedNil(src) || !src.CanInterface() {
+		return false, nil
+	}
+
+	toString := dst.Kind() == reflect.String
+	toError := dst.Kind() == reflect.Interface && dst.Type().Implements(errorType)
+	if !toString && !toError {
+		return false, nil
+	}
+	if ctx.config.ErrorValuePolicy == ErrorValueSkip || !dst.CanSet() {
+		return true, nil
+	}
+
+	err, _ := src.Interface().(error)
+	if err == nil {
+		return true, nil
+	}
+	if toString {
+		dst.SetString(err.Error())
+		return true, nil
+	}
+
+	value := reflect.ValueOf(err)
+	if !value.Type().Implements(dst.Type()) {
+		return false, nil
+	}
+	dst.Set(value)
+	return true, nil
+}
@@ -0,0 +1,101 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the field context exposed to callbacks.
+package mapper
+
+import (
+	gocontext "context"
+	"errors"
+	"reflect"
+)
+
+// FieldContext describes the value being mapped when a callback runs.
+type FieldContext struct {
+	// Path is the full source path, including slice indexes and map keys,
+	// e.g. "User.Addresses[2].City".
+	Path string
+
+	// SrcField and DstField are the names of the source and destination
+	// fields, if the value is a struct field.
+	SrcField string
+	DstField string
+
+	// SrcType and DstType are the types of the values, if known.
+	SrcType reflect.Type
+	DstType reflect.Type
+
+	// Depth is the nesting depth of the value.
+	Depth int
+}
+
+// FieldErrorHandlerFunc is an ErrorHandlerFunc receiving the full context
+// of the failing field.
+type FieldErrorHandlerFunc func(err error, field FieldContext) error
+
+type fieldContextKey struct{}
+
+// FieldFromContext returns the FieldContext of the value being converted
+// from the context.Context passed to a ContextConverterFunc.
+//
+// Example:
+//
+//	mapper.WithContextConverter(moneyType, func(ctx context.Context, v reflect.Value) (reflect.Value, error) {
+//	    if field, ok := mapper.FieldFromContext(ctx); ok {
+//	        log.Printf("converting %s", field.Path)
+//	    }
+//	    ...
+//	})
+func FieldFromContext(goctx gocontext.Context) (FieldContext, bool) {
+	field, ok := goctx.Value(fieldContextKey{}).(FieldContext)
+	return field, ok
+}
+
+// fieldContext describes the value at the current path.
+func (ctx *context) fieldContext(dst, src reflect.Value, srcName, dstName string) FieldContext {
+	field := FieldContext{
+		Path:     ctx.fieldPath(),
+		SrcField: srcName,
+		DstField: dstName,
+		Depth:    ctx.depth,
+	}
+	if src.IsValid() {
+		field.SrcType = src.Type()
+	}
+	if dst.IsValid() {
+		field.DstType = dst.Type()
+	}
+	return field
+}
+
+// converterContext returns the context.Context passed to context
+// converters, carrying the FieldContext of the value being converted.
+func (ctx *context) converterContext(dst, src reflect.Value) gocontext.Context {
+	var srcName string
+	if n := len(ctx.path); n > 1 && ctx.path[n-1].name != "" {
+		srcName = ctx.path[n-1].name
+	}
+	return gocontext.WithValue(ctx.context(), fieldContextKey{}, ctx.fieldContext(dst, src, srcName, ""))
+}
+
+// fieldError passes a field failure through the configured error handlers
+// and collects it, wrapped in a MapError, unless a handler discards it.
+func (ctx *context) fieldError(err error, dst, src reflect.Value, srcName, dstName string) {
+	// Handlers see the callback's own error; passing it on keeps its category
+	cause := err
+	callback, fromCallback := err.(*callbackFailure)
+	if fromCallback {
+		cause = callback.err
+	}
+
+	switch {
+	case ctx.config.FieldErrorHandler != nil:
+		err = ctx.config.FieldErrorHandler(cause, ctx.fieldContext(dst, src, srcName, dstName))
+	case ctx.config.ErrorHandler != nil:
+		err = ctx.config.ErrorHandler(cause, srcName, dstName)
+	}
+	if fromCallback && errors.Is(err, cause) {
+		err = callbackError(err)
+	}
+	if err != nil {
+		ctx.addError(ctx.mapError("mapStruct", dst, src, srcName, dstName, err))
+	}
+}
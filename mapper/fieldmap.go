@@ -0,0 +1,33 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the fluent builder for explicit field mappings.
+package mapper
+
+// FieldMapping is a pending mapping of a source field, created by
+// Mapper.Field and completed by To.
+type FieldMapping struct {
+	m   *Mapper
+	src string
+}
+
+// Field starts an explicit mapping of the named source field.
+//
+// Field mappings must be registered before the mapper is used concurrently.
+// Completing a field mapping on a frozen mapper panics with ErrFrozen; use
+// Register with WithFieldMapping to handle the error instead.
+//
+// Example:
+//
+//	m := mapper.NewMapper()
+//	m.Field("FullName").To("Name").Field("YearsOld").To("Age")
+func (m *Mapper) Field(name string) *FieldMapping {
+	return &FieldMapping{m: m, src: name}
+}
+
+// To maps the source field onto the named destination field and returns
+// the mapper for chaining.
+func (f *FieldMapping) To(name string) *Mapper {
+	if err := f.m.Register(WithFieldMapping(map[string]string{f.src: name})); err != nil {
+		panic(err)
+	}
+	return f.m
+}
@@ -0,0 +1,183 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements fingerprinting of values under the mapping rules.
+package mapper
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Fingerprint returns a stable 64-bit hash of v computed with the same
+// traversal, tag and exclusion rules as mapping: fields a mapping would
+// skip (unexported or untagged fields, "-" tags) do not contribute, so
+// caches and change detection share the mapper's notion of identity.
+//
+// The hash is stable across processes. Map entries are hashed independently
+// of iteration order, pointers are followed (nil and cyclic references hash
+// to fixed markers) and time.Time values hash by instant, regardless of
+// location. Values of DoNotMapper types fail with ErrDoNotMap.
+//
+// Example:
+//
+//	before, _ := mapper.Fingerprint(user, mapper.WithTagName("json"))
+//	...
+//	after, _ := mapper.Fingerprint(user, mapper.WithTagName("json"))
+//	changed := before != after
+func Fingerprint(v interface{}, opts ...Option) (uint64, error) {
+	return NewMapper(opts...).Fingerprint(v)
+}
+
+// Fingerprint returns a stable 64-bit hash of v under the mapper's
+// configuration. See the package-level Fingerprint.
+func (m *Mapper) Fingerprint(v interface{}) (uint64, error) {
+	ctx := &context{
+		visited: make(map[visitKey]reflect.Value),
+		config:  m.config,
+		plans:   m.plans,
+	}
+
+	h := fnv.New64a()
+	if err := ctx.fingerprint(h, reflect.ValueOf(v)); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+// Markers separating values of different shapes in the hash stream.
+const (
+	fingerprintInvalid byte = iota
+	fingerprintNil
+	fingerprintCycle
+	fingerprintValue
+)
+
+// fingerprint writes the canonical hash input of v to h.
+func (ctx *context) fingerprint(h hash.Hash64, v reflect.Value) error {
+	if !v.IsValid() {
+		h.Write([]byte{fingerprintInvalid})
+		return nil
+	}
+
+	if ctx.config.MaxDepth != NoDepthLimit && ctx.depth > ctx.config.MaxDepth {
+		return ErrMaxDepthExceeded
+	}
+	ctx.depth++
+	defer func() { ctx.depth-- }()
+
+	if err := checkDoNotMap(v); err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			h.Write([]byte{fingerprintNil})
+			return nil
+		}
+	}
+
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Map {
+		key, cyclic := ctx.checkCircular(v, reflect.Value{})
+		if cyclic {
+			h.Write([]byte{fingerprintCycle})
+			return nil
+		}
+		defer ctx.leave(key)
+	}
+
+	h.Write([]byte{fingerprintValue})
+	var buf [8]byte
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(buf[:], u)
+		h.Write(buf[:])
+	}
+	writeString := func(s string) {
+		writeUint(uint64(len(s)))
+		h.Write([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(math.Float64bits(real(v.Complex())))
+		writeUint(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeString(v.String())
+	case reflect.Ptr:
+		return ctx.fingerprint(h, v.Elem())
+	case reflect.Interface:
+		writeString(v.Elem().Type().String())
+		return ctx.fingerprint(h, v.Elem())
+	case reflect.Slice, reflect.Array:
+		writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := ctx.fingerprint(h, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Hash entries separately and sort the sums for an
+		// order-independent result
+		sums := make([]uint64, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := fnv.New64a()
+			if err := ctx.fingerprint(entry, iter.Key()); err != nil {
+				return err
+			}
+			if err := ctx.fingerprint(entry, iter.Value()); err != nil {
+				return err
+			}
+			sums = append(sums, entry.Sum64())
+		}
+		slices.Sort(sums)
+		writeUint(uint64(len(sums)))
+		for _, sum := range sums {
+			writeUint(sum)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType && v.CanInterface() {
+			t := v.Interface().(time.Time)
+			writeUint(uint64(t.Unix()))
+			writeUint(uint64(t.Nanosecond()))
+			return nil
+		}
+		plan := ctx.structPlan(v.Type(), v.Type())
+		for _, field := range plan.fields {
+			fv, err := v.FieldByIndexErr(field.srcIndex)
+			if err != nil {
+				continue
+			}
+			if field.srcAtomic {
+				if fv, err = atomicLoad(fv); err != nil {
+					return err
+				}
+			}
+			writeString(field.srcName)
+			if err := ctx.fingerprint(h, fv); err != nil {
+				return fmt.Errorf("%s: %w", field.srcName, err)
+			}
+		}
+	default:
+		// Functions and channels contribute only whether they are set
+		writeString(v.Kind().String())
+	}
+	return nil
+}
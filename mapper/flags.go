@@ -0,0 +1,143 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements bitmask flag sets mapped to and from flag names.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// flagName is a named flag of a flag set.
+type flagName struct {
+	name string
+	bits uint64
+}
+
+// flagSet lists the named flags of an integer type in ascending bit order.
+type flagSet []flagName
+
+// WithFlags registers the names of the bitmask flags of flagType, an
+// integer type, so that its values map onto string slices of the names of
+// their set flags, and string slices map back onto flag values. Flag
+// values are converted to flagType, so untyped constants can be used; a
+// name may stand for several bits, and is then listed when all of them are
+// set. Bits without a name and unknown names are handled by the
+// EnumUnknownPolicy: they fail with ErrUnknownEnumValue by default, and are
+// dropped otherwise.
+//
+// Example:
+//
+//	type Permission uint8
+//
+//	const (
+//	    Read Permission = 1 << iota
+//	    Write
+//	    Delete
+//	)
+//
+//	mapper.Copy(&dto, user, mapper.WithFlags(reflect.TypeOf(Permission(0)),
+//	    map[string]any{"read": Read, "write": Write, "delete": Delete}))
+//	// user.Permissions = Read|Delete gives dto.Permissions = []string{"read", "delete"}
+func WithFlags(flagType reflect.Type, names map[string]any) Option {
+	var set flagSet
+	for name, v := range names {
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() || !rv.CanConvert(flagType) || (!rv.CanInt() && !rv.CanUint()) {
+			continue
+		}
+		if bits := flagBits(rv.Convert(flagType)); bits != 0 {
+			set = append(set, flagName{name: name, bits: bits})
+		}
+	}
+	sort.Slice(set, func(i, j int) bool {
+		if set[i].bits != set[j].bits {
+			return set[i].bits < set[j].bits
+		}
+		return set[i].name < set[j].name
+	})
+
+	return func(c *Config) {
+		if c.flags == nil {
+			c.flags = make(map[reflect.Type]flagSet)
+		}
+		c.flags[flagType] = set
+	}
+}
+
+// flagBits returns the bits of an integer flag value.
+func flagBits(v reflect.Value) uint64 {
+	if v.CanInt() {
+		return uint64(v.Int())
+	}
+	return v.Uint()
+}
+
+// isStringSlice reports whether t is a slice of a string kind.
+func isStringSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String
+}
+
+// mapFlags maps registered flag values onto string slices of flag names
+// and back. It reports whether the value was handled.
+func (ctx *context) mapFlags(dst, src reflect.Value) (bool, error) {
+	if !dst.CanSet() {
+		return false, nil
+	}
+	if set, ok := ctx.config.flags[src.Type()]; ok && isStringSlice(dst.Type()) {
+		return true, ctx.flagNames(dst, src, set)
+	}
+	if set, ok := ctx.config.flags[dst.Type()]; ok && isStringSlice(src.Type()) {
+		return true, ctx.flagValue(dst, src, set)
+	}
+	return false, nil
+}
+
+// flagNames sets dst to the names of the flags set in src.
+func (ctx *context) flagNames(dst, src reflect.Value, set flagSet) error {
+	bits := flagBits(src)
+	names := reflect.MakeSlice(dst.Type(), 0, len(set))
+	var named uint64
+	for _, flag := range set {
+		if bits&flag.bits == flag.bits {
+			names = reflect.Append(names, reflect.ValueOf(flag.name).Convert(dst.Type().Elem()))
+			named |= flag.bits
+		}
+	}
+	if unknown := bits &^ named; unknown != 0 && ctx.config.EnumUnknownPolicy == EnumUnknownError {
+		return fmt.Errorf("%w: %s has unnamed bits %#x", ErrUnknownEnumValue, src.Type(), unknown)
+	}
+	dst.Set(names)
+	return nil
+}
+
+// flagValue sets dst to the flags named in src.
+func (ctx *context) flagValue(dst, src reflect.Value, set flagSet) error {
+	var bits uint64
+	var unknown []string
+	for i := 0; i < src.Len(); i++ {
+		name := src.Index(i).String()
+		found := false
+		for _, flag := range set {
+			if flag.name == name {
+				bits |= flag.bits
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 && ctx.config.EnumUnknownPolicy == EnumUnknownError {
+		return fmt.Errorf("%w: %s has no flags %s", ErrUnknownEnumValue, dst.Type(), strings.Join(unknown, ", "))
+	}
+
+	if dst.CanInt() {
+		dst.SetInt(int64(bits))
+	} else {
+		dst.SetUint(bits)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements registration after construction and freezing.
+package mapper
+
+// Register applies opts to a mapper after construction, e.g. to add
+// converters or field mappings discovered at startup, and discards the
+// plans compiled so far. It returns ErrFrozen once the mapper is frozen.
+//
+// Register must not run concurrently with mappings: complete the
+// registrations, then Freeze the mapper before sharing it.
+//
+// Example:
+//
+//	m := mapper.NewMapper()
+//	if err := m.Register(mapper.WithCustomConverter(moneyType, formatMoney)); err != nil {
+//	    return err
+//	}
+//	m.Freeze()
+func (m *Mapper) Register(opts ...Option) error {
+	if m.frozen.Load() {
+		return ErrFrozen
+	}
+	for _, opt := range opts {
+		opt(m.config)
+	}
+	if m.intern == nil && m.config.InternMaxLen > 0 {
+		m.intern = newInterner(m.config.InternMaxLen)
+	}
+
+	// Compiled plans resolved names without these options
+	m.plans.Clear()
+	return nil
+}
+
+// Freeze makes the configuration of the mapper immutable: Register then
+// returns ErrFrozen, and Field(...).To panics with it. A frozen mapper only
+// ever reads its configuration and cached plans, so it can be shared across
+// goroutines without synchronization. Freezing is irreversible and
+// idempotent.
+func (m *Mapper) Freeze() {
+	m.frozen.Store(true)
+}
+
+// Frozen reports whether Freeze was called.
+func (m *Mapper) Frozen() bool {
+	return m.frozen.Load()
+}
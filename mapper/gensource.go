@@ -0,0 +1,337 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements exporting compiled plans as Go source.
+package mapper
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// GeneratePlanSource returns the Go source of functions equivalent to the
+// compiled plan of srcType onto dstType, which must be structs or pointers
+// to structs: Map<Src>To<Dst> plus one function per nested struct pair.
+// Type names are qualified with their package names, so the source compiles
+// in any package importing them.
+//
+// Fields whose mapping only exists at runtime (registered converters, unit
+// and atomic conversions, split, combine and computed members, overflow,
+// role-restricted fields) are left out with a comment explaining why, so
+// reviewers can see exactly what the generated code does not cover.
+// Pointers, slices and maps are deep copied when DeepCopy is on, like the
+// runtime does, and shared with a comment saying so when it is off.
+//
+// Example:
+//
+//	src, err := m.GeneratePlanSource(reflect.TypeOf(User{}), reflect.TypeOf(UserDTO{}))
+//	os.WriteFile("user_mapping.go", []byte("package dto\n\n"+src), 0o644)
+func (m *Mapper) GeneratePlanSource(srcType, dstType reflect.Type) (string, error) {
+	if srcType == nil || dstType == nil {
+		return "", fmt.Errorf("%w: nil type", ErrUnsupportedType)
+	}
+	srcType, dstType = derefType(srcType), derefType(dstType)
+	if srcType.Kind() != reflect.Struct || dstType.Kind() != reflect.Struct {
+		return "", fmt.Errorf("%w: cannot generate %s onto %s", ErrUnsupportedType, srcType, dstType)
+	}
+
+	g := &planGenerator{
+		ctx: &context{
+			config: m.config,
+			plans:  m.plans,
+		},
+		done: make(map[planKey]bool),
+	}
+	g.enqueue(srcType, dstType)
+	for len(g.queue) > 0 {
+		pair := g.queue[0]
+		g.queue = g.queue[1:]
+		g.emitFunc(pair.src, pair.dst)
+	}
+
+	formatted, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("mapper: formatting generated source: %w", err)
+	}
+	return string(formatted), nil
+}
+
+// planGenerator emits the mapping functions of a queue of struct pairs.
+type planGenerator struct {
+	ctx   *context
+	buf   bytes.Buffer
+	queue []planKey
+	done  map[planKey]bool
+	tmp   int
+}
+
+// planFuncName returns the name of the function generated for a pair.
+func planFuncName(src, dst reflect.Type) string {
+	return "Map" + src.Name() + "To" + dst.Name()
+}
+
+// enqueue schedules a struct pair and returns its function name.
+func (g *planGenerator) enqueue(src, dst reflect.Type) string {
+	key := planKey{src: src, dst: dst}
+	if !g.done[key] {
+		g.done[key] = true
+		g.queue = append(g.queue, key)
+	}
+	return planFuncName(src, dst)
+}
+
+// emitFunc writes the mapping function of one struct pair.
+func (g *planGenerator) emitFunc(srcType, dstType reflect.Type) {
+	name := planFuncName(srcType, dstType)
+	fmt.Fprintf(&g.buf, "// %s maps %s into a new %s.\n", name, srcType, dstType)
+	fmt.Fprintf(&g.buf, "func %s(src %s) (%s, error) {\n\tvar dst %s\n", name, srcType, dstType, dstType)
+
+	g.ctx.path = []pathSegment{{name: rootPathName(srcType)}}
+	sp := g.ctx.structPlan(srcType, dstType)
+	for _, field := range sp.fields {
+		g.ctx.pushPath(field.srcName)
+		g.emitField(srcType, dstType, sp, field)
+		g.ctx.popPath()
+	}
+
+	for _, member := range sp.members {
+		for _, dstField := range member.dstFields {
+			fmt.Fprintf(&g.buf, "\t// %s: set by a member rule at runtime\n", dstField.Name)
+		}
+	}
+	for _, d := range sp.defaults {
+		fmt.Fprintf(&g.buf, "\t// %s: defaults to %q at runtime\n", d.name, d.value)
+	}
+
+	g.buf.WriteString("\treturn dst, nil\n}\n\n")
+}
+
+// emitField writes the statements of one planned field.
+func (g *planGenerator) emitField(srcType, dstType reflect.Type, sp *structPlan, field fieldPlan) {
+	if field.dstIndex == nil {
+		if sp.dstOverflow >= 0 {
+			fmt.Fprintf(&g.buf, "\t// %s: captured as overflow at runtime\n", field.srcName)
+		}
+		return
+	}
+	if g.ctx.ignoredField(field.dstName) {
+		return
+	}
+
+	srcField := srcType.FieldByIndex(field.srcIndex)
+	dstField := dstFieldByIndex(dstType, field.dstIndex)
+	if converter := g.ctx.plannedConverter(field, srcField.Type, dstField.Type); converter != "" {
+		fmt.Fprintf(&g.buf, "\t// %s: %s conversion runs at runtime only\n", field.dstName, converter)
+		return
+	}
+	if field.srcRoles != nil || field.dstRoles != nil {
+		fmt.Fprintf(&g.buf, "\t// %s: visible to some roles only, mapped at runtime\n", field.dstName)
+		return
+	}
+
+	srcExpr, guards := fieldExpr("src", srcType, field.srcIndex)
+	dstExpr, allocs := g.dstExpr(dstType, field.dstIndex)
+
+	stmt, ok := g.assign(dstExpr, srcExpr, dstField.Type, srcField.Type)
+	if !ok {
+		fmt.Fprintf(&g.buf, "\t// %s: no static mapping from %s to %s\n", field.dstName, srcField.Type, dstField.Type)
+		return
+	}
+
+	for _, guard := range guards {
+		fmt.Fprintf(&g.buf, "\tif %s != nil {\n", guard)
+	}
+	g.buf.WriteString(allocs)
+	g.buf.WriteString(stmt)
+	for range guards {
+		g.buf.WriteString("\t}\n")
+	}
+}
+
+// fieldExpr returns the selector expression of the field at index within a
+// value of type t named root, and the pointers along the way that must be
+// non-nil to evaluate it.
+func fieldExpr(root string, t reflect.Type, index []int) (string, []string) {
+	var guards []string
+	expr := root
+	for i, fi := range index {
+		if i > 0 && t.Kind() == reflect.Ptr {
+			guards = append(guards, expr)
+			t = t.Elem()
+		}
+		field := t.Field(fi)
+		expr += "." + field.Name
+		t = field.Type
+	}
+	return expr, guards
+}
+
+// dstExpr returns the selector expression of the destination field at
+// index, and the statements allocating nil pointers along the way.
+func (g *planGenerator) dstExpr(t reflect.Type, index []int) (string, string) {
+	var allocs strings.Builder
+	expr := "dst"
+	for i, fi := range index {
+		if i > 0 && t.Kind() == reflect.Ptr {
+			fmt.Fprintf(&allocs, "\tif %s == nil {\n\t\t%s = new(%s)\n\t}\n", expr, expr, t.Elem())
+			t = t.Elem()
+		}
+		field := t.Field(fi)
+		expr += "." + field.Name
+		t = field.Type
+	}
+	return expr, allocs.String()
+}
+
+// assign returns the statements assigning srcExpr (of type st) to dstExpr
+// (of type dt), or false if the mapping has no static equivalent.
+func (g *planGenerator) assign(dstExpr, srcExpr string, dt, st reflect.Type) (string, bool) {
+	if st == dt {
+		return g.copyValue(dstExpr, srcExpr, st)
+	}
+
+	if st.Kind() == reflect.Struct && dt.Kind() == reflect.Struct && st != timeType && dt != timeType {
+		v := g.temp()
+		return fmt.Sprintf("\t{\n\t\t%s, err := %s(%s)\n\t\tif err != nil {\n\t\t\treturn dst, err\n\t\t}\n\t\t%s = %s\n\t}\n",
+			v, g.enqueue(st, dt), srcExpr, dstExpr, v), true
+	}
+
+	switch {
+	case st.Kind() == reflect.Ptr && dt.Kind() == reflect.Ptr:
+		v := g.temp()
+		inner, ok := g.assign(v, "(*"+srcExpr+")", dt.Elem(), st.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n\t\tvar %s %s\n%s\t\t%s = &%s\n\t}\n",
+			srcExpr, v, dt.Elem(), inner, dstExpr, v), true
+	case st.Kind() == reflect.Ptr:
+		inner, ok := g.assign(dstExpr, "(*"+srcExpr+")", dt, st.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n%s\t}\n", srcExpr, inner), true
+	case st.Kind() == reflect.Slice && dt.Kind() == reflect.Slice:
+		i := g.temp()
+		inner, ok := g.assign(dstExpr+"["+i+"]", srcExpr+"["+i+"]", dt.Elem(), st.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor %s := range %s {\n%s\t\t}\n\t}\n",
+			srcExpr, dstExpr, dt, srcExpr, i, srcExpr, inner), true
+	}
+
+	if staticConvertible(st, dt) {
+		return fmt.Sprintf("\t%s = %s(%s)\n", dstExpr, dt, srcExpr), true
+	}
+	return "", false
+}
+
+// copyValue copies a value between fields of identical type. With
+// DeepCopy on, pointers, slices, arrays, maps and structs holding them are
+// duplicated like the runtime does, so the destination shares no storage
+// with the source; interfaces have no static equivalent then. With
+// DeepCopy off, they are shared and marked as such.
+func (g *planGenerator) copyValue(dstExpr, srcExpr string, t reflect.Type) (string, bool) {
+	if !holdsReferences(t) {
+		return fmt.Sprintf("\t%s = %s\n", dstExpr, srcExpr), true
+	}
+	if !g.ctx.config.DeepCopy {
+		return fmt.Sprintf("\t%s = %s // shared with the source\n", dstExpr, srcExpr), true
+	}
+	if g.ctx.config.DeepCopyDepth > 0 {
+		// Sharing depends on the nesting depth at runtime
+		return "", false
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		v := g.temp()
+		inner, ok := g.copyValue(v, "(*"+srcExpr+")", t.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n\t\tvar %s %s\n%s\t\t%s = &%s\n\t}\n",
+			srcExpr, v, t.Elem(), inner, dstExpr, v), true
+	case reflect.Slice:
+		if !holdsReferences(t.Elem()) {
+			return fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tcopy(%s, %s)\n\t}\n",
+				srcExpr, dstExpr, t, srcExpr, dstExpr, srcExpr), true
+		}
+		i := g.temp()
+		inner, ok := g.copyValue(dstExpr+"["+i+"]", srcExpr+"["+i+"]", t.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor %s := range %s {\n%s\t\t}\n\t}\n",
+			srcExpr, dstExpr, t, srcExpr, i, srcExpr, inner), true
+	case reflect.Array:
+		i := g.temp()
+		inner, ok := g.copyValue(dstExpr+"["+i+"]", srcExpr+"["+i+"]", t.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tfor %s := range %s {\n%s\t}\n", i, srcExpr, inner), true
+	case reflect.Map:
+		k, v := g.temp(), g.temp()
+		if !holdsReferences(t.Elem()) {
+			return fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor %s, %s := range %s {\n\t\t\t%s[%s] = %s\n\t\t}\n\t}\n",
+				srcExpr, dstExpr, t, srcExpr, k, v, srcExpr, dstExpr, k, v), true
+		}
+		elem := g.temp()
+		inner, ok := g.copyValue(elem, v, t.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor %s, %s := range %s {\n\t\t\tvar %s %s\n%s\t\t\t%s[%s] = %s\n\t\t}\n\t}\n",
+			srcExpr, dstExpr, t, srcExpr, k, v, srcExpr, elem, t.Elem(), inner, dstExpr, k, elem), true
+	case reflect.Struct:
+		v := g.temp()
+		return fmt.Sprintf("\t{\n\t\t%s, err := %s(%s)\n\t\tif err != nil {\n\t\t\treturn dst, err\n\t\t}\n\t\t%s = %s\n\t}\n",
+			v, g.enqueue(t, t), srcExpr, dstExpr, v), true
+	}
+	return "", false
+}
+
+// holdsReferences reports whether values of type t refer to storage that
+// copying them by assignment would share: pointers, slices, maps and
+// interfaces, directly or in array elements and exported struct fields.
+func holdsReferences(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	case reflect.Array:
+		return holdsReferences(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() && holdsReferences(f.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// staticConvertible reports whether a Go conversion reproduces the
+// runtime conversion between two basic types. Integer to string
+// conversions are excluded because they produce runes.
+func staticConvertible(st, dt reflect.Type) bool {
+	numeric := func(k reflect.Kind) bool {
+		return k >= reflect.Int && k <= reflect.Float64
+	}
+	switch {
+	case numeric(st.Kind()) && numeric(dt.Kind()):
+		return true
+	case st.Kind() == reflect.String && dt.Kind() == reflect.String,
+		st.Kind() == reflect.Bool && dt.Kind() == reflect.Bool:
+		return true
+	}
+	return false
+}
+
+func (g *planGenerator) temp() string {
+	g.tmp++
+	return "v" + strconv.Itoa(g.tmp)
+}
@@ -0,0 +1,81 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements group-by projections of slices.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GroupBy groups items by the value of their keyField, preserving the order
+// of items within each group. Items must be structs or pointers to structs,
+// and the key field must be assignable or convertible to K.
+//
+// Example:
+//
+//	byOrder, err := mapper.GroupBy[OrderRow, int64](rows, "OrderID")
+func GroupBy[T any, K comparable](items []T, keyField string) (map[K][]T, error) {
+	groups := make(map[K][]T)
+	for i, item := range items {
+		key, err := groupKey[K](reflect.ValueOf(item), keyField)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		groups[key] = append(groups[key], item)
+	}
+	return groups, nil
+}
+
+// GroupByMapped groups items like GroupBy and maps each item into a new D
+// with the provided options, for assembling nested view models from flat
+// query rows.
+//
+// Example:
+//
+//	lines, err := mapper.GroupByMapped[OrderRow, int64, LineDTO](rows, "OrderID")
+func GroupByMapped[T any, K comparable, D any](items []T, keyField string, opts ...Option) (map[K][]D, error) {
+	m := NewMapper(opts...)
+	groups := make(map[K][]D)
+	for i, item := range items {
+		key, err := groupKey[K](reflect.ValueOf(item), keyField)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		var dst D
+		if err := m.Map(&dst, item); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		groups[key] = append(groups[key], dst)
+	}
+	return groups, nil
+}
+
+// groupKey reads the named field of a struct (or pointer to struct) value
+// as a K.
+func groupKey[K comparable](v reflect.Value, field string) (K, error) {
+	var key K
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return key, ErrNilPointer
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return key, fmt.Errorf("%w: cannot group %s by field", ErrUnsupportedType, v.Type())
+	}
+
+	f := v.FieldByName(field)
+	if !f.IsValid() || !f.CanInterface() {
+		return key, fmt.Errorf("%w: %s has no exported field %s", ErrTypeMismatch, v.Type(), field)
+	}
+
+	keyType := reflect.TypeOf((*K)(nil)).Elem()
+	f, ok := convertKey(f, keyType)
+	if !ok {
+		return key, fmt.Errorf("%w: key field %s of type %s is not a %s", ErrTypeMismatch, field, f.Type(), keyType)
+	}
+
+	reflect.ValueOf(&key).Elem().Set(f)
+	return key, nil
+}
@@ -0,0 +1,46 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements before/after mapping hooks and per-struct callbacks.
+package mapper
+
+import "reflect"
+
+// MapHookFunc is called before or after a Map call with the destination
+// pointer and source passed to Map. Returning an error aborts the mapping
+// (before hooks) or is returned from Map (after hooks).
+type MapHookFunc func(dst, src interface{}) error
+
+// AfterMapper is implemented by destination types that need to compute
+// derived fields or check invariants once mapped. AfterMap is called with
+// the source value after all fields of the struct have been populated,
+// including when the struct is nested in a larger mapping.
+//
+// Example:
+//
+//	func (d *OrderDTO) AfterMap(src any) error {
+//	    d.Total = d.Net + d.Tax
+//	    return nil
+//	}
+type AfterMapper interface {
+	AfterMap(src interface{}) error
+}
+
+var afterMapperType = reflect.TypeOf((*AfterMapper)(nil)).Elem()
+
+// afterMap invokes AfterMap on the destination struct if it implements
+// AfterMapper. Structural copies (source snapshots) do not invoke it.
+func (ctx *context) afterMap(dst, src reflect.Value) error {
+	if ctx.structural || !dst.CanAddr() {
+		return nil
+	}
+
+	ptr := dst.Addr()
+	if !ptr.Type().Implements(afterMapperType) || !ptr.CanInterface() {
+		return nil
+	}
+
+	var srcValue interface{}
+	if src.CanInterface() {
+		srcValue = src.Interface()
+	}
+	return ptr.Interface().(AfterMapper).AfterMap(srcValue)
+}
@@ -0,0 +1,77 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements localization of user-facing string fields.
+package mapper
+
+import (
+	gocontext "context"
+	"reflect"
+)
+
+// I18nTagOption routes a field's value through the configured translator
+// under the given message key, e.g. `mapper:",i18n=order.status"`. The
+// option may be set on the source or the destination field.
+const I18nTagOption = "i18n"
+
+// TranslatorFunc translates the value of a field tagged with I18nTagOption
+// into locale. key is the message key of the tag; value is the source value
+// with pointers followed, typically an enum or label.
+type TranslatorFunc func(locale, key string, value interface{}) (string, error)
+
+// localeKey is the context.Context key of the locale set with
+// ContextWithLocale.
+type localeKey struct{}
+
+// ContextWithLocale returns a copy of goctx carrying the locale passed to
+// the translator by MapContext. Without it, the translator receives the
+// locale set with WithTranslator.
+//
+// Example:
+//
+//	err := m.MapContext(mapper.ContextWithLocale(ctx, "de-DE"), &dto, order)
+func ContextWithLocale(goctx gocontext.Context, locale string) gocontext.Context {
+	return gocontext.WithValue(goctx, localeKey{}, locale)
+}
+
+// locale returns the locale of the current mapping.
+func (ctx *context) locale() string {
+	if ctx.goctx != nil {
+		if locale, ok := ctx.goctx.Value(localeKey{}).(string); ok {
+			return locale
+		}
+	}
+	return ctx.config.DefaultLocale
+}
+
+// fieldI18nKey returns the message key annotated on the source field, or
+// else on the destination field, or "".
+func (ctx *context) fieldI18nKey(srcField, dstField reflect.StructField) string {
+	if key, ok := ctx.tagOptionValue(srcField, I18nTagOption); ok {
+		return key
+	}
+	key, _ := ctx.tagOptionValue(dstField, I18nTagOption)
+	return key
+}
+
+// translate returns the translation of src under key, or src itself when
+// no translator is configured or src is nil.
+func (ctx *context) translate(src reflect.Value, key string) (reflect.Value, error) {
+	if ctx.config.Translator == nil {
+		return src, nil
+	}
+	value := src
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return src, nil
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() || !value.CanInterface() {
+		return src, nil
+	}
+
+	translated, err := ctx.config.Translator(ctx.locale(), key, value.Interface())
+	if err != nil {
+		return reflect.Value{}, callbackError(err)
+	}
+	return reflect.ValueOf(translated), nil
+}
@@ -0,0 +1,63 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements field and type ignore lists.
+package mapper
+
+import (
+	"path"
+	"reflect"
+	"strings"
+)
+
+// ignoredField reports whether the field being mapped, whose source path
+// is on the context, matches one of the IgnoreFields patterns. Patterns
+// without a dot match the source or destination field name at any depth;
+// dotted patterns match the path relative to the root, with the last
+// segment taken from either side.
+func (ctx *context) ignoredField(dstName string) bool {
+	if len(ctx.config.IgnoreFields) == 0 {
+		return false
+	}
+	fields := ctx.fieldNames()
+	if len(fields) < 2 {
+		return false
+	}
+
+	names := []string{fields[len(fields)-1]}
+	if dstName != "" && dstName != names[0] {
+		names = append(names, dstName)
+	}
+	parent := strings.Join(fields[1:len(fields)-1], "/")
+
+	for _, pattern := range ctx.config.IgnoreFields {
+		dotted := strings.Contains(pattern, ".")
+		pattern = strings.ReplaceAll(pattern, ".", "/")
+		for _, name := range names {
+			candidate := name
+			if dotted && parent != "" {
+				candidate = parent + "/" + name
+			}
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excludedField reports whether a field is never mapped because of its tag
+// (`mapper:"-"`) or its type.
+func (ctx *context) excludedField(field reflect.StructField) bool {
+	return field.Tag.Get(ctx.tagKey()) == "-" || ctx.ignoredType(field.Type)
+}
+
+// ignoredType reports whether fields of type t are skipped: t, or the type
+// t points to, is listed in IgnoreTypes.
+func (ctx *context) ignoredType(t reflect.Type) bool {
+	if len(ctx.config.IgnoreTypes) == 0 {
+		return false
+	}
+	if ctx.config.IgnoreTypes[t] {
+		return true
+	}
+	return t.Kind() == reflect.Ptr && ctx.config.IgnoreTypes[t.Elem()]
+}
@@ -0,0 +1,192 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements inspection of compiled mapping plans.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MappingPlan describes how a source struct type maps onto a destination
+// struct type, without mapping any values. Nested struct pairs are
+// expanded, so field names are dotted paths relative to the root.
+type MappingPlan struct {
+	Source      reflect.Type
+	Destination reflect.Type
+
+	// Fields lists the source fields that map onto destination fields.
+	Fields []PlannedField
+
+	// UnmappedSource lists source fields with no destination counterpart.
+	UnmappedSource []string
+
+	// UnmappedDestination lists settable destination fields that no
+	// source field or member rule writes.
+	UnmappedDestination []string
+}
+
+// PlannedField is one source-to-destination field mapping of a plan.
+type PlannedField struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+
+	// Converter names the conversion that would fire for the field:
+	// "named", "field", "pair", "context" or "custom" for registered converters,
+	// "unit", "atomic", "overflow", "split", "combine" or "computed", or ""
+	// for the default mapping.
+	Converter string `json:"converter,omitempty"`
+}
+
+// Plan returns the mapping plan for srcType onto dstType, which must be
+// structs or pointers to structs. It is intended for startup checks and
+// tests, e.g. asserting that UnmappedDestination is empty.
+//
+// Example:
+//
+//	plan, err := m.Plan(reflect.TypeOf(User{}), reflect.TypeOf(UserDTO{}))
+//	if err == nil && len(plan.UnmappedDestination) > 0 {
+//	    log.Fatalf("unmapped fields: %v", plan.UnmappedDestination)
+//	}
+func (m *Mapper) Plan(srcType, dstType reflect.Type) (*MappingPlan, error) {
+	if srcType == nil || dstType == nil {
+		return nil, fmt.Errorf("%w: nil type", ErrUnsupportedType)
+	}
+	srcType, dstType = derefType(srcType), derefType(dstType)
+	if srcType.Kind() != reflect.Struct || dstType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: cannot plan %s onto %s", ErrUnsupportedType, srcType, dstType)
+	}
+
+	ctx := &context{
+		config: m.config,
+		plans:  m.plans,
+		path:   []pathSegment{{name: rootPathName(srcType)}},
+	}
+	plan := &MappingPlan{Source: srcType, Destination: dstType}
+	ctx.inspectPlan(plan, srcType, dstType, "", map[planKey]bool{})
+	return plan, nil
+}
+
+// inspectPlan adds the fields of one struct type pair to plan, prefixing
+// names with prefix and recursing into nested struct pairs.
+func (ctx *context) inspectPlan(plan *MappingPlan, srcType, dstType reflect.Type, prefix string, seen map[planKey]bool) {
+	key := planKey{src: srcType, dst: dstType}
+	if seen[key] {
+		return
+	}
+	seen[key] = true
+	defer delete(seen, key)
+
+	sp := ctx.structPlan(srcType, dstType)
+	written := map[int]bool{} // top-level destination field indexes
+
+	for _, field := range sp.fields {
+		if field.dstIndex == nil {
+			if sp.dstOverflow >= 0 {
+				plan.Fields = append(plan.Fields, PlannedField{
+					Source:      prefix + field.srcName,
+					Destination: prefix + dstType.Field(sp.dstOverflow).Name,
+					Converter:   "overflow",
+				})
+			} else {
+				plan.UnmappedSource = append(plan.UnmappedSource, prefix+field.srcName)
+			}
+			continue
+		}
+
+		srcField := srcType.FieldByIndex(field.srcIndex)
+		dstField := dstFieldByIndex(dstType, field.dstIndex)
+		written[field.dstIndex[0]] = true
+
+		ctx.pushPath(field.srcName)
+		converter := ctx.plannedConverter(field, srcField.Type, dstField.Type)
+		nestedSrc, nestedDst := derefType(srcField.Type), derefType(dstField.Type)
+		if converter == "" && nestedSrc.Kind() == reflect.Struct && nestedDst.Kind() == reflect.Struct &&
+			nestedSrc != timeType && nestedDst != timeType {
+			ctx.inspectPlan(plan, nestedSrc, nestedDst, prefix+field.srcName+".", seen)
+		} else {
+			plan.Fields = append(plan.Fields, PlannedField{
+				Source:      prefix + field.srcName,
+				Destination: prefix + field.dstName,
+				Converter:   converter,
+			})
+		}
+		ctx.popPath()
+	}
+
+	for _, member := range sp.members {
+		sources := make([]string, len(member.srcNames))
+		for i, name := range member.srcNames {
+			sources[i] = prefix + name
+		}
+		converter := "combine"
+		switch {
+		case member.split != nil:
+			converter = "split"
+		case member.compute != nil:
+			converter = "computed"
+			sources = nil
+		}
+		for i, dstField := range member.dstFields {
+			written[member.dstIndexes[i][0]] = true
+			plan.Fields = append(plan.Fields, PlannedField{
+				Source:      strings.Join(sources, ","),
+				Destination: prefix + dstField.Name,
+				Converter:   converter,
+			})
+		}
+	}
+
+	for i := 0; i < dstType.NumField(); i++ {
+		dstField := dstType.Field(i)
+		if dstField.PkgPath != "" || i == sp.dstOverflow || ctx.excludedField(dstField) || written[i] {
+			continue
+		}
+		plan.UnmappedDestination = append(plan.UnmappedDestination, prefix+dstField.Name)
+	}
+}
+
+// plannedConverter names the conversion that would fire for a field whose
+// path is on the context.
+func (ctx *context) plannedConverter(field fieldPlan, srcType, dstType reflect.Type) string {
+	if field.converter != "" {
+		return "named"
+	}
+	if _, ok := ctx.fieldConverter(); ok {
+		return "field"
+	}
+	if _, ok := ctx.config.PairConverters[TypePair{Source: srcType, Destination: dstType}]; ok {
+		return "pair"
+	}
+	if _, ok := ctx.config.ContextConverters[srcType]; ok {
+		return "context"
+	}
+	if _, ok := ctx.config.CustomConverters[srcType]; ok {
+		return "custom"
+	}
+	switch {
+	case field.unit != nil:
+		return "unit"
+	case field.srcAtomic || field.dstAtomic:
+		return "atomic"
+	}
+	return ""
+}
+
+// dstFieldByIndex is Type.FieldByIndex stepping through pointers.
+func dstFieldByIndex(t reflect.Type, index []int) reflect.StructField {
+	var field reflect.StructField
+	for _, i := range index {
+		t = derefType(t)
+		field = t.Field(i)
+		t = field.Type
+	}
+	return field
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
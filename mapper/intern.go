@@ -0,0 +1,53 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements interning of short destination strings.
+package mapper
+
+import (
+	"hash/maphash"
+	"reflect"
+	"sync"
+)
+
+// internSlots is the number of slots of a string interner.
+const internSlots = 4096
+
+// interner deduplicates short strings through a direct-mapped table: each
+// string hashes to one slot, which keeps the last string stored there. The
+// table never grows, so memory stays bounded however many distinct strings
+// a batch holds, while the repeated ones (status codes, country names)
+// quickly settle into their slots and are shared.
+type interner struct {
+	mu     sync.Mutex
+	seed   maphash.Seed
+	maxLen int
+	slots  [internSlots]string
+}
+
+// newInterner creates an interner for strings of up to maxLen bytes.
+func newInterner(maxLen int) *interner {
+	return &interner{seed: maphash.MakeSeed(), maxLen: maxLen}
+}
+
+// intern returns the shared instance of s, storing s as that instance if
+// its slot holds another string.
+func (in *interner) intern(s string) string {
+	if len(s) == 0 || len(s) > in.maxLen {
+		return s
+	}
+	slot := maphash.String(in.seed, s) % internSlots
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.slots[slot] == s {
+		return in.slots[slot]
+	}
+	in.slots[slot] = s
+	return s
+}
+
+// internString replaces the string held by dst with its interned instance.
+func (ctx *context) internString(dst reflect.Value) {
+	if ctx.interner != nil && dst.Kind() == reflect.String && dst.CanSet() {
+		dst.SetString(ctx.interner.intern(dst.String()))
+	}
+}
@@ -0,0 +1,76 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements source isolation via defensive snapshots.
+package mapper
+
+import "reflect"
+
+// Snapshot returns a deep copy of src of the same type, taken while holding
+// the source locks registered with WithSourceLocker. It produces consistent
+// point-in-time copies of live, concurrently updated structures, e.g. for
+// metrics or debug endpoints. Only exported fields are copied; see
+// Mapper.Snapshot.
+//
+// Example:
+//
+//	view, err := mapper.Snapshot(stats,
+//	    mapper.WithSourceLocker(reflect.TypeOf(&Stats{}), lockStats, unlockStats))
+func Snapshot(src interface{}, opts ...Option) (interface{}, error) {
+	return NewMapper(opts...).Snapshot(src)
+}
+
+// Snapshot returns a deep copy of src of the same type using the mapper's
+// source lockers. Each locked value stays locked until it and everything
+// reachable from it has been copied; converters, tags and name mapping are
+// not applied. Values of DoNotMapper types are kept by reference.
+//
+// Only exported fields are copied: unexported fields, including embedded
+// locks and other sync primitives, are left zero in the snapshot, even when
+// the mapper maps them with WithAllowPrivateFields.
+func (m *Mapper) Snapshot(src interface{}) (interface{}, error) {
+	if src == nil {
+		return nil, ErrNilPointer
+	}
+
+	cp, err := m.snapshotSource(reflect.ValueOf(src))
+	if err != nil {
+		return nil, err
+	}
+	return cp.Interface(), nil
+}
+
+// snapshotSource returns a deep copy of src of the same type.
+//
+// The snapshot is taken with a plain structural copy that ignores
+// converters, tags and name mapping, so the actual mapping runs against a
+// private, consistent view of the source. Ignored types and fields are
+// left out, and DoNotMapper values are kept by reference: the mapping
+// itself refuses them if they are mapped. Registered source lockers are
+// honored while copying; callers mutating the source concurrently without
+// such a lock must still synchronize with the snapshot themselves.
+func (m *Mapper) snapshotSource(src reflect.Value) (reflect.Value, error) {
+	ctx := &context{
+		visited:    make(map[visitKey]reflect.Value),
+		structural: true,
+		config: &Config{
+			MaxDepth:          m.config.MaxDepth,
+			IgnoreUnexported:  true,
+			DeepCopy:          true,
+			CaseSensitive:     true,
+			SkipCircularCheck: m.config.SkipCircularCheck,
+			CyclePolicy:       m.config.CyclePolicy,
+			SourceLockers:     m.config.SourceLockers,
+			IgnoreTypes:       m.config.IgnoreTypes,
+			IgnoreFields:      m.config.IgnoreFields,
+		},
+		path: []pathSegment{{name: rootPathName(src.Type())}},
+	}
+
+	cp := reflect.New(src.Type()).Elem()
+	if err := ctx.mapValue(cp, src); err != nil {
+		return reflect.Value{}, err
+	}
+	if len(ctx.errors) > 0 {
+		return reflect.Value{}, ctx.errors[0]
+	}
+	return cp, nil
+}
@@ -0,0 +1,135 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements enrichment of mapped slices from lookup maps.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Lookup resolves a foreign key of each source item through a lookup map
+// and maps the matching value into a destination field. Lookups are
+// created with LookupBy.
+type Lookup struct {
+	keyField string
+	dstField string
+	values   reflect.Value
+}
+
+// LookupBy creates a Lookup reading the foreign key from the source field
+// keyField and mapping values[key] into the destination field dstField.
+// Both field names may be dotted paths. The key field must be assignable
+// or convertible to K.
+//
+// Example:
+//
+//	mapper.LookupBy("CustomerID", "Customer", customersByID)
+func LookupBy[K comparable, V any](keyField, dstField string, values map[K]V) Lookup {
+	return Lookup{keyField: keyField, dstField: dstField, values: reflect.ValueOf(values)}
+}
+
+// Join maps srcs into a new []D and enriches each item through the given
+// lookups, stitching related records loaded in bulk into nested
+// destination structs. Items whose key is missing from a lookup map, or
+// whose key field is a nil pointer, keep their mapped value.
+//
+// Example:
+//
+//	customers := loadCustomers(customerIDs(orders)) // map[int64]Customer
+//	dtos, err := mapper.Join[Order, OrderDTO](orders, []mapper.Lookup{
+//	    mapper.LookupBy("CustomerID", "Customer", customers),
+//	})
+func Join[S, D any](srcs []S, lookups []Lookup, opts ...Option) ([]D, error) {
+	m := NewMapper(opts...)
+	ctx := &context{config: m.config}
+
+	dsts := make([]D, len(srcs))
+	for i := range srcs {
+		if err := m.Map(&dsts[i], srcs[i]); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		src := reflect.ValueOf(srcs[i])
+		dst := reflect.ValueOf(&dsts[i]).Elem()
+		for _, lookup := range lookups {
+			if err := lookup.apply(ctx, m, dst, src); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+	}
+	return dsts, nil
+}
+
+// apply resolves the lookup for one source item and maps the match into
+// its destination.
+func (l Lookup) apply(ctx *context, m *Mapper, dst, src reflect.Value) error {
+	if !l.values.IsValid() || l.values.Kind() != reflect.Map {
+		return fmt.Errorf("%w: lookup for %s has no values map", ErrUnsupportedType, l.keyField)
+	}
+
+	src, ok := derefStruct(src)
+	if !ok {
+		return nil
+	}
+	keyField, keyIndex, found := ctx.resolvePath(src.Type(), l.keyField)
+	if !found || keyField.PkgPath != "" {
+		return fmt.Errorf("%w: %s has no field %s", ErrTypeMismatch, src.Type(), l.keyField)
+	}
+	key, err := src.FieldByIndexErr(keyIndex)
+	if err != nil {
+		return nil
+	}
+	if key.Kind() == reflect.Ptr {
+		if key.IsNil() {
+			return nil
+		}
+		key = key.Elem()
+	}
+	key, ok = convertKey(key, l.values.Type().Key())
+	if !ok {
+		return fmt.Errorf("%w: key field %s of type %s is not a %s", ErrTypeMismatch, l.keyField, keyField.Type, l.values.Type().Key())
+	}
+
+	value := l.values.MapIndex(key)
+	if !value.IsValid() {
+		return nil
+	}
+
+	dst, ok = derefStruct(dst)
+	if !ok {
+		return nil
+	}
+	_, dstIndex, found := ctx.resolvePath(dst.Type(), l.dstField)
+	if !found {
+		return fmt.Errorf("%w: %s has no field %s", ErrTypeMismatch, dst.Type(), l.dstField)
+	}
+	target := fieldByIndexAlloc(dst, dstIndex)
+	if !target.CanSet() {
+		return nil
+	}
+	return m.Map(target.Addr().Interface(), value.Interface())
+}
+
+// derefStruct dereferences pointers and interfaces down to a struct value.
+// It reports false for nil pointers and non-struct values.
+func derefStruct(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
+
+// convertKey converts a key value to keyType. Numbers are not converted
+// to strings (as runes) and vice versa.
+func convertKey(v reflect.Value, keyType reflect.Type) (reflect.Value, bool) {
+	switch {
+	case v.Type().AssignableTo(keyType):
+		return v, true
+	case v.Type().ConvertibleTo(keyType) && (v.Kind() == reflect.String) == (keyType.Kind() == reflect.String):
+		return v.Convert(keyType), true
+	}
+	return v, false
+}
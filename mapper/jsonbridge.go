@@ -0,0 +1,52 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements bridging between JSON blobs and structured values.
+package mapper
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// isJSONBlob reports whether t holds raw JSON: json.RawMessage or another
+// byte slice type.
+func isJSONBlob(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// isJSONDocument reports whether values of t are bridged to JSON blobs:
+// structs (other than time.Time) and maps, or pointers to them.
+func isJSONDocument(t reflect.Type) bool {
+	t = derefType(t)
+	return (t.Kind() == reflect.Struct && t != timeType) || t.Kind() == reflect.Map
+}
+
+// mapJSONBridge unmarshals JSON blobs into struct and map destinations,
+// and marshals struct and map sources into JSON blob destinations. Empty
+// blobs leave the destination untouched. It reports whether the value was
+// handled.
+func (ctx *context) mapJSONBridge(dst, src reflect.Value) (bool, error) {
+	switch {
+	case isJSONBlob(src.Type()) && isJSONDocument(dst.Type()):
+		if !dst.CanSet() || src.Len() == 0 {
+			return true, nil
+		}
+		target := reflect.New(dst.Type())
+		if err := json.Unmarshal(src.Bytes(), target.Interface()); err != nil {
+			return true, err
+		}
+		dst.Set(target.Elem())
+		return true, nil
+
+	case isJSONDocument(src.Type()) && isJSONBlob(dst.Type()):
+		if !dst.CanSet() || !src.CanInterface() {
+			return true, nil
+		}
+		data, err := json.Marshal(src.Interface())
+		if err != nil {
+			return true, err
+		}
+		dst.SetBytes(data)
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,98 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements capturing the raw source value of a field.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// KeepRawTagOption stores the source value of a field, before converters,
+// translation or any other transformation, into a sibling field of the
+// destination, e.g. `mapper:"email,keepraw=RawEmail"`. The sibling must be
+// a string field, which receives the value formatted with fmt.Sprint, or
+// an interface field such as any, which receives the value itself. The
+// option may be set on the source or the destination field.
+//
+// Example:
+//
+//	type SignupDTO struct {
+//	    Email    string `mapper:",converter=normalizeEmail,keepraw=RawEmail"`
+//	    RawEmail string // the email exactly as submitted, for audit
+//	}
+const KeepRawTagOption = "keepraw"
+
+// rawCapture is the sibling destination field receiving a raw source
+// value. err is set when the sibling is invalid and is reported when the
+// field is mapped.
+type rawCapture struct {
+	index []int
+	err   error
+}
+
+// fieldRawCapture returns the raw capture annotated on the source field, or
+// else on the destination field at dstIndex of dstType, or nil. The sibling
+// is looked up in the struct declaring the destination field.
+func (ctx *context) fieldRawCapture(srcField, dstField reflect.StructField, dstType reflect.Type, dstIndex []int) *rawCapture {
+	name, ok := ctx.tagOptionValue(srcField, KeepRawTagOption)
+	if !ok {
+		if name, ok = ctx.tagOptionValue(dstField, KeepRawTagOption); !ok {
+			return nil
+		}
+	}
+
+	parentIndex := dstIndex[:len(dstIndex)-1]
+	parent := dstType
+	if len(parentIndex) > 0 {
+		parent = dstType.FieldByIndex(parentIndex).Type
+		if parent.Kind() == reflect.Ptr {
+			parent = parent.Elem()
+		}
+	}
+
+	sibling, found := parent.FieldByName(name)
+	switch {
+	case !found:
+		return &rawCapture{err: fmt.Errorf("%w: keepraw field %q not found in %s", ErrUnsupportedType, name, parent)}
+	case sibling.Type.Kind() != reflect.String && sibling.Type.Kind() != reflect.Interface:
+		return &rawCapture{err: fmt.Errorf("%w: keepraw field %s.%s must be a string or an interface, not %s", ErrTypeMismatch, parent, name, sibling.Type)}
+	}
+	return &rawCapture{index: append(append([]int(nil), parentIndex...), sibling.Index...)}
+}
+
+// captureRaw stores the source value src into the sibling field of the
+// destination struct dst described by capture. Nil sources store the
+// sibling's zero value.
+func (ctx *context) captureRaw(dst, src reflect.Value, capture *rawCapture) error {
+	if capture.err != nil {
+		return capture.err
+	}
+	sibling := fieldByIndexAlloc(dst, capture.index)
+	if ctx.config.AllowPrivateFields {
+		sibling = exposeField(sibling)
+	}
+	if !sibling.CanSet() {
+		return nil
+	}
+
+	for src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			sibling.SetZero()
+			return nil
+		}
+		src = src.Elem()
+	}
+	if !src.CanInterface() {
+		return nil
+	}
+
+	switch {
+	case sibling.Kind() == reflect.String:
+		sibling.SetString(fmt.Sprint(src.Interface()))
+	case src.Type().Implements(sibling.Type()):
+		sibling.Set(src)
+	default:
+		return fmt.Errorf("%w: %s does not implement %s", ErrTypeMismatch, src.Type(), sibling.Type())
+	}
+	return nil
+}
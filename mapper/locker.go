@@ -0,0 +1,56 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements per-type lock hooks for mutex-guarded sources.
+package mapper
+
+import "reflect"
+
+// LockFunc acquires or releases a lock guarding a source value. It receives
+// the source value (typically a pointer) being mapped.
+type LockFunc func(src interface{})
+
+// SourceLocker pairs the lock and unlock hooks registered for a type.
+type SourceLocker struct {
+	Lock   LockFunc
+	Unlock LockFunc
+}
+
+// lockSource acquires the registered lock for src, if any, and returns the
+// function releasing it, or nil when src's type has no locker or its lock is
+// already held by this mapping.
+func (ctx *context) lockSource(src reflect.Value) func() {
+	if len(ctx.config.SourceLockers) == 0 {
+		return nil
+	}
+
+	locker, ok := ctx.config.SourceLockers[src.Type()]
+	if !ok && src.Kind() != reflect.Ptr && src.CanAddr() {
+		// Addressable struct values (e.g. embedded or reached through a
+		// pointer) are locked through their address.
+		src = src.Addr()
+		locker, ok = ctx.config.SourceLockers[src.Type()]
+	}
+	if !ok || !src.CanInterface() || src.Kind() != reflect.Ptr {
+		return nil
+	}
+
+	ptr := src.Pointer()
+	ctx.mu.Lock()
+	if ctx.locked[ptr] {
+		ctx.mu.Unlock()
+		return nil
+	}
+	if ctx.locked == nil {
+		ctx.locked = make(map[uintptr]bool)
+	}
+	ctx.locked[ptr] = true
+	ctx.mu.Unlock()
+
+	v := src.Interface()
+	locker.Lock(v)
+	return func() {
+		locker.Unlock(v)
+		ctx.mu.Lock()
+		delete(ctx.locked, ptr)
+		ctx.mu.Unlock()
+	}
+}
@@ -0,0 +1,355 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements manifests of mapping configurations.
+package mapper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Manifest records the mapping rules of one or more profiles: their
+// configuration and the plans of selected type pairs, along with a content
+// hash. Regulated services export a manifest, review and approve it (for
+// instance by signing the hash or committing the JSON), and verify at
+// startup that the running configuration still matches it.
+//
+// Callbacks such as converters and hooks are recorded by presence and by
+// the types or paths they are registered for, not by their code.
+type Manifest struct {
+	Profiles []ManifestProfile `json:"profiles"`
+
+	// Hash is the hex SHA-256 of the JSON encoding of Profiles, prefixed
+	// with "sha256:".
+	Hash string `json:"hash"`
+}
+
+// ManifestProfile is the configuration of one mapper in a Manifest.
+type ManifestProfile struct {
+	Name string `json:"name"`
+
+	// Settings holds the non-zero configuration settings by name.
+	Settings map[string]string `json:"settings"`
+
+	Plans []ManifestPlan `json:"plans,omitempty"`
+}
+
+// ManifestPlan is the mapping plan of a type pair in a Manifest.
+type ManifestPlan struct {
+	Source              string         `json:"source"`
+	Destination         string         `json:"destination"`
+	Fields              []PlannedField `json:"fields"`
+	UnmappedSource      []string       `json:"unmapped_source,omitempty"`
+	UnmappedDestination []string       `json:"unmapped_destination,omitempty"`
+}
+
+// TypePair is a source and destination type, whose plan is recorded in a
+// Manifest or whose values are converted by WithPairConverter.
+type TypePair struct {
+	Source      reflect.Type
+	Destination reflect.Type
+}
+
+// Pair returns the TypePair of S and D.
+func Pair[S, D any]() TypePair {
+	return TypePair{
+		Source:      reflect.TypeOf((*S)(nil)).Elem(),
+		Destination: reflect.TypeOf((*D)(nil)).Elem(),
+	}
+}
+
+// Manifest returns the manifest of the mapper as a single profile called
+// name, including the plans of pairs, which must be struct type pairs.
+//
+// Example:
+//
+//	mf, err := m.Manifest("api", mapper.Pair[User, UserDTO]())
+//	data, err := json.MarshalIndent(mf, "", "  ")
+func (m *Mapper) Manifest(name string, pairs ...TypePair) (*Manifest, error) {
+	profile, err := m.manifestProfile(name, pairs)
+	if err != nil {
+		return nil, err
+	}
+	return newManifest([]ManifestProfile{profile})
+}
+
+// Manifest returns the manifest of every profile of the registry, in
+// sorted order, each including the plans of pairs.
+//
+// Example:
+//
+//	//go:embed mapping-manifest.json
+//	var approved []byte
+//
+//	var want mapper.Manifest
+//	if err := json.Unmarshal(approved, &want); err != nil {
+//	    log.Fatal(err)
+//	}
+//	got, err := reg.Manifest(mapper.Pair[User, UserDTO]())
+//	if err == nil {
+//	    err = got.Verify(&want)
+//	}
+//	if err != nil {
+//	    log.Fatalf("mapping rules drifted: %v", err)
+//	}
+func (r *Registry) Manifest(pairs ...TypePair) (*Manifest, error) {
+	var profiles []ManifestProfile
+	for _, name := range r.Profiles() {
+		m, _ := r.Mapper(name)
+		profile, err := m.manifestProfile(name, pairs)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return newManifest(profiles)
+}
+
+// Verify checks that the manifest matches the approved one. It returns an
+// error wrapping ErrManifestMismatch that lists the differences, or that
+// reports an approved manifest whose contents no longer match its hash.
+func (mf *Manifest) Verify(approved *Manifest) error {
+	hash, err := manifestHash(approved.Profiles)
+	if err != nil {
+		return err
+	}
+	if hash != approved.Hash {
+		return fmt.Errorf("%w: approved manifest does not match its hash", ErrManifestMismatch)
+	}
+	if mf.Hash == approved.Hash {
+		return nil
+	}
+
+	diffs := diffProfiles(approved.Profiles, mf.Profiles)
+	if len(diffs) == 0 {
+		diffs = []string{"hash " + approved.Hash + " became " + mf.Hash}
+	}
+	return fmt.Errorf("%w: %s", ErrManifestMismatch, strings.Join(diffs, "; "))
+}
+
+// newManifest returns the manifest of profiles with its hash.
+func newManifest(profiles []ManifestProfile) (*Manifest, error) {
+	hash, err := manifestHash(profiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{Profiles: profiles, Hash: hash}, nil
+}
+
+// manifestHash returns the content hash of profiles.
+func manifestHash(profiles []ManifestProfile) (string, error) {
+	data, err := json.Marshal(profiles)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// manifestProfile describes the mapper as the profile name.
+func (m *Mapper) manifestProfile(name string, pairs []TypePair) (ManifestProfile, error) {
+	profile := ManifestProfile{Name: name, Settings: m.config.settings()}
+	for _, pair := range pairs {
+		plan, err := m.Plan(pair.Source, pair.Destination)
+		if err != nil {
+			return ManifestProfile{}, err
+		}
+		profile.Plans = append(profile.Plans, ManifestPlan{
+			Source:              plan.Source.String(),
+			Destination:         plan.Destination.String(),
+			Fields:              plan.Fields,
+			UnmappedSource:      plan.UnmappedSource,
+			UnmappedDestination: plan.UnmappedDestination,
+		})
+	}
+	return profile, nil
+}
+
+// settings returns the non-zero settings of c by field name. Rules stored
+// in unexported fields are listed under the name of their option.
+func (c *Config) settings() map[string]string {
+	settings := make(map[string]string)
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if s := describeSetting(v.Field(i)); s != "" {
+			settings[field.Name] = s
+		}
+	}
+
+	var rules []string
+	for pair, table := range c.enums {
+		var entries []string
+		for from, to := range table {
+			entries = append(entries, fmt.Sprintf("%v=%v", from, to))
+		}
+		sort.Strings(entries)
+		rules = append(rules, fmt.Sprintf("%s->%s{%s}", pair[0], pair[1], strings.Join(entries, ", ")))
+	}
+	addRules(settings, "WithEnumMapping", rules)
+
+	rules = nil
+	for typ, set := range c.flags {
+		names := make([]string, len(set))
+		for i, flag := range set {
+			names[i] = fmt.Sprintf("%s=%#x", flag.name, flag.bits)
+		}
+		rules = append(rules, fmt.Sprintf("%s{%s}", typ, strings.Join(names, ", ")))
+	}
+	addRules(settings, "WithFlags", rules)
+
+	rules = nil
+	for path, conds := range c.conditions {
+		rules = append(rules, fmt.Sprintf("%s(%d)", path, len(conds)))
+	}
+	addRules(settings, "WithCondition", rules)
+
+	rules = nil
+	for _, field := range c.computed {
+		rules = append(rules, field.srcType.String()+"->"+field.target)
+	}
+	addRules(settings, "ForField", rules)
+
+	return settings
+}
+
+// addRules records the sorted rules under name, if any.
+func addRules(settings map[string]string, name string, rules []string) {
+	if len(rules) == 0 {
+		return
+	}
+	sort.Strings(rules)
+	settings[name] = "[" + strings.Join(rules, ", ") + "]"
+}
+
+// describeSetting formats a configuration value deterministically: funcs
+// by presence, maps by sorted keys (and values, unless they are funcs) and
+// structs by their non-zero exported fields. It returns "" for zero values.
+func describeSetting(v reflect.Value) string {
+	if v.IsZero() {
+		return ""
+	}
+
+	switch v.Kind() {
+	case reflect.Func:
+		return "func"
+
+	case reflect.Interface:
+		if t, ok := v.Interface().(reflect.Type); ok {
+			return t.String()
+		}
+		return describeSetting(v.Elem())
+
+	case reflect.Ptr:
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+		return describeSetting(v.Elem())
+
+	case reflect.Map:
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := describeSetting(iter.Key())
+			if iter.Value().Kind() != reflect.Func {
+				entry += "=" + describeSetting(iter.Value())
+			}
+			entries = append(entries, entry)
+		}
+		sort.Strings(entries)
+		return "{" + strings.Join(entries, ", ") + "}"
+
+	case reflect.Slice, reflect.Array:
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = describeSetting(v.Index(i))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+
+	case reflect.Struct:
+		var fields []string
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				// Opaque values such as policies print as a whole
+				return fmt.Sprintf("%+v", v.Interface())
+			}
+			if s := describeSetting(v.Field(i)); s != "" {
+				fields = append(fields, v.Type().Field(i).Name+":"+s)
+			}
+		}
+		return "{" + strings.Join(fields, " ") + "}"
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// diffProfiles lists the differences between the approved profiles and the
+// running ones.
+func diffProfiles(approved, running []ManifestProfile) []string {
+	var diffs []string
+	byName := make(map[string]ManifestProfile, len(running))
+	for _, profile := range running {
+		byName[profile.Name] = profile
+	}
+
+	for _, want := range approved {
+		got, ok := byName[want.Name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("profile %q is missing", want.Name))
+			continue
+		}
+		delete(byName, want.Name)
+
+		keys := sortedKeysOf(want.Settings)
+		for _, key := range sortedKeysOf(got.Settings) {
+			if _, ok := want.Settings[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		for _, key := range keys {
+			if want.Settings[key] != got.Settings[key] {
+				diffs = append(diffs, fmt.Sprintf("profile %q: %s is %q, approved %q", want.Name, key, got.Settings[key], want.Settings[key]))
+			}
+		}
+
+		plans := make(map[string]ManifestPlan, len(got.Plans))
+		for _, plan := range got.Plans {
+			plans[plan.Source+"->"+plan.Destination] = plan
+		}
+		for _, plan := range want.Plans {
+			key := plan.Source + "->" + plan.Destination
+			gotPlan, ok := plans[key]
+			switch {
+			case !ok:
+				diffs = append(diffs, fmt.Sprintf("profile %q: plan %s is missing", want.Name, key))
+			case !reflect.DeepEqual(plan, gotPlan):
+				diffs = append(diffs, fmt.Sprintf("profile %q: plan %s changed", want.Name, key))
+			}
+			delete(plans, key)
+		}
+		for _, key := range sortedKeysOf(plans) {
+			diffs = append(diffs, fmt.Sprintf("profile %q: plan %s was not approved", want.Name, key))
+		}
+	}
+
+	for _, name := range sortedKeysOf(byName) {
+		diffs = append(diffs, fmt.Sprintf("profile %q was not approved", name))
+	}
+	return diffs
+}
+
+// sortedKeysOf returns the keys of m in sorted order.
+func sortedKeysOf[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -16,9 +16,13 @@
 package mapper
 
 import (
+	gocontext "context"
 	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fbarikzehi/gomap/internal/reflectutil"
@@ -28,8 +32,11 @@ import (
 // It holds configuration options and manages a pool of reusable
 // mapping contexts to minimize allocations.
 type Mapper struct {
-	config *Config    // Configuration for this mapper instance
-	pool   *sync.Pool // Pool of reusable mapping contexts
+	config *Config     // Configuration for this mapper instance
+	pool   *sync.Pool  // Pool of reusable mapping contexts
+	plans  *planCache  // Compiled struct mapping plans by type pair
+	intern *interner   // Shared string instances, or nil
+	frozen atomic.Bool // Set by Freeze
 }
 
 // NewMapper creates and returns a new Mapper instance configured with
@@ -57,12 +64,19 @@ func NewMapper(opts ...Option) *Mapper {
 		opt(cfg)
 	}
 
+	var intern *interner
+	if cfg.InternMaxLen > 0 {
+		intern = newInterner(cfg.InternMaxLen)
+	}
+
 	return &Mapper{
 		config: cfg,
+		plans:  &planCache{},
+		intern: intern,
 		pool: &sync.Pool{
 			New: func() interface{} {
 				return &context{
-					visited: make(map[uintptr]reflect.Value),
+					visited: make(map[visitKey]reflect.Value),
 					errors:  make([]error, 0),
 				}
 			},
@@ -71,21 +85,58 @@ func NewMapper(opts ...Option) *Mapper {
 }
 
 // Map performs the actual mapping from src to dst. The destination must
-// be a pointer to a struct.
+// be a non-nil pointer, typically to a struct, slice or map. The source may
+// be a value or a pointer to a value of any supported kind. Slice and map
+// roots need no plan of their own: their struct elements use the plans
+// cached for every other mapping of the same types.
 //
 // Map performs a deep copy of all supported types (structs, slices, maps, etc.)
 // and applies custom converters or tag-based field mapping as configured.
+// The source is only read, never modified. Maps and slices share their
+// backing storage with the caller even when passed by value, so sources that
+// may be mutated concurrently should be mapped with WithIsolateSource(true).
 //
 // Example:
 //
 //	var dst UserDTO
 //	err := mapper.Map(&dst, srcUser)
 //
+//	var dtos []UserDTO
+//	err = mapper.Map(&dtos, &users)
+//
 // Returns an error if:
 //   - dst or src is nil (ErrNilPointer)
 //   - dst is not a pointer (ErrInvalidDestination)
+//   - a slice, array or map root is mapped onto an incompatible kind (ErrTypeMismatch)
 //   - The mapping exceeds the maximum configured depth (ErrMaxDepthExceeded)
 func (m *Mapper) Map(dst, src interface{}) error {
+	return m.MapContext(gocontext.Background(), dst, src)
+}
+
+// MapContext maps src into dst like Map, passing goctx to converters
+// registered with WithContextConverter. Mapping stops once goctx is done,
+// and MapContext then returns goctx.Err(); the destination may be partially
+// populated.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Second)
+//	defer cancel()
+//	err := m.MapContext(ctx, &report, hugeGraph)
+func (m *Mapper) MapContext(goctx gocontext.Context, dst, src interface{}) error {
+	return m.mapContext(goctx, dst, src, nil)
+}
+
+// mapContext implements MapContext, recording warnings into report when it
+// is non-nil.
+func (m *Mapper) mapContext(goctx gocontext.Context, dst, src interface{}, report *Report) error {
+	if goctx == nil {
+		goctx = gocontext.Background()
+	}
+	if err := goctx.Err(); err != nil {
+		return err
+	}
+
 	if dst == nil || src == nil {
 		return ErrNilPointer
 	}
@@ -94,8 +145,33 @@ func (m *Mapper) Map(dst, src interface{}) error {
 	if dstVal.Kind() != reflect.Ptr {
 		return ErrInvalidDestination
 	}
+	if dstVal.IsNil() {
+		return ErrNilPointer
+	}
 
 	srcVal := reflect.ValueOf(src)
+	if err := validateRoots(dstVal.Elem(), srcVal); err != nil {
+		return err
+	}
+	if m.config.AllowPrivateFields {
+		if err := m.config.requireUnsafe("private field access"); err != nil {
+			return err
+		}
+	}
+
+	if m.config.BeforeMap != nil {
+		if err := m.config.BeforeMap(dst, src); err != nil {
+			return err
+		}
+	}
+
+	if m.config.IsolateSource {
+		snapshot, err := m.snapshotSource(srcVal)
+		if err != nil {
+			return err
+		}
+		srcVal = snapshot
+	}
 
 	ctx := m.pool.Get().(*context)
 	defer m.pool.Put(ctx)
@@ -105,31 +181,81 @@ func (m *Mapper) Map(dst, src interface{}) error {
 		delete(ctx.visited, k)
 	}
 	ctx.errors = ctx.errors[:0]
+	clear(ctx.locked)
+	clear(ctx.mapped)
 	ctx.depth = 0
+	ctx.path = append(ctx.path[:0], pathSegment{name: rootPathName(srcVal.Type())})
 	ctx.config = m.config
+	ctx.plans = m.plans
+	ctx.interner = m.intern
+	ctx.goctx = goctx
+	ctx.report = report
+	defer func() { ctx.goctx, ctx.report = nil, nil }()
 
 	err := ctx.mapValue(dstVal.Elem(), srcVal)
+	if goctxErr := goctx.Err(); goctxErr != nil {
+		return goctxErr
+	}
+	if callback, ok := err.(*callbackFailure); ok {
+		// Root values have no path to categorize with
+		err = callback.err
+	}
 	if err != nil {
 		return err
 	}
+	ctx.validateRoot(dst)
 
 	if len(ctx.errors) > 0 {
-		return fmt.Errorf("mapping completed with %d errors: %v", len(ctx.errors), ctx.errors[0])
+		// The context is pooled, so the errors are copied out
+		return &MappingErrors{errs: slices.Clone(ctx.errors)}
+	}
+
+	if m.config.AfterMap != nil {
+		return m.config.AfterMap(dst, src)
+	}
+
+	return nil
+}
+
+// validateRoots checks that collection roots are mapped onto compatible
+// destinations. Slices and arrays must map onto slices or arrays, and maps
+// onto maps or structs; struct and basic roots are handled by the regular mapping rules.
+func validateRoots(dst, src reflect.Value) error {
+	srcType := src.Type()
+	for srcType.Kind() == reflect.Ptr {
+		srcType = srcType.Elem()
+	}
+	dstType := dst.Type()
+	for dstType.Kind() == reflect.Ptr {
+		dstType = dstType.Elem()
 	}
 
+	compatible := true
+	switch srcType.Kind() {
+	case reflect.Slice, reflect.Array:
+		compatible = dstType.Kind() == reflect.Slice || dstType.Kind() == reflect.Array
+	case reflect.Map:
+		compatible = dstType.Kind() == reflect.Map || dstType.Kind() == reflect.Struct
+	}
+
+	if !compatible && dstType.Kind() != reflect.Interface {
+		return fmt.Errorf("%w: cannot map %s onto %s", ErrTypeMismatch, srcType, dstType)
+	}
 	return nil
 }
 
 // Copy is a convenience helper for performing a one-time struct mapping
-// without explicitly creating a Mapper instance.
+// without explicitly creating a Mapper instance. It maps with the default
+// mapper (see SetDefault), configured further with opts if any. Per-call
+// options build a one-off mapper whose compiled plans are not reused by
+// later calls; hot paths should map with a Mapper built once instead.
 //
 // Example:
 //
 //	var dst MyStruct
 //	err := Copy(&dst, src, WithMaxDepth(5))
 func Copy(dst, src interface{}, opts ...Option) error {
-	m := NewMapper(opts...)
-	return m.Map(dst, src)
+	return withDefaults(opts).Map(dst, src)
 }
 
 // mapValue recursively maps a value from src to dst.
@@ -148,6 +274,11 @@ func (ctx *context) mapValue(dst, src reflect.Value) error {
 		return nil
 	}
 
+	// Stop once the caller's context is done
+	if err := ctx.canceled(); err != nil {
+		return err
+	}
+
 	// Depth control
 	if ctx.config.MaxDepth != NoDepthLimit && ctx.depth > ctx.config.MaxDepth {
 		return ErrMaxDepthExceeded
@@ -164,22 +295,102 @@ func (ctx *context) mapValue(dst, src reflect.Value) error {
 		return nil
 	}
 
+	// Refuse to copy resource handles and other marked types. Snapshots
+	// keep them by reference: the mapping proper refuses them if needed.
+	if err := checkDoNotMap(src); err != nil {
+		if ctx.structural && dst.CanSet() && dst.Type() == src.Type() {
+			dst.Set(src)
+			return nil
+		}
+		return err
+	}
+
 	// Circular reference detection
 	if !ctx.config.SkipCircularCheck && reflectutil.IsPointerLike(src.Kind()) {
-		if err := ctx.checkCircular(src); err != nil {
-			return err
+		key, cyclic := ctx.checkCircular(src, dst)
+		if cyclic {
+			if proceed, err := ctx.handleCycle(dst, key); !proceed {
+				return err
+			}
+		} else {
+			if ctx.reuse(dst, src) {
+				ctx.leave(key)
+				return nil
+			}
+			defer ctx.done(key, dst)
 		}
 	}
 
-	// Custom converters
+	// Hold the registered read lock of mutex-guarded sources while copying
+	if unlock := ctx.lockSource(src); unlock != nil {
+		defer unlock()
+	}
+
+	// Custom converters, the most specific first; each may decline the
+	// value with ErrSkipConversion
+	if converter, ok := ctx.config.PairConverters[TypePair{Source: src.Type(), Destination: dst.Type()}]; ok {
+		if handled, err := ctx.applyConverter(converter, dst, src); handled || err != nil {
+			return err
+		}
+	}
+	if converter, ok := ctx.config.ContextConverters[src.Type()]; ok {
+		bound := func(v reflect.Value) (reflect.Value, error) {
+			return converter(ctx.converterContext(dst, v), v)
+		}
+		if handled, err := ctx.applyConverter(bound, dst, src); handled || err != nil {
+			return err
+		}
+	}
 	if converter, ok := ctx.config.CustomConverters[src.Type()]; ok {
-		converted, err := converter(src)
-		if err != nil {
+		if handled, err := ctx.applyConverter(converter, dst, src); handled || err != nil {
 			return err
 		}
-		if dst.CanSet() && converted.Type().AssignableTo(dst.Type()) {
-			dst.Set(converted)
+	}
+
+	// Share references in shallow mode
+	if !ctx.config.DeepCopy && ctx.share(dst, src) {
+		return nil
+	}
+
+	// Share values below the deep copy depth by reference
+	if ctx.config.DeepCopyDepth > 0 && ctx.depth >= ctx.config.DeepCopyDepth && dst.CanSet() && dst.Type() == src.Type() {
+		dst.Set(src)
+		return nil
+	}
+
+	// Build fresh destinations with their registered constructor
+	if err := ctx.construct(dst); err != nil {
+		return err
+	}
+
+	return ctx.mapKind(dst, src)
+}
+
+// mapKind maps src into dst without applying custom converters, routing
+// the value through the built-in conversions and the kind-specific handlers.
+func (ctx *context) mapKind(dst, src reflect.Value) error {
+	// Built-in conversions (civil time, money, ...)
+	if handled, err := ctx.mapBuiltin(dst, src); handled {
+		return err
+	}
+
+	// Single values onto slices become one-element slices
+	if handled, err := ctx.wrapSingleElement(dst, src); handled {
+		return err
+	}
+
+	// Render Stringers into strings rather than converting them as numbers
+	if ctx.config.StringerFallback && ctx.mapStringer(dst, src) {
+		return nil
+	}
+
+	// Concrete values onto interfaces are copied as their own type
+	if dst.Kind() == reflect.Interface && src.Kind() != reflect.Interface && dst.CanSet() && src.Type().Implements(dst.Type()) {
+		cp := reflect.New(src.Type()).Elem()
+		if err := ctx.mapKind(cp, src); err != nil {
+			return err
 		}
+		dst.Set(cp)
 		return nil
 	}
 
@@ -237,6 +448,10 @@ func (ctx *context) mapStruct(dst, src reflect.Value) error {
 		return ctx.mapStruct(dst.Elem(), src)
 	}
 
+	if dst.Kind() == reflect.Map {
+		return ctx.structToMap(dst, src)
+	}
+
 	if dst.Kind() != reflect.Struct {
 		return nil
 	}
@@ -249,86 +464,201 @@ func (ctx *context) mapStruct(dst, src reflect.Value) error {
 		return nil
 	}
 
-	srcType := src.Type()
-	dstType := dst.Type()
+	// Unexported source fields are only exposed through their address
+	if ctx.config.AllowPrivateFields && !src.CanAddr() {
+		addressable := reflect.New(src.Type()).Elem()
+		addressable.Set(src)
+		src = addressable
+	}
 
-	for i := 0; i < src.NumField(); i++ {
-		srcField := srcType.Field(i)
+	plan := ctx.structPlan(src.Type(), dst.Type())
 
-		// Skip unexported fields if configured
-		if ctx.config.IgnoreUnexported && srcField.PkgPath != "" && !srcField.Anonymous {
-			continue
+	// Re-emit unknown fields captured by a previous mapping first, so that
+	// regularly matched fields take precedence.
+	if plan.srcOverflow >= 0 {
+		if err := ctx.emitOverflow(dst, src.Field(plan.srcOverflow), plan.dstOverflow); err != nil {
+			return err
 		}
+	}
+
+	for _, field := range plan.fields {
+		ctx.pushPath(field.srcName)
+		ctx.mapField(dst, src, plan, field)
+		ctx.popPath()
+	}
+	ctx.mapMembers(dst, src, plan.members)
+	ctx.reportMissing(dst, src, plan.missing)
+	ctx.applyDefaults(dst, plan.defaults)
+
+	if err := ctx.afterMap(dst, src); err != nil {
+		return err
+	}
+	ctx.pruneEmpty(dst)
+	ctx.validate(dst)
+	return nil
+}
+
+// mapField maps a single planned source field into its destination field.
+// Errors are passed through the configured ErrorHandler and collected.
+func (ctx *context) mapField(dst, src reflect.Value, plan *structPlan, field fieldPlan) {
+	// Fields hidden from the caller are neither mapped nor captured
+	if !ctx.visible(field.srcRoles, field.dstRoles) {
+		return
+	}
+
+	srcValue, err := src.FieldByIndexErr(field.srcIndex)
+	if ctx.config.AllowPrivateFields {
+		srcValue = exposeField(srcValue)
+	}
+	if err == nil && !ctx.conditionsPass(src, srcValue) {
+		return
+	}
+	if field.required && (err != nil || isZero(ctx.config, srcValue)) {
+		ctx.fieldError(ErrRequiredField, reflect.Value{}, srcValue, field.srcName, field.dstName)
+		return
+	}
+	if err != nil {
+		// A nil pointer along a dotted source path leaves the field unmapped
+		return
+	}
+	if field.omitEmpty && isZero(ctx.config, srcValue) {
+		return
+	}
 
-		// Tag filtering
-		if ctx.config.TagName != "" {
-			tag := srcField.Tag.Get(ctx.config.TagName)
-			if tag == "" || tag == "-" {
-				continue
+	if field.dstIndex == nil {
+		if plan.dstOverflow >= 0 && !ctx.ignoredField("") {
+			if err := ctx.captureOverflow(dst.Field(plan.dstOverflow), field.srcName, srcValue); err != nil {
+				ctx.addError(ctx.mapError("mapStruct", dst.Field(plan.dstOverflow), srcValue, field.srcName, "", err))
 			}
 		}
+		return
+	}
 
-		srcValue := src.Field(i)
-		dstFieldName := ctx.getDestFieldName(srcField)
-		dstField, found := ctx.findDstField(dstType, dstFieldName)
-		if !found {
-			continue
-		}
+	// Leave the destination branch alone, unallocated, for zero structs
+	if ctx.config.SkipZeroStructs && srcValue.Kind() == reflect.Struct && srcValue.Type() != timeType && isZero(ctx.config, srcValue) {
+		return
+	}
 
-		dstValue := dst.FieldByIndex(dstField.Index)
-		if !dstValue.CanSet() {
-			continue
-		}
+	dstValue := fieldByIndexAlloc(dst, field.dstIndex)
+	if ctx.config.AllowPrivateFields {
+		dstValue = exposeField(dstValue)
+	}
+	if !dstValue.CanSet() {
+		return
+	}
 
-		// Zero field if configured
-		if ctx.config.ZeroFields && srcValue.IsZero() {
-			dstValue.Set(reflect.Zero(dstValue.Type()))
-			continue
+	// Load atomic source fields before inspecting them
+	if field.srcAtomic {
+		loaded, err := ctx.loadAtomicField(srcValue)
+		if err != nil {
+			ctx.addError(ctx.mapError("mapStruct", dstValue, srcValue, field.srcName, field.dstName, err))
+			return
 		}
+		srcValue = loaded
+	}
 
-		// Recursive field mapping
-		if err := ctx.mapValue(dstValue, srcValue); err != nil {
-			if ctx.config.ErrorHandler != nil {
-				err = ctx.config.ErrorHandler(err, srcField.Name, dstField.Name)
-			}
-			if err != nil {
-				ctx.addError(err)
-			}
+	// Keep the untransformed source value alongside the mapped one
+	if field.keepRaw != nil {
+		if err := ctx.captureRaw(dst, srcValue, field.keepRaw); err != nil {
+			ctx.fieldError(err, dstValue, srcValue, field.srcName, field.dstName)
 		}
 	}
 
-	return nil
+	ctx.setField(dstValue, srcValue, field)
+}
+
+// setField maps a source field value into its settable destination field,
+// applying the ignore list, the merge mode and the field's zeroing, translation, atomic, unit and converter rules. Errors
+// are passed through the configured ErrorHandler and collected.
+func (ctx *context) setField(dstValue, srcValue reflect.Value, field fieldPlan) {
+	// Never write ignored fields
+	if ctx.ignoredField(field.dstName) {
+		return
+	}
+
+	// Keep destination values protected by the merge mode
+	if ctx.skipMerge(dstValue, srcValue) {
+		return
+	}
+
+	// Zero field if configured
+	if ctx.config.ZeroFields && isZero(ctx.config, srcValue) {
+		dstValue.Set(reflect.Zero(dstValue.Type()))
+		return
+	}
+
+	// Localize user-facing values before mapping them
+	if field.i18nKey != "" {
+		translated, err := ctx.translate(srcValue, field.i18nKey)
+		if err != nil {
+			ctx.fieldError(err, dstValue, srcValue, field.srcName, field.dstName)
+			return
+		}
+		srcValue = translated
+	}
+
+	// Recursive field mapping
+	var err error
+	switch {
+	case field.dstAtomic:
+		err = ctx.storeAtomicField(dstValue, srcValue)
+	case field.unit != nil:
+		err = ctx.mapUnit(dstValue, srcValue, field.unit)
+	case field.numeric && field.converter == "":
+		err = ctx.mapNumericString(dstValue, srcValue)
+	default:
+		handled := false
+		if field.converter != "" {
+			handled, err = ctx.applyNamedConverter(field.converter, dstValue, srcValue)
+		} else if converter, ok := ctx.fieldConverter(); ok {
+			handled, err = ctx.applyConverter(converter, dstValue, srcValue)
+		}
+		if !handled && err == nil && !(field.shallow && ctx.share(dstValue, srcValue)) {
+			err = ctx.mapValue(dstValue, srcValue)
+		}
+	}
+	if err != nil {
+		ctx.fieldError(err, dstValue, srcValue, field.srcName, field.dstName)
+	}
 }
 
 // mapMap performs mapping between two maps, recursively mapping both keys
 // and values. It creates a new destination map if needed.
 func (ctx *context) mapMap(dst, src reflect.Value) error {
+	if src.Kind() == reflect.Map && dst.Kind() != reflect.Map {
+		return ctx.mapToStruct(dst, src)
+	}
 	if src.Kind() != reflect.Map || dst.Kind() != reflect.Map {
 		return nil
 	}
 
-	if dst.IsNil() && dst.CanSet() {
-		dst.Set(reflect.MakeMap(dst.Type()))
+	if dst.IsNil() {
+		if !dst.CanSet() {
+			return nil
+		}
+		dst.Set(reflect.MakeMapWithSize(dst.Type(), src.Len()))
 	}
 
+	// Map every entry through one reusable key and value slot. SetMapIndex
+	// copies the slots, and they are zeroed between entries, so pointer
+	// values are allocated exactly once per entry and mapped in place.
+	newKey := reflect.New(dst.Type().Key()).Elem()
+	newVal := reflect.New(dst.Type().Elem()).Elem()
+
 	iter := src.MapRange()
 	for iter.Next() {
-		key := iter.Key()
-		value := iter.Value()
-
-		newKey := reflect.New(dst.Type().Key()).Elem()
-		newVal := reflect.New(dst.Type().Elem()).Elem()
-
-		if err := ctx.mapValue(newKey, key); err != nil {
-			ctx.addError(err)
-			continue
-		}
-		if err := ctx.mapValue(newVal, value); err != nil {
-			ctx.addError(err)
-			continue
+		newKey.SetZero()
+		newVal.SetZero()
+
+		ctx.pushKey(iter.Key())
+		if err := ctx.mapValue(newKey, iter.Key()); err != nil {
+			ctx.addError(ctx.mapError("mapMap", newKey, iter.Key(), "", "", err))
+		} else if err := ctx.mapValue(newVal, iter.Value()); err != nil {
+			ctx.addError(ctx.mapError("mapMap", newVal, iter.Value(), "", "", err))
+		} else {
+			dst.SetMapIndex(newKey, newVal)
 		}
-
-		dst.SetMapIndex(newKey, newVal)
+		ctx.popPath()
 	}
 
 	return nil
@@ -338,29 +668,135 @@ func (ctx *context) mapMap(dst, src reflect.Value) error {
 // new destination slice if necessary and maps elements recursively.
 func (ctx *context) mapSlice(dst, src reflect.Value) error {
 	if dst.Kind() != reflect.Slice && dst.Kind() != reflect.Array {
-		return nil
+		return ctx.mapSingleElement(dst, src)
 	}
 
-	srcLen := src.Len()
+	srcElem, dstElem := src.Type().Elem(), dst.Type().Elem()
+	if !ctx.elementsMappable(srcElem, dstElem) {
+		return ctx.mapError("mapSlice", dst, src, "", "", fmt.Errorf("%w: cannot map elements of %s onto %s", ErrTypeMismatch, srcElem, dstElem))
+	}
 
-	if dst.Kind() == reflect.Slice {
-		if dst.IsNil() || dst.Len() < srcLen {
-			if dst.CanSet() {
-				dst.Set(reflect.MakeSlice(dst.Type(), srcLen, srcLen))
-			}
+	srcLen, err := ctx.sliceLength(dst, src)
+	if err != nil {
+		return err
+	}
+
+	if dst.Kind() == reflect.Slice && dst.CanSet() {
+		switch {
+		case dst.IsNil() || dst.Len() < srcLen:
+			dst.Set(reflect.MakeSlice(dst.Type(), srcLen, srcLen))
+		case dst.Len() > srcLen:
+			// Drop stale trailing elements from a previously populated destination
+			dst.Set(dst.Slice(0, srcLen))
 		}
 	}
 
 	length := min(dst.Len(), srcLen)
 	for i := 0; i < length; i++ {
+		ctx.pushIndex(i)
 		if err := ctx.mapValue(dst.Index(i), src.Index(i)); err != nil {
-			ctx.addError(fmt.Errorf("slice index %d: %w", i, err))
+			index := "[" + strconv.Itoa(i) + "]"
+			ctx.addError(ctx.mapError("mapSlice", dst.Index(i), src.Index(i), index, index, err))
+		}
+		ctx.popPath()
+	}
+
+	if dst.Kind() == reflect.Array {
+		// Clear stale elements of a longer destination array, and report
+		// source elements that do not fit
+		for i := length; i < dst.Len(); i++ {
+			if dst.Index(i).CanSet() {
+				dst.Index(i).SetZero()
+			}
+		}
+		if srcLen > dst.Len() {
+			return ctx.mapError("mapSlice", dst, src, "", "", fmt.Errorf("%w: %d elements do not fit in %s", ErrTypeMismatch, srcLen, dst.Type()))
 		}
 	}
 
 	return nil
 }
 
+// mapSingleElement maps a one-element slice or array onto a single
+// destination value. Empty sources leave the destination untouched.
+func (ctx *context) mapSingleElement(dst, src reflect.Value) error {
+	if dst.Kind() == reflect.Interface || !kindsCompatible(src.Type().Elem(), dst.Type()) {
+		return nil
+	}
+
+	switch src.Len() {
+	case 0:
+		return nil
+	case 1:
+		return ctx.mapValue(dst, src.Index(0))
+	default:
+		return ctx.mapError("mapSlice", dst, src, "", "", fmt.Errorf("%w: cannot map %d elements onto a single %s", ErrTypeMismatch, src.Len(), dst.Type()))
+	}
+}
+
+// wrapSingleElement maps a single source value onto a destination slice
+// as its only element. It reports whether the pair of values was handled.
+func (ctx *context) wrapSingleElement(dst, src reflect.Value) (bool, error) {
+	if dst.Kind() != reflect.Slice || !dst.CanSet() {
+		return false, nil
+	}
+	switch src.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Ptr, reflect.Interface:
+		return false, nil
+	}
+	if src.Type().ConvertibleTo(dst.Type()) || !kindsCompatible(src.Type(), dst.Type().Elem()) {
+		return false, nil
+	}
+
+	dst.Set(reflect.MakeSlice(dst.Type(), 1, 1))
+	return true, ctx.mapValue(dst.Index(0), src)
+}
+
+// elementsMappable reports whether elements of type srcElem can be mapped
+// onto elements of type dstElem, by the regular rules or a converter or
+// built-in conversion that may apply to them.
+func (ctx *context) elementsMappable(srcElem, dstElem reflect.Type) bool {
+	if dstElem.Kind() == reflect.Interface || kindsCompatible(srcElem, dstElem) {
+		return true
+	}
+	if _, ok := ctx.config.CustomConverters[srcElem]; ok {
+		return true
+	}
+	if _, ok := ctx.config.ContextConverters[srcElem]; ok {
+		return true
+	}
+	if _, ok := ctx.config.PairConverters[TypePair{Source: srcElem, Destination: dstElem}]; ok {
+		return true
+	}
+	cfg := ctx.config
+	return cfg.CivilTime || cfg.MoneySupport || cfg.StringConversion || ctx.timeConversions()
+}
+
+// mapError wraps an error raised while mapping src onto dst in a MapError
+// carrying the current path, within the error type of its category. Errors
+// that already are MapErrors, raised deeper in the graph, are returned
+// unchanged.
+func (ctx *context) mapError(op string, dst, src reflect.Value, srcField, dstField string, err error) error {
+	if _, ok := err.(CategorizedError); ok {
+		return err
+	}
+	mapErr := &MapError{
+		Err:       err,
+		SrcField:  srcField,
+		DstField:  dstField,
+		Depth:     ctx.depth,
+		Operation: op,
+		Path:      ctx.fieldPath(),
+	}
+	if src.IsValid() {
+		mapErr.SrcType = src.Type().String()
+	}
+	if dst.IsValid() {
+		mapErr.DstType = dst.Type().String()
+	}
+	return categorize(mapErr)
+}
+
 // mapInterface handles mapping between interface values, extracting
 // and mapping the underlying concrete types.
 func (ctx *context) mapInterface(dst, src reflect.Value) error {
@@ -368,6 +804,10 @@ func (ctx *context) mapInterface(dst, src reflect.Value) error {
 		return nil
 	}
 
+	if handled, err := ctx.mapTypedNil(dst, src); handled {
+		return err
+	}
+
 	if dst.Kind() != reflect.Interface {
 		return ctx.mapValue(dst, src.Elem())
 	}
@@ -399,11 +839,22 @@ func (ctx *context) mapBasic(dst, src reflect.Value) error {
 
 	if src.Type() == dst.Type() {
 		dst.Set(src)
+		ctx.internString(dst)
 		return nil
 	}
 
+	// Parse and format strings instead of rune conversions
+	if ctx.config.StringConversion {
+		if handled, err := ctx.convertString(dst, src); handled {
+			return err
+		}
+	}
+
 	if src.Type().ConvertibleTo(dst.Type()) {
 		dst.Set(src.Convert(dst.Type()))
+		ctx.auditBasicConversion(src.Type(), dst.Type())
+		ctx.warnLossy(dst, src)
+		ctx.internString(dst)
 		return nil
 	}
 
@@ -411,18 +862,15 @@ func (ctx *context) mapBasic(dst, src reflect.Value) error {
 }
 
 // getDestFieldName determines the destination field name using
-// struct tags, configuration options, or a custom field name mapper.
+// explicit field mappings, struct tags, configuration options, or a custom
+// field name mapper.
 func (ctx *context) getDestFieldName(srcField reflect.StructField) string {
-	if ctx.config.TagName != "" {
-		if tag := srcField.Tag.Get(ctx.config.TagName); tag != "" && tag != "-" {
-			return tag
-		}
+	if name, ok := ctx.config.FieldMappings[srcField.Name]; ok {
+		return name
 	}
 
-	if ctx.config.UseJSONTag {
-		if tag := srcField.Tag.Get("json"); tag != "" && tag != "-" {
-			return tag
-		}
+	if tag := ctx.tagName(srcField); tag != "" {
+		return tag
 	}
 
 	if ctx.config.FieldNameMapper != nil {
@@ -432,18 +880,40 @@ func (ctx *context) getDestFieldName(srcField reflect.StructField) string {
 	return srcField.Name
 }
 
-// findDstField locates the destination field in the target struct
-// using case-sensitive or case-insensitive matching according to configuration.
+// findDstField locates the destination field in the target struct by
+// field name, then by encoding tag name, then by name with prefixes and
+// suffixes stripped, using case-sensitive or case-insensitive matching
+// according to configuration.
 func (ctx *context) findDstField(dstType reflect.Type, fieldName string) (reflect.StructField, bool) {
 	if field, found := dstType.FieldByName(fieldName); found {
-		return field, true
+		return field, !ctx.excludedField(field)
+	}
+
+	// Encoding tags name fields on both sides
+	if ctx.config.hasEncodingTags() {
+		for i := 0; i < dstType.NumField(); i++ {
+			field := dstType.Field(i)
+			name := ctx.encodingTagName(field)
+			if name != "" && (name == fieldName || (!ctx.config.CaseSensitive && reflectutil.EqualFold(name, fieldName))) {
+				return field, !ctx.excludedField(field)
+			}
+		}
 	}
 
 	if !ctx.config.CaseSensitive {
 		for i := 0; i < dstType.NumField(); i++ {
 			field := dstType.Field(i)
 			if reflectutil.EqualFold(field.Name, fieldName) {
-				return field, true
+				return field, !ctx.excludedField(field)
+			}
+		}
+	}
+
+	if ctx.config.hasAffixes() {
+		for i := 0; i < dstType.NumField(); i++ {
+			field := dstType.Field(i)
+			if ctx.affixMatch(fieldName, field.Name) {
+				return field, !ctx.excludedField(field)
 			}
 		}
 	}
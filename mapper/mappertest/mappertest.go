@@ -0,0 +1,82 @@
+// Package mappertest checks declared invariants of a mapping against
+// randomly generated sources, using testing/quick. It complements example
+// based tests: instead of asserting the output for a few inputs, a test
+// states what must hold for every input, e.g. "ID is always preserved" or
+// "Email is lowercased", and lets the generator look for counterexamples.
+//
+// Example:
+//
+//	func TestUserMapping(t *testing.T) {
+//	    users := mapper.New[User, UserDTO](mapper.WithFieldConverter("Email", lower))
+//	    mappertest.Check(t, users, nil,
+//	        mappertest.Preserved("ID preserved",
+//	            func(u User) int { return u.ID },
+//	            func(d UserDTO) int { return d.ID }),
+//	        mappertest.Invariant[User, UserDTO]{
+//	            Name:  "email lowercased",
+//	            Holds: func(u User, d UserDTO) bool { return d.Email == strings.ToLower(u.Email) },
+//	        },
+//	    )
+//	}
+package mappertest
+
+import (
+	"errors"
+	"testing"
+	"testing/quick"
+
+	"github.com/fbarikzehi/gomap/mapper"
+)
+
+// Invariant is a named property that must hold between every source and
+// the destination it is mapped into.
+type Invariant[S, D any] struct {
+	Name  string
+	Holds func(src S, dst D) bool
+}
+
+// Preserved returns an invariant stating that the value selected from the
+// source by src equals the value selected from the destination by dst.
+func Preserved[S, D any, V comparable](name string, src func(S) V, dst func(D) V) Invariant[S, D] {
+	return Invariant[S, D]{
+		Name: name,
+		Holds: func(s S, d D) bool {
+			return src(s) == dst(d)
+		},
+	}
+}
+
+// Check maps random sources with m and reports through t every invariant
+// that does not hold, along with the source that broke it. Mapping errors
+// are reported as failures too. cfg controls the number of sources and how
+// they are generated, as for quick.Check; nil uses the quick defaults.
+// Sources are generated with testing/quick, so S must be a type quick can
+// generate: types with unexported fields or interfaces should implement
+// quick.Generator or be generated through cfg.Values.
+func Check[S, D any](t testing.TB, m *mapper.TypedMapper[S, D], cfg *quick.Config, invariants ...Invariant[S, D]) {
+	t.Helper()
+
+	for _, inv := range invariants {
+		var mapErr error
+		property := func(src S) bool {
+			dst, err := m.Map(src)
+			if err != nil {
+				mapErr = err
+				return false
+			}
+			return inv.Holds(src, dst)
+		}
+
+		err := quick.Check(property, cfg)
+		var counter *quick.CheckError
+		switch {
+		case err == nil:
+		case errors.As(err, &counter) && mapErr != nil:
+			t.Errorf("mappertest: %s: mapping %#v failed: %v", inv.Name, counter.In[0], mapErr)
+		case errors.As(err, &counter):
+			t.Errorf("mappertest: %s does not hold for %#v", inv.Name, counter.In[0])
+		default:
+			t.Errorf("mappertest: %s: %v", inv.Name, err)
+		}
+	}
+}
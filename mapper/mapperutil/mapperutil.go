@@ -0,0 +1,68 @@
+// Package mapperutil exposes a stable subset of the reflection helpers used
+// internally by the mapper, so custom converters and resolvers can apply the
+// same nil, tag and name-matching semantics as the mapper itself, and its
+// default zero-value rules.
+//
+// Example:
+//
+//	conv := func(v reflect.Value) (reflect.Value, error) {
+//	    if mapperutil.IsZeroValue(v) {
+//	        return reflect.ValueOf(""), nil
+//	    }
+//	    return reflect.ValueOf(fmt.Sprint(v.Interface())), nil
+//	}
+package mapperutil
+
+import (
+	"reflect"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// IsNillable reports whether values of the given kind can be nil
+// (channels, functions, interfaces, maps, pointers and slices).
+func IsNillable(k reflect.Kind) bool {
+	return reflectutil.IsNillable(k)
+}
+
+// IsNil reports whether v is invalid or a nil value of a nillable kind.
+// Unlike reflect.Value.IsNil it never panics.
+func IsNil(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	return reflectutil.IsNillable(v.Kind()) && v.IsNil()
+}
+
+// IsPointerLike reports whether values of the given kind are references
+// that the mapper tracks for circular reference detection.
+func IsPointerLike(k reflect.Kind) bool {
+	return reflectutil.IsPointerLike(k)
+}
+
+// IsBasicType reports whether the given kind is a basic type (booleans,
+// numbers and strings) that the mapper assigns or converts directly.
+func IsBasicType(k reflect.Kind) bool {
+	return reflectutil.IsBasicType(k)
+}
+
+// IsZeroValue reports whether v is the zero value of its type, as
+// reflect.Value.IsZero does; invalid values are zero. It ignores zero
+// checkers registered with WithZeroChecker and IsZero methods: use
+// Mapper.IsZero to apply a mapper's zero rules.
+func IsZeroValue(v reflect.Value) bool {
+	return reflectutil.IsZeroValue(v)
+}
+
+// FieldTag returns the value of the given tag key on a struct field. The
+// second result is false when the tag is missing, empty or "-", which the
+// mapper treats as "not mapped by tag".
+func FieldTag(field reflect.StructField, tagName string) (string, bool) {
+	return reflectutil.GetFieldTag(field, tagName)
+}
+
+// EqualFold reports whether two field names are equal under the mapper's
+// ASCII case-insensitive matching rules.
+func EqualFold(a, b string) bool {
+	return reflectutil.EqualFold(a, b)
+}
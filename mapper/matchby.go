@@ -0,0 +1,118 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements destination-driven field matching.
+package mapper
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// MatchBy selects which struct of a pair drives field matching.
+//
+// The zero value is MatchBySource.
+type MatchBy int
+
+const (
+	// MatchBySource looks up a destination field for every source field,
+	// by the source field's name, tag or field mapping.
+	MatchBySource MatchBy = iota
+
+	// MatchByDestination looks up a source field for every destination
+	// field, by the destination field's name or by the source field name
+	// or dotted path in its tag, e.g. `mapper:"Customer.Name"`. The tag
+	// key is the one set with WithTagName, or "mapper". Tags on source
+	// fields are not consulted, so wide destinations populated from narrow
+	// sources declare their mapping in one place.
+	MatchByDestination
+)
+
+// compileDestinationFields plans the fields of a struct type pair driven by
+// the destination fields, in their declaration order. Source fields left
+// unmatched are appended without a destination, for overflow capture and
+// plan inspection.
+func (ctx *context) compileDestinationFields(srcType, dstType reflect.Type, plan *structPlan) []fieldPlan {
+	var fields []fieldPlan
+	used := make(map[int]bool)
+
+	for i := 0; i < dstType.NumField(); i++ {
+		dstField := dstType.Field(i)
+		if i == plan.dstOverflow || (dstField.PkgPath != "" && !dstField.Anonymous) || ctx.ignoredType(dstField.Type) {
+			continue
+		}
+
+		srcName, ok := ctx.sourceNameOf(dstField)
+		if !ok || strings.Contains(srcName, ".") {
+			// Dotted paths are planned by compileSourcePaths
+			continue
+		}
+		srcField, found := ctx.findSrcField(srcType, srcName)
+		if !found || srcField.Index[0] == plan.srcOverflow || ctx.unmappableSource(srcField) {
+			continue
+		}
+
+		used[srcField.Index[0]] = true
+		fields = append(fields, ctx.pairFields(srcField.Name, srcField, srcField.Index, dstField, dstType, dstField.Index))
+	}
+
+	for i := 0; i < srcType.NumField(); i++ {
+		srcField := srcType.Field(i)
+		if used[i] || i == plan.srcOverflow || ctx.unmappableSource(srcField) {
+			continue
+		}
+		fields = append(fields, fieldPlan{
+			srcIndex:  srcField.Index,
+			srcName:   srcField.Name,
+			srcAtomic: ctx.hasTagOption(srcField, AtomicTagOption),
+			srcRoles:  ctx.fieldRoles(srcField),
+		})
+	}
+	return fields
+}
+
+// sourceNameOf returns the name or path of the source field populating
+// dstField: its explicit field mapping, its tag name or its own name. It
+// reports false for fields excluded with a "-" tag.
+func (ctx *context) sourceNameOf(dstField reflect.StructField) (string, bool) {
+	for srcName, dstName := range ctx.config.FieldMappings {
+		if dstName == dstField.Name && !strings.Contains(srcName, ".") {
+			return srcName, true
+		}
+	}
+
+	switch name := ctx.fieldTag(dstField).name; name {
+	case "-":
+		return "", false
+	case "":
+		return dstField.Name, true
+	default:
+		return name, true
+	}
+}
+
+// findSrcField locates the source field named name, as findDstField does
+// for destination fields: by exact name, by name with the configured
+// affixes stripped, then case-insensitively if configured.
+func (ctx *context) findSrcField(srcType reflect.Type, name string) (reflect.StructField, bool) {
+	if field, found := srcType.FieldByName(name); found {
+		return field, true
+	}
+
+	if ctx.config.hasAffixes() {
+		for i := 0; i < srcType.NumField(); i++ {
+			if field := srcType.Field(i); ctx.affixMatch(field.Name, name) {
+				return field, true
+			}
+		}
+	}
+
+	if !ctx.config.CaseSensitive {
+		for i := 0; i < srcType.NumField(); i++ {
+			if field := srcType.Field(i); reflectutil.EqualFold(field.Name, name) {
+				return field, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,272 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements one-to-many and many-to-one field rules.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SplitFunc splits one source field value into the values of several
+// destination fields, in the order the fields were declared.
+type SplitFunc func(src interface{}) ([]interface{}, error)
+
+// CombineFunc combines several source field values, in the order the
+// fields were declared, into the value of one destination field.
+type CombineFunc func(srcs []interface{}) (interface{}, error)
+
+// FieldSplit feeds several destination fields from one source field.
+type FieldSplit struct {
+	Source  string
+	Targets []string
+	Split   SplitFunc
+}
+
+// FieldCombine feeds one destination field from several source fields.
+type FieldCombine struct {
+	Sources []string
+	Target  string
+	Combine CombineFunc
+}
+
+// MemberResolver describes how a destination member is resolved from the
+// source. Resolvers are created with Combine or MapFrom and registered with
+// ForMember.
+type MemberResolver struct {
+	sources []string
+
+	// resolve combines the source values, judging fallbacks with the zero
+	// rules of the configuration the resolver is registered on
+	resolve func(cfg *Config, srcs []interface{}) (interface{}, error)
+}
+
+// ForMember resolves the target destination field with resolver instead of
+// name matching. It is the declarative form of WithFieldCombine.
+//
+// Example:
+//
+//	mapper.ForMember("Address", mapper.Combine(mapper.JoinWith(", "), "Street", "City", "Zip"))
+func ForMember(target string, resolver MemberResolver) Option {
+	return func(c *Config) {
+		WithFieldCombine(resolver.sources, target, func(srcs []interface{}) (interface{}, error) {
+			return resolver.resolve(c, srcs)
+		})(c)
+	}
+}
+
+// Combine resolves a member by combining the values of the source fields,
+// passed to combine in the order given.
+func Combine(combine CombineFunc, sources ...string) MemberResolver {
+	return MemberResolver{
+		sources: sources,
+		resolve: func(_ *Config, srcs []interface{}) (interface{}, error) {
+			return combine(srcs)
+		},
+	}
+}
+
+// MapFrom resolves a member from a single source field, which may be a
+// dotted path. The value is mapped with the regular rules.
+func MapFrom(source string) MemberResolver {
+	return MemberResolver{
+		sources: []string{source},
+		resolve: func(_ *Config, srcs []interface{}) (interface{}, error) {
+			return srcs[0], nil
+		},
+	}
+}
+
+// OrFrom returns a resolver that falls back to the source field when r
+// yields no usable value: an error, nil or a zero value under the mapper's
+// zero rules (see Mapper.IsZero). Fallbacks are tried in order.
+//
+// Example:
+//
+//	mapper.ForMember("Name", mapper.MapFrom("DisplayName").OrFrom("Username").OrValue("anonymous"))
+func (r MemberResolver) OrFrom(source string) MemberResolver {
+	n, resolve := len(r.sources), r.resolve
+	return MemberResolver{
+		sources: append(r.sources[:n:n], source),
+		resolve: func(cfg *Config, srcs []interface{}) (interface{}, error) {
+			if result, err := resolve(cfg, srcs[:n]); err == nil && usableValue(cfg, result) {
+				return result, nil
+			}
+			return srcs[n], nil
+		},
+	}
+}
+
+// OrValue returns a resolver that falls back to value when r yields no
+// usable value.
+func (r MemberResolver) OrValue(value interface{}) MemberResolver {
+	resolve := r.resolve
+	return MemberResolver{
+		sources: r.sources,
+		resolve: func(cfg *Config, srcs []interface{}) (interface{}, error) {
+			if result, err := resolve(cfg, srcs); err == nil && usableValue(cfg, result) {
+				return result, nil
+			}
+			return value, nil
+		},
+	}
+}
+
+// usableValue reports whether a resolved value is neither nil nor zero
+// under the zero rules of cfg.
+func usableValue(cfg *Config, v interface{}) bool {
+	return !isZero(cfg, reflect.ValueOf(v))
+}
+
+// JoinWith returns a CombineFunc that formats the source values with
+// fmt.Sprint and joins them with sep. Nil pointers and values formatting to
+// an empty string are left out, so optional parts do not leave dangling
+// separators.
+func JoinWith(sep string) CombineFunc {
+	return func(srcs []interface{}) (interface{}, error) {
+		parts := make([]string, 0, len(srcs))
+		for _, src := range srcs {
+			v := reflect.ValueOf(src)
+			for v.Kind() == reflect.Ptr && !v.IsNil() {
+				v = v.Elem()
+			}
+			if !v.IsValid() || v.Kind() == reflect.Ptr {
+				continue
+			}
+			if s := fmt.Sprint(v.Interface()); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, sep), nil
+	}
+}
+
+// computedField computes a destination field from a whole source struct
+// of type srcType. It is registered by Builder.ForField.
+type computedField struct {
+	srcType reflect.Type
+	target  string
+	compute func(src reflect.Value) (interface{}, error)
+}
+
+// memberPlan is a split, combine or computed rule resolved for a struct
+// type pair.
+type memberPlan struct {
+	srcIndexes [][]int
+	srcNames   []string
+	dstIndexes [][]int
+	dstFields  []reflect.StructField
+	split      SplitFunc
+	combine    CombineFunc
+	compute    func(src reflect.Value) (interface{}, error)
+}
+
+// compileMembers resolves the split and combine rules whose fields all
+// exist in the source and destination types, and the computed fields of
+// the source type. Field names may be dotted paths.
+func (ctx *context) compileMembers(srcType, dstType reflect.Type) []memberPlan {
+	var members []memberPlan
+
+	resolve := func(member *memberPlan, srcs, dsts []string) bool {
+		for _, name := range srcs {
+			field, index, found := ctx.resolvePath(srcType, name)
+			if !found || field.PkgPath != "" {
+				return false
+			}
+			member.srcIndexes = append(member.srcIndexes, index)
+			member.srcNames = append(member.srcNames, name)
+		}
+		for _, name := range dsts {
+			field, index, found := ctx.resolvePath(dstType, name)
+			if !found || field.PkgPath != "" {
+				return false
+			}
+			member.dstIndexes = append(member.dstIndexes, index)
+			member.dstFields = append(member.dstFields, field)
+		}
+		return true
+	}
+
+	for _, rule := range ctx.config.FieldSplits {
+		member := memberPlan{split: rule.Split}
+		if resolve(&member, []string{rule.Source}, rule.Targets) {
+			members = append(members, member)
+		}
+	}
+	for _, rule := range ctx.config.FieldCombines {
+		member := memberPlan{combine: rule.Combine}
+		if resolve(&member, rule.Sources, []string{rule.Target}) {
+			members = append(members, member)
+		}
+	}
+	for _, rule := range ctx.config.computed {
+		if rule.srcType != srcType {
+			continue
+		}
+		member := memberPlan{compute: rule.compute, srcNames: []string{rule.target}}
+		if resolve(&member, nil, []string{rule.target}) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// mapMembers applies the member rules of a struct plan. Their
+// errors are passed through the configured ErrorHandler and collected.
+func (ctx *context) mapMembers(dst, src reflect.Value, members []memberPlan) {
+	for _, member := range members {
+		values := make([]interface{}, len(member.srcIndexes))
+		for i, index := range member.srcIndexes {
+			if v, err := src.FieldByIndexErr(index); err == nil {
+				values[i] = v.Interface()
+			}
+		}
+
+		var (
+			results []interface{}
+			err     error
+		)
+		switch {
+		case member.compute != nil:
+			var result interface{}
+			result, err = member.compute(src)
+			err = callbackError(err)
+			results = []interface{}{result}
+		case member.split != nil:
+			results, err = member.split(values[0])
+			err = callbackError(err)
+			if err == nil && len(results) != len(member.dstIndexes) {
+				err = fmt.Errorf("%w: split of %s returned %d values for %d fields", ErrTypeMismatch, member.srcNames[0], len(results), len(member.dstIndexes))
+			}
+		default:
+			var result interface{}
+			result, err = member.combine(values)
+			err = callbackError(err)
+			results = []interface{}{result}
+		}
+		if err != nil {
+			ctx.memberError(err, member, dst, src)
+			continue
+		}
+
+		for i, result := range results {
+			dstValue := fieldByIndexAlloc(dst, member.dstIndexes[i])
+			if !dstValue.CanSet() {
+				continue
+			}
+			ctx.pushPath(member.srcNames[0])
+			ctx.setField(dstValue, reflect.ValueOf(result), fieldPlan{
+				srcName: member.srcNames[0],
+				dstName: member.dstFields[i].Name,
+			})
+			ctx.popPath()
+		}
+	}
+}
+
+// memberError reports an error of a split or combine rule.
+func (ctx *context) memberError(err error, member memberPlan, dst, src reflect.Value) {
+	ctx.pushPath(member.srcNames[0])
+	ctx.fieldError(err, dst, src, member.srcNames[0], member.dstFields[0].Name)
+	ctx.popPath()
+}
@@ -0,0 +1,60 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements merge modes for patching existing destinations.
+package mapper
+
+import "reflect"
+
+// MergeMode controls whether mapping a field may overwrite the value
+// already held by the destination.
+type MergeMode int
+
+const (
+	// MergeOverwrite writes every mapped field (the default).
+	MergeOverwrite MergeMode = iota
+
+	// MergeSkipExistingNonZero keeps destination fields that are already
+	// non-zero. Nested structs are merged field by field.
+	MergeSkipExistingNonZero
+
+	// MergeSkipZeroSource only writes fields whose source value is
+	// non-zero, so a sparsely populated source patches the destination.
+	MergeSkipZeroSource
+
+	// MergePatch applies HTTP PATCH semantics: nil pointer and unset
+	// Optional source fields leave the destination untouched, while set
+	// ones are written even when their value is zero.
+	MergePatch
+)
+
+// skipMerge reports whether the merge mode leaves the destination field
+// untouched for the given source value.
+func (ctx *context) skipMerge(dst, src reflect.Value) bool {
+	switch ctx.config.MergeMode {
+	case MergeSkipExistingNonZero:
+		if isStructLike(dst) {
+			// Merged field by field
+			return false
+		}
+		return !isZero(ctx.config, dst)
+	case MergeSkipZeroSource:
+		return isZero(ctx.config, src)
+	case MergePatch:
+		if !src.IsValid() || isUnsetOptional(src) {
+			return true
+		}
+		return (src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface) && src.IsNil()
+	}
+	return false
+}
+
+// isStructLike reports whether v is a struct (other than time.Time) or a
+// non-nil pointer to one.
+func isStructLike(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Struct && v.Type() != timeType
+}
@@ -0,0 +1,239 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the money adapter mapping between minor-unit money
+// structs, decimal strings and float fields.
+package mapper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultMoneyScale is the default number of minor-unit digits (e.g. cents).
+const DefaultMoneyScale = 2
+
+// RoundingMode selects how monetary values with more precision than the
+// configured scale are rounded into minor units.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest value, ties to even (banker's rounding).
+	RoundHalfEven RoundingMode = iota
+
+	// RoundHalfUp rounds to the nearest value, ties away from zero.
+	RoundHalfUp
+
+	// RoundDown truncates toward zero.
+	RoundDown
+
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+// Money is an amount expressed in minor units (e.g. cents) with an ISO 4217
+// currency code. Any struct whose only fields are an integer Amount and a
+// string Currency is treated the same way when money support is enabled.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// isMoneyType reports whether t has exactly the {Amount, Currency} shape.
+func isMoneyType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return false
+	}
+	amount, ok := t.FieldByName("Amount")
+	if !ok || amount.PkgPath != "" {
+		return false
+	}
+	currency, ok := t.FieldByName("Currency")
+	if !ok || currency.PkgPath != "" || currency.Type.Kind() != reflect.String {
+		return false
+	}
+	switch amount.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// mapMoney converts between money structs and decimal strings or floats.
+// It reports whether the pair of values was handled.
+func (ctx *context) mapMoney(dst, src reflect.Value) (bool, error) {
+	srcMoney := isMoneyType(src.Type())
+	dstMoney := isMoneyType(dst.Type())
+	if srcMoney == dstMoney {
+		return false, nil
+	}
+
+	scale := ctx.config.MoneyScale
+	mode := ctx.config.MoneyRounding
+
+	if srcMoney {
+		amount := src.FieldByName("Amount").Int()
+		currency := src.FieldByName("Currency").String()
+
+		switch dst.Kind() {
+		case reflect.String:
+			if dst.CanSet() {
+				s := formatMinorUnits(amount, scale)
+				if currency != "" {
+					s += " " + currency
+				}
+				dst.SetString(s)
+				ctx.auditConversion(src.Type(), dst.Type(), false)
+			}
+		case reflect.Float32, reflect.Float64:
+			if dst.CanSet() {
+				f, _ := strconv.ParseFloat(formatMinorUnits(amount, scale), 64)
+				dst.SetFloat(f)
+				ctx.auditConversion(src.Type(), dst.Type(), true)
+			}
+		default:
+			return false, nil
+		}
+		return true, nil
+	}
+
+	var (
+		decimal  string
+		currency string
+	)
+	switch src.Kind() {
+	case reflect.String:
+		decimal, currency, _ = strings.Cut(strings.TrimSpace(src.String()), " ")
+	case reflect.Float32, reflect.Float64:
+		decimal = strconv.FormatFloat(src.Float(), 'f', -1, src.Type().Bits())
+	default:
+		return false, nil
+	}
+
+	amount, err := parseMinorUnits(decimal, scale, mode)
+	if err != nil {
+		return true, err
+	}
+
+	if dst.CanSet() {
+		amountField := dst.FieldByName("Amount")
+		if amountField.OverflowInt(amount) {
+			return true, invalidValue(fmt.Errorf("%w: amount %d overflows %s", ErrTypeMismatch, amount, amountField.Type()))
+		}
+		amountField.SetInt(amount)
+		dst.FieldByName("Currency").SetString(strings.TrimSpace(currency))
+
+		// Digits beyond the scale were rounded away
+		_, frac, _ := strings.Cut(decimal, ".")
+		ctx.auditConversion(src.Type(), dst.Type(), len(strings.TrimRight(frac, "0")) > scale)
+	}
+	return true, nil
+}
+
+// formatMinorUnits renders an amount of minor units as a decimal string
+// with exactly scale fractional digits.
+func formatMinorUnits(amount int64, scale int) string {
+	neg := amount < 0
+	abs := uint64(amount)
+	if neg {
+		abs = -abs
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+
+	if neg {
+		return "-" + digits
+	}
+	return digits
+}
+
+// parseMinorUnits parses a decimal string into minor units, rounding any
+// digits beyond scale according to mode.
+func parseMinorUnits(s string, scale int, mode RoundingMode) (int64, error) {
+	invalid := invalidValue(fmt.Errorf("%w: invalid decimal amount %q", ErrTypeMismatch, s))
+
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	intPart, frac, _ := strings.Cut(s, ".")
+	if intPart == "" && frac == "" || !isDigits(intPart) || !isDigits(frac) {
+		return 0, invalid
+	}
+
+	var rest string
+	if len(frac) > scale {
+		frac, rest = frac[:scale], frac[scale:]
+	} else {
+		frac += strings.Repeat("0", scale-len(frac))
+	}
+
+	digits := strings.TrimLeft(intPart+frac, "0")
+	if digits == "" {
+		digits = "0"
+	}
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+
+	if roundsAway(rest, amount, mode) {
+		// Rejected for both signs, like the magnitude of math.MinInt64,
+		// which does not parse above either
+		if amount == math.MaxInt64 {
+			return 0, invalidValue(fmt.Errorf("%w: decimal amount %q overflows int64 minor units", ErrTypeMismatch, s))
+		}
+		amount++
+	}
+
+	if neg {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// roundsAway reports whether the discarded digits require the magnitude of
+// the retained amount to be incremented under the given rounding mode.
+func roundsAway(rest string, amount int64, mode RoundingMode) bool {
+	if strings.Trim(rest, "0") == "" {
+		return false
+	}
+
+	switch mode {
+	case RoundDown:
+		return false
+	case RoundUp:
+		return true
+	case RoundHalfUp:
+		return rest[0] >= '5'
+	default:
+		if rest[0] != '5' {
+			return rest[0] > '5'
+		}
+		if strings.Trim(rest[1:], "0") != "" {
+			return true
+		}
+		return amount%2 != 0
+	}
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
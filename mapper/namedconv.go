@@ -0,0 +1,34 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements converters named in struct tags.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConverterTagOption applies a converter registered with WithNamedConverter
+// to a field, e.g. `mapper:"price,converter=centsToDollars"`. The option may
+// be set on the source or the destination field; the source field's
+// converter wins when both carry one.
+const ConverterTagOption = "converter"
+
+// fieldConverterName returns the converter named on the source field, or
+// else on the destination field, or "".
+func (ctx *context) fieldConverterName(srcField, dstField reflect.StructField) string {
+	if name, ok := ctx.tagOptionValue(srcField, ConverterTagOption); ok {
+		return name
+	}
+	name, _ := ctx.tagOptionValue(dstField, ConverterTagOption)
+	return name
+}
+
+// applyNamedConverter runs the converter registered under name on src. A
+// name without a registered converter fails the field.
+func (ctx *context) applyNamedConverter(name string, dst, src reflect.Value) (bool, error) {
+	converter, ok := ctx.config.NamedConverters[name]
+	if !ok {
+		return true, fmt.Errorf("%w: converter %q is not registered", ErrUnsupportedType, name)
+	}
+	return ctx.applyConverter(converter, dst, src)
+}
@@ -0,0 +1,71 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the do-not-copy markers for resource handle types.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DoNotMapper is implemented by types that must never be copied by the
+// mapper, such as database handles, loggers or contexts. Mapping a value
+// of such a type fails with ErrDoNotMap.
+//
+// A struct type can also opt out without defining a method by declaring a
+// blank marker field tagged with `mapper:"-"`.
+//
+// Example:
+//
+//	type Pool struct{ ... }
+//
+//	func (*Pool) DoNotMap() {}
+//
+//	type Logger struct {
+//	    _ struct{} `mapper:"-"`
+//	    ...
+//	}
+type DoNotMapper interface {
+	DoNotMap()
+}
+
+// doNotMapMarker is the tag value of a blank struct field marking the
+// enclosing struct type as not copyable.
+const doNotMapMarker = "-"
+
+var doNotMapperType = reflect.TypeOf((*DoNotMapper)(nil)).Elem()
+
+// doNotMapCache caches the do-not-copy decision per type.
+var doNotMapCache sync.Map // map[reflect.Type]bool
+
+// isDoNotMap reports whether values of type t must not be copied.
+func isDoNotMap(t reflect.Type) bool {
+	if cached, ok := doNotMapCache.Load(t); ok {
+		return cached.(bool)
+	}
+
+	marked := t.Implements(doNotMapperType)
+	if !marked && t.Kind() != reflect.Interface && t.Kind() != reflect.Ptr {
+		marked = reflect.PointerTo(t).Implements(doNotMapperType)
+	}
+	if !marked && t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Name == "_" && f.Tag.Get(DefaultTagName) == doNotMapMarker {
+				marked = true
+				break
+			}
+		}
+	}
+
+	doNotMapCache.Store(t, marked)
+	return marked
+}
+
+// checkDoNotMap returns an ErrDoNotMap error if src holds a marked type.
+func checkDoNotMap(src reflect.Value) error {
+	if isDoNotMap(src.Type()) {
+		return fmt.Errorf("%w: %s", ErrDoNotMap, src.Type())
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements conversions of database/sql nullable types.
+package mapper
+
+import "reflect"
+
+// isNullableType reports whether t is one of the database/sql nullable
+// types (sql.NullString, sql.NullInt64, ..., sql.Null[T]): a struct holding
+// a value field followed by a Valid flag.
+func isNullableType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == "database/sql" &&
+		t.NumField() == 2 && t.Field(1).Name == "Valid" && t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// mapNullable converts between database/sql nullable types and plain or
+// pointer values. An invalid nullable maps onto a nil pointer or a zero
+// value; a nil pointer maps onto an invalid nullable. It reports whether
+// the value was handled.
+func (ctx *context) mapNullable(dst, src reflect.Value) (bool, error) {
+	srcNullable, dstNullable := isNullableType(src.Type()), isNullableType(dst.Type())
+	if !srcNullable && !dstNullable {
+		return false, nil
+	}
+	if !dst.CanSet() {
+		return true, nil
+	}
+
+	if srcNullable {
+		if !src.Field(1).Bool() {
+			if !ctx.config.IgnoreNilFields {
+				dst.SetZero()
+			}
+			return true, nil
+		}
+		src = src.Field(0)
+	}
+
+	if dstNullable {
+		for src.Kind() == reflect.Ptr {
+			if src.IsNil() {
+				if !ctx.config.IgnoreNilFields {
+					dst.SetZero()
+				}
+				return true, nil
+			}
+			src = src.Elem()
+		}
+		if err := ctx.mapValue(dst.Field(0), src); err != nil {
+			return true, err
+		}
+		dst.Field(1).SetBool(true)
+		return true, nil
+	}
+
+	// Valid values are stored into freshly allocated pointer destinations
+	if dst.Kind() == reflect.Ptr {
+		value := reflect.New(dst.Type().Elem())
+		if err := ctx.mapValue(value.Elem(), src); err != nil {
+			return true, err
+		}
+		dst.Set(value)
+		return true, nil
+	}
+	return true, ctx.mapValue(dst, src)
+}
@@ -0,0 +1,96 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements exact decimal formatting of numbers onto
+// json.Number and numeric string fields.
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// NumericTagOption formats numeric sources onto a string destination field
+// the way they are formatted onto json.Number, e.g. `mapper:",numeric"`.
+const NumericTagOption = "numeric"
+
+var (
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+	bigIntType     = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType   = reflect.TypeOf((*big.Float)(nil))
+)
+
+// formatNumeric formats an integer, float, *big.Int or *big.Float as a
+// decimal string without exponent. Floats use the shortest representation
+// that parses back to the same value, so 0.1 formats as "0.1" rather than
+// with float rounding artifacts. It reports false for other values, and
+// fails for NaN and infinities, which have no decimal form.
+func formatNumeric(v reflect.Value) (string, bool, error) {
+	switch v.Type() {
+	case bigIntType:
+		if v.IsNil() {
+			return "", false, nil
+		}
+		return v.Interface().(*big.Int).String(), true, nil
+	case bigFloatType:
+		if v.IsNil() {
+			return "", false, nil
+		}
+		f := v.Interface().(*big.Float)
+		if f.IsInf() {
+			return "", true, invalidValue(fmt.Errorf("%w: %v has no decimal form", ErrTypeMismatch, f))
+		}
+		return f.Text('f', -1), true, nil
+	}
+
+	switch {
+	case v.CanFloat():
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", true, invalidValue(fmt.Errorf("%w: %v has no decimal form", ErrTypeMismatch, f))
+		}
+	case !v.CanInt() && !v.CanUint():
+		return "", false, nil
+	}
+	s, _ := formatBasic(v)
+	return s, true, nil
+}
+
+// mapJSONNumber formats numeric sources onto json.Number destinations and
+// parses json.Number sources onto numeric destinations. It reports whether
+// the value was handled.
+func (ctx *context) mapJSONNumber(dst, src reflect.Value) (bool, error) {
+	if !dst.CanSet() {
+		return false, nil
+	}
+	switch {
+	case dst.Type() == jsonNumberType:
+		s, ok, err := formatNumeric(src)
+		if ok && err == nil {
+			dst.SetString(s)
+		}
+		return ok, err
+	case src.Type() == jsonNumberType && (dst.CanInt() || dst.CanUint() || dst.CanFloat()):
+		return ctx.parseString(dst, src.String())
+	}
+	return false, nil
+}
+
+// mapNumericString maps src onto a string field tagged with
+// NumericTagOption, following source pointers. Other sources and
+// destinations are mapped with the regular rules.
+func (ctx *context) mapNumericString(dst, src reflect.Value) error {
+	value := src
+	for value.Kind() == reflect.Ptr && value.Type() != bigIntType && value.Type() != bigFloatType && !value.IsNil() {
+		value = value.Elem()
+	}
+	if dst.Kind() == reflect.String {
+		if s, ok, err := formatNumeric(value); ok {
+			if err == nil {
+				dst.SetString(s)
+			}
+			return err
+		}
+	}
+	return ctx.mapValue(dst, src)
+}
@@ -0,0 +1,110 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the Optional wrapper for partial updates.
+package mapper
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Optional wraps a value that may be absent, as in the fields of an HTTP
+// PATCH payload. An unset Optional source leaves the destination untouched
+// under MergePatch and behaves like a nil pointer otherwise; a set one maps
+// its Value, even when that value is zero. Mapping onto an Optional sets it.
+//
+// When decoded from JSON, an Optional is set if its key is present, so a
+// patch can tell an omitted field from one explicitly set to null or zero.
+type Optional[T any] struct {
+	Value T
+	Set   bool
+}
+
+// Some returns an Optional set to v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Set: true}
+}
+
+// Get returns the value and whether it is set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Set
+}
+
+// MarshalJSON encodes the value, or null when unset.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON decodes the value and marks the Optional as set.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	return json.Unmarshal(data, &o.Value)
+}
+
+// optionalValue gives reflective access to an Optional's value and flag.
+func (o *Optional[T]) optionalValue() (value reflect.Value, set *bool) {
+	return reflect.ValueOf(&o.Value).Elem(), &o.Set
+}
+
+// optionalValuer is implemented by pointers to every Optional type.
+type optionalValuer interface {
+	optionalValue() (reflect.Value, *bool)
+}
+
+var optionalValuerType = reflect.TypeOf((*optionalValuer)(nil)).Elem()
+
+func isOptionalType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(optionalValuerType)
+}
+
+// optionalOf returns the value and flag of an Optional, copying it first
+// when it is not addressable.
+func optionalOf(v reflect.Value) (reflect.Value, *bool) {
+	if !v.CanAddr() {
+		cp := reflect.New(v.Type())
+		cp.Elem().Set(v)
+		v = cp.Elem()
+	}
+	return v.Addr().Interface().(optionalValuer).optionalValue()
+}
+
+// isUnsetOptional reports whether v is an Optional that is not set.
+func isUnsetOptional(v reflect.Value) bool {
+	if !v.IsValid() || !isOptionalType(v.Type()) {
+		return false
+	}
+	_, set := optionalOf(v)
+	return !*set
+}
+
+// mapOptional maps Optional sources onto their value and values onto
+// Optional destinations. It reports whether either side was an Optional.
+func (ctx *context) mapOptional(dst, src reflect.Value) (bool, error) {
+	srcOptional, dstOptional := isOptionalType(src.Type()), isOptionalType(dst.Type())
+	if !srcOptional && !dstOptional {
+		return false, nil
+	}
+	if !dst.CanSet() {
+		return true, nil
+	}
+
+	if srcOptional {
+		value, set := optionalOf(src)
+		if !*set {
+			if !ctx.config.IgnoreNilFields {
+				dst.SetZero()
+			}
+			return true, nil
+		}
+		src = value
+	}
+
+	if dstOptional {
+		value, set := optionalOf(dst)
+		*set = true
+		return true, ctx.mapValue(value, src)
+	}
+	return true, ctx.mapValue(dst, src)
+}
@@ -14,7 +14,10 @@
 //	)
 package mapper
 
-import "reflect"
+import (
+	"reflect"
+	"time"
+)
 
 // Option represents a functional option for configuring a Mapper instance.
 //
@@ -61,17 +64,71 @@ func WithIgnoreUnexported(ignore bool) Option {
 }
 
 // WithDeepCopy enables or disables deep copying of complex types such as
-// slices, maps, and nested structs.
+// slices, maps, and nested structs. Deep copying is the default. With
+// deep set to false, pointers, slices and maps are assigned directly when
+// the source and destination types are identical, sharing pointees,
+// backing arrays and maps with the source; values of different types,
+// including structs, are still mapped field by field. ShallowTagOption
+// selects the same behavior for single fields.
 //
 // Example:
 //
-//	mapper.Copy(&dst, src, mapper.WithDeepCopy(true))
+//	mapper.Copy(&dst, src, mapper.WithDeepCopy(false))
 func WithDeepCopy(deep bool) Option {
 	return func(c *Config) {
 		c.DeepCopy = deep
 	}
 }
 
+// WithDeepCopyUntilDepth deep-copies the first n levels of the source and
+// shares deeper values by reference whenever the source and destination
+// types are identical, e.g. for large graphs where only the top layers are
+// mutated downstream. Levels are counted like WithMaxDepth: the root is at
+// level 0, and every struct, pointer, slice, map or interface adds one, so
+// with n = 1 the fields of a root struct are shared. Values of different
+// types are still mapped, and registered converters still apply.
+//
+// Example:
+//
+//	// Copy the order and its item slice, share the items' product graphs
+//	mapper.Copy(&dst, order, mapper.WithDeepCopyUntilDepth(3))
+func WithDeepCopyUntilDepth(n int) Option {
+	return func(c *Config) {
+		c.DeepCopyDepth = n
+	}
+}
+
+// WithPruneEmpty sets the struct pointer fields of mapped destination
+// structs whose pointees are entirely zero back to nil, producing sparse
+// DTOs that serialize without empty objects. Pruning runs bottom-up once
+// each struct is mapped, after defaults and AfterMap hooks and before
+// validation. Empty maps and slices are kept unless the
+// EmptyCollectionPolicy is EmptyCollectionNil.
+//
+// Example:
+//
+//	mapper.Copy(&dto, user,
+//	    mapper.WithPruneEmpty(true),
+//	    mapper.WithEmptyCollectionPolicy(mapper.EmptyCollectionNil))
+func WithPruneEmpty(prune bool) Option {
+	return func(c *Config) {
+		c.PruneEmpty = prune
+	}
+}
+
+// WithEmptyCollectionPolicy sets how WithPruneEmpty treats empty maps and
+// slices. The default is EmptyCollectionKeep.
+//
+// Example:
+//
+//	mapper.Copy(&dto, user, mapper.WithPruneEmpty(true),
+//	    mapper.WithEmptyCollectionPolicy(mapper.EmptyCollectionNil))
+func WithEmptyCollectionPolicy(policy EmptyCollectionPolicy) Option {
+	return func(c *Config) {
+		c.EmptyCollectionPolicy = policy
+	}
+}
+
 // WithZeroFields configures whether destination fields should be zeroed
 // when the corresponding source field is a zero value.
 //
@@ -84,6 +141,27 @@ func WithZeroFields(zero bool) Option {
 	}
 }
 
+// WithZeroChecker registers a custom zero-value check for a given type,
+// overriding both an IsZero method on the type and reflect's structural
+// zero check wherever the mapper needs to decide whether a value is zero.
+//
+// Example:
+//
+//	// Treat a Money value as zero when its amount is zero, whatever the currency.
+//	mapper.Copy(&dst, src,
+//	    mapper.WithZeroFields(true),
+//	    mapper.WithZeroChecker(reflect.TypeOf(mapper.Money{}), func(v reflect.Value) bool {
+//	        return v.FieldByName("Amount").Int() == 0
+//	    }))
+func WithZeroChecker(typ reflect.Type, checker ZeroCheckerFunc) Option {
+	return func(c *Config) {
+		if c.ZeroCheckers == nil {
+			c.ZeroCheckers = make(map[reflect.Type]ZeroCheckerFunc)
+		}
+		c.ZeroCheckers[typ] = checker
+	}
+}
+
 // WithIgnoreNilFields configures whether nil pointer fields in the source
 // should be skipped during mapping.
 //
@@ -109,13 +187,19 @@ func WithCaseSensitive(sensitive bool) Option {
 }
 
 // WithJSONTag enables support for JSON struct tags ("json") when matching
-// source and destination fields.
+// source and destination fields. A source field is matched by its JSON
+// name, options such as omitempty aside, against destination field names
+// and then destination JSON names, so fields sharing a JSON name match
+// whatever their Go names.
 //
 // Example:
 //
 //	type Source struct {
 //	    Name string `json:"full_name"`
 //	}
+//	type Dest struct {
+//	    FullName string `json:"full_name,omitempty"`
+//	}
 //	mapper.Copy(&dst, src, mapper.WithJSONTag(true))
 func WithJSONTag(use bool) Option {
 	return func(c *Config) {
@@ -123,6 +207,148 @@ func WithJSONTag(use bool) Option {
 	}
 }
 
+// WithYAMLTag enables support for YAML struct tags ("yaml") when matching
+// source and destination fields, as WithJSONTag does for JSON tags. Tag
+// options such as omitempty, flow and inline are ignored. With both
+// enabled, a field's JSON name takes precedence over its YAML name.
+//
+// Example:
+//
+//	type Source struct {
+//	    Timeout string `yaml:"request_timeout"`
+//	}
+//	type Dest struct {
+//	    RequestTimeout string `yaml:"request_timeout,omitempty"`
+//	}
+//	mapper.Copy(&dst, src, mapper.WithYAMLTag(true))
+func WithYAMLTag(use bool) Option {
+	return func(c *Config) {
+		c.UseYAMLTag = use
+	}
+}
+
+// WithBSONTag enables support for BSON struct tags ("bson") when matching
+// source and destination fields, as WithJSONTag does for JSON tags, so
+// MongoDB documents map onto domain types. Tag options such as omitempty
+// and inline are ignored. JSON and YAML names, when enabled, take
+// precedence over BSON names. The mapper/bsonmap module converts the
+// MongoDB driver's types.
+//
+// Example:
+//
+//	type UserDocument struct {
+//	    ID   primitive.ObjectID `bson:"_id,omitempty"`
+//	    Name string             `bson:"display_name"`
+//	}
+//	type User struct {
+//	    ID          string `bson:"_id"`
+//	    DisplayName string `bson:"display_name"`
+//	}
+//	mapper.Copy(&user, doc, mapper.WithBSONTag(true), bsonmap.WithMongoTypes())
+func WithBSONTag(use bool) Option {
+	return func(c *Config) {
+		c.UseBSONTag = use
+	}
+}
+
+// WithEnumUnknownPolicy sets how values missing from an enum mapping table
+// registered with WithEnumMapping are mapped. The default fails the field
+// with ErrUnknownEnumValue.
+//
+// Example:
+//
+//	mapper.Copy(&dto, order, statusTable, mapper.WithEnumUnknownPolicy(mapper.EnumUnknownZero))
+func WithEnumUnknownPolicy(policy EnumUnknownPolicy) Option {
+	return func(c *Config) {
+		c.EnumUnknownPolicy = policy
+	}
+}
+
+// WithErrorValuePolicy sets how source values implementing error are
+// mapped. By default, string destinations receive the error message and
+// error destinations the error value itself.
+//
+// Example:
+//
+//	type Result struct{ Err error }
+//	type ResultDTO struct{ Err string }
+//	mapper.Copy(&dto, result, mapper.WithErrorValuePolicy(mapper.ErrorValueSkip))
+func WithErrorValuePolicy(policy ErrorValuePolicy) Option {
+	return func(c *Config) {
+		c.ErrorValuePolicy = policy
+	}
+}
+
+// WithTypedNilPolicy sets how a source interface holding a typed nil is
+// mapped. By default the destination is set to its zero value, so interface
+// destinations are nil rather than holding a nil pointer.
+//
+// Example:
+//
+//	mapper.Copy(&dst, src, mapper.WithTypedNilPolicy(mapper.TypedNilError))
+func WithTypedNilPolicy(policy TypedNilPolicy) Option {
+	return func(c *Config) {
+		c.TypedNilPolicy = policy
+	}
+}
+
+// WithSkipZeroStructs configures whether nested source structs that are
+// entirely zero are skipped, so they neither overwrite nor allocate the
+// corresponding destination branch. It suits sparse partial DTOs that use
+// struct values rather than pointers. time.Time fields are not affected.
+//
+// Example:
+//
+//	mapper.Copy(&user, patch, mapper.WithSkipZeroStructs(true))
+func WithSkipZeroStructs(skip bool) Option {
+	return func(c *Config) {
+		c.SkipZeroStructs = skip
+	}
+}
+
+// WithMergeMode sets how mapping treats values already present in the
+// destination, so an existing value can be patched without clobbering the
+// fields that are already set.
+//
+// Example:
+//
+//	// Fill in defaults without overwriting user-provided settings
+//	mapper.Copy(&settings, defaults, mapper.WithMergeMode(mapper.MergeSkipExistingNonZero))
+//
+//	// Apply only the fields present in a partial update
+//	mapper.Copy(&user, patch, mapper.WithMergeMode(mapper.MergeSkipZeroSource))
+//
+//	// Apply a PATCH payload of pointer and Optional fields
+//	mapper.Copy(&user, patch, mapper.WithMergeMode(mapper.MergePatch))
+func WithMergeMode(mode MergeMode) Option {
+	return func(c *Config) {
+		c.MergeMode = mode
+	}
+}
+
+// WithFlattenEmbedded enables flattening of embedded source structs. When
+// the destination has no field matching an embedded struct as a whole, the
+// fields it promotes are mapped individually, following Go's promotion
+// rules: shallower fields shadow deeper ones, and fields ambiguous at the
+// same depth are not mapped. Nil embedded pointers leave their fields
+// unmapped.
+//
+// Mapping flat sources onto destinations with embedded structs needs no
+// option, as promoted destination fields are matched by name.
+//
+// Example:
+//
+//	type Model struct{ ID int64; CreatedAt time.Time }
+//	type User struct{ Model; Name string }
+//	type UserRow struct{ ID int64; CreatedAt time.Time; Name string }
+//
+//	mapper.Copy(&row, user, mapper.WithFlattenEmbedded(true))
+func WithFlattenEmbedded(flatten bool) Option {
+	return func(c *Config) {
+		c.FlattenEmbedded = flatten
+	}
+}
+
 // WithCustomConverter registers a custom conversion function for a given type.
 // The converter is used when mapping a value of that specific type.
 //
@@ -146,6 +372,306 @@ func WithCustomConverter(typ reflect.Type, converter ConverterFunc) Option {
 	}
 }
 
+// WithContextConverter registers a converter for a specific type that
+// receives the context.Context passed to MapContext. It takes precedence
+// over a WithCustomConverter converter for the same type, which still
+// converts the values it declines with ErrSkipConversion.
+//
+// Example:
+//
+//	mapper.WithContextConverter(reflect.TypeOf(Money{}),
+//	    func(ctx context.Context, v reflect.Value) (reflect.Value, error) {
+//	        return reflect.ValueOf(formatMoney(v.Interface().(Money), localeFrom(ctx))), nil
+//	    })
+func WithContextConverter(typ reflect.Type, converter ContextConverterFunc) Option {
+	return func(c *Config) {
+		if c.ContextConverters == nil {
+			c.ContextConverters = make(map[reflect.Type]ContextConverterFunc)
+		}
+		c.ContextConverters[typ] = converter
+	}
+}
+
+// WithPairConverter registers a converter for values of type src mapped
+// onto destinations of type dst. Unlike WithCustomConverter, it leaves the
+// other destinations of src alone, so integrations can convert their own
+// types without taking over every string or time.Time field. It takes
+// precedence over per-type converters; values it declines with
+// ErrSkipConversion are converted by them or mapped as usual.
+//
+// Example:
+//
+//	mapper.WithPairConverter(reflect.TypeOf(time.Time{}), reflect.TypeOf(""),
+//	    func(v reflect.Value) (reflect.Value, error) {
+//	        return reflect.ValueOf(v.Interface().(time.Time).Format(time.RFC3339)), nil
+//	    })
+func WithPairConverter(src, dst reflect.Type, converter ConverterFunc) Option {
+	return func(c *Config) {
+		if c.PairConverters == nil {
+			c.PairConverters = make(map[TypePair]ConverterFunc)
+		}
+		c.PairConverters[TypePair{Source: src, Destination: dst}] = converter
+	}
+}
+
+// WithConstructorCtx registers a constructor for destination values of
+// type typ. Whenever a destination of that type is zero, including values
+// the mapper allocates for nil pointers, slice elements and map entries, it
+// is first set to the constructor's result and the source is then mapped
+// onto it. The constructor receives the context.Context passed to
+// MapContext, carrying request-scoped values such as the tenant or locale.
+//
+// Example:
+//
+//	mapper.WithConstructorCtx(reflect.TypeOf(Order{}), func(ctx context.Context) (any, error) {
+//	    return Order{TenantID: tenant.From(ctx), CreatedAt: time.Now()}, nil
+//	})
+func WithConstructorCtx(typ reflect.Type, constructor ConstructorFunc) Option {
+	return func(c *Config) {
+		if c.Constructors == nil {
+			c.Constructors = make(map[reflect.Type]ConstructorFunc)
+		}
+		c.Constructors[typ] = constructor
+	}
+}
+
+// WithSourceLocker registers lock hooks for a pointer source type. While a
+// value of that type is being copied, lock is held (typically a read lock)
+// and unlock is called once the value and everything reachable from it has
+// been mapped. Addressable struct values, such as embedded structs, are
+// locked through their address.
+//
+// Example:
+//
+//	mapper.Copy(&dst, cache,
+//	    mapper.WithSourceLocker(reflect.TypeOf(&Cache{}),
+//	        func(v any) { v.(*Cache).mu.RLock() },
+//	        func(v any) { v.(*Cache).mu.RUnlock() }))
+func WithSourceLocker(typ reflect.Type, lock, unlock LockFunc) Option {
+	return func(c *Config) {
+		if c.SourceLockers == nil {
+			c.SourceLockers = make(map[reflect.Type]SourceLocker)
+		}
+		c.SourceLockers[typ] = SourceLocker{Lock: lock, Unlock: unlock}
+	}
+}
+
+// WithFieldConverter registers a converter for a specific source field
+// path instead of a whole type. The path starts with the root source type
+// name and lists field names ("Order.Total", "Order.Customer.Name"); the
+// root segment may be omitted ("Total"). Slice, array and map roots are
+// named after their element type ("Item.Price" for an []Item root). Slice and map elements do not add
+// path segments, so "Order.Items.Price" matches the Price of every item.
+//
+// Field converters take precedence over type converters and may return
+// ErrSkipConversion to fall through to default mapping.
+//
+// Example:
+//
+//	mapper.Copy(&dto, order,
+//	    mapper.WithFieldConverter("Order.Status", func(v reflect.Value) (reflect.Value, error) {
+//	        return reflect.ValueOf(statusNames[v.Int()]), nil
+//	    }))
+func WithFieldConverter(path string, converter ConverterFunc) Option {
+	return func(c *Config) {
+		if c.FieldConverters == nil {
+			c.FieldConverters = make(map[string]ConverterFunc)
+		}
+		c.FieldConverters[path] = converter
+	}
+}
+
+// WithNamedConverter registers a converter under a name, so that fields
+// select it in their tag: `mapper:",converter=name"`. Unlike type
+// converters it only affects the tagged fields, and the conversion is
+// visible at the struct definition. Named converters take precedence over
+// field and type converters and may return ErrSkipConversion to fall
+// through to default mapping.
+//
+// Example:
+//
+//	type ProductDTO struct {
+//	    Price float64 `mapper:",converter=centsToDollars"`
+//	}
+//
+//	mapper.Copy(&dto, product,
+//	    mapper.WithNamedConverter("centsToDollars", func(v reflect.Value) (reflect.Value, error) {
+//	        return reflect.ValueOf(float64(v.Int()) / 100), nil
+//	    }))
+func WithNamedConverter(name string, converter ConverterFunc) Option {
+	return func(c *Config) {
+		if c.NamedConverters == nil {
+			c.NamedConverters = make(map[string]ConverterFunc)
+		}
+		c.NamedConverters[name] = converter
+	}
+}
+
+// WithFieldMapping maps differently named fields by an explicit table of
+// source field names to destination field names. It is useful for types
+// whose struct tags cannot be edited, such as generated or vendor structs.
+// Entries take precedence over tags and the field name mapper; the
+// table is merged with previously registered mappings.
+//
+// Example:
+//
+//	mapper.Copy(&dst, src,
+//	    mapper.WithFieldMapping(map[string]string{"FullName": "Name", "YearsOld": "Age"}))
+func WithFieldMapping(mappings map[string]string) Option {
+	return func(c *Config) {
+		if c.FieldMappings == nil {
+			c.FieldMappings = make(map[string]string, len(mappings))
+		}
+		for src, dst := range mappings {
+			c.FieldMappings[src] = dst
+		}
+	}
+}
+
+// WithIgnoreFields excludes fields from mapping, regardless of name
+// matching, converters or member rules. A pattern without a dot matches a
+// source or destination field name at any depth; a dotted pattern matches
+// the field path relative to the root. Segments may use path.Match globs.
+// Destination fields tagged `mapper:"-"` are always excluded.
+//
+// Example:
+//
+//	mapper.Copy(&dto, user, mapper.WithIgnoreFields("Password", "Secret*", "Audit.*"))
+func WithIgnoreFields(patterns ...string) Option {
+	return func(c *Config) {
+		c.IgnoreFields = append(c.IgnoreFields, patterns...)
+	}
+}
+
+// WithIgnoreTypes skips every field of the given types, or of pointers to
+// them, on either side of a mapping. Types are matched by the declared field
+// type, so listing an interface skips fields declared with that interface.
+// It keeps contexts, loggers and database handles from being copied.
+//
+// Example:
+//
+//	mapper.WithIgnoreTypes(
+//	    reflect.TypeOf((*context.Context)(nil)).Elem(),
+//	    reflect.TypeOf(sql.DB{}),
+//	)
+func WithIgnoreTypes(types ...reflect.Type) Option {
+	return func(c *Config) {
+		if c.IgnoreTypes == nil {
+			c.IgnoreTypes = make(map[reflect.Type]bool)
+		}
+		for _, t := range types {
+			c.IgnoreTypes[t] = true
+		}
+	}
+}
+
+// WithFieldSplit fans one source field out into several destination
+// fields. The split function receives the source field value and returns
+// one value per target field, which are then mapped with the regular rules.
+// Field names may be dotted paths. The rule applies to every struct pair
+// that has all of the named fields.
+//
+// Example:
+//
+//	mapper.WithFieldSplit("FullName", []string{"FirstName", "LastName"},
+//	    func(src any) ([]any, error) {
+//	        first, last, _ := strings.Cut(src.(string), " ")
+//	        return []any{first, last}, nil
+//	    })
+func WithFieldSplit(source string, targets []string, split SplitFunc) Option {
+	return func(c *Config) {
+		c.FieldSplits = append(c.FieldSplits, FieldSplit{Source: source, Targets: targets, Split: split})
+	}
+}
+
+// WithFieldCombine combines several source fields into one destination
+// field, the reverse of WithFieldSplit. The combine function receives the
+// source field values in order and returns the destination value.
+//
+// Example:
+//
+//	mapper.WithFieldCombine([]string{"FirstName", "LastName"}, "FullName",
+//	    func(srcs []any) (any, error) {
+//	        return srcs[0].(string) + " " + srcs[1].(string), nil
+//	    })
+func WithFieldCombine(sources []string, target string, combine CombineFunc) Option {
+	return func(c *Config) {
+		c.FieldCombines = append(c.FieldCombines, FieldCombine{Sources: sources, Target: target, Combine: combine})
+	}
+}
+
+// WithMatchBy selects which struct drives field matching. With
+// MatchByDestination, each destination field names the source field or
+// dotted source path populating it in its tag, so mappings onto a wide
+// destination are declared on the destination type alone.
+//
+// Example:
+//
+//	type OrderView struct {
+//	    ID           string
+//	    CustomerName string `mapper:"Customer.Name"`
+//	    Total        int64  `mapper:"AmountCents"`
+//	}
+//	mapper.Copy(&view, order, mapper.WithMatchBy(mapper.MatchByDestination))
+func WithMatchBy(by MatchBy) Option {
+	return func(c *Config) {
+		c.MatchBy = by
+	}
+}
+
+// WithSourcePrefix strips the first matching prefix from source field names
+// that do not match a destination field as they are, so entity fields with
+// column prefixes match plain destination fields.
+//
+// Example:
+//
+//	// DBUserName → UserName
+//	mapper.Copy(&dst, entity, mapper.WithSourcePrefix("DB"))
+func WithSourcePrefix(prefixes ...string) Option {
+	return func(c *Config) {
+		c.SourcePrefixes = append(c.SourcePrefixes, prefixes...)
+	}
+}
+
+// WithSourceSuffix strips the first matching suffix from source field names
+// that do not match a destination field as they are.
+//
+// Example:
+//
+//	// UserNameCol → UserName
+//	mapper.Copy(&dst, entity, mapper.WithSourceSuffix("Col"))
+func WithSourceSuffix(suffixes ...string) Option {
+	return func(c *Config) {
+		c.SourceSuffixes = append(c.SourceSuffixes, suffixes...)
+	}
+}
+
+// WithDestPrefix strips the first matching prefix from destination field
+// names when matching source fields that have no exact counterpart.
+//
+// Example:
+//
+//	// UserName → DBUserName
+//	mapper.Copy(&entity, user, mapper.WithDestPrefix("DB"))
+func WithDestPrefix(prefixes ...string) Option {
+	return func(c *Config) {
+		c.DestPrefixes = append(c.DestPrefixes, prefixes...)
+	}
+}
+
+// WithDestSuffix strips the first matching suffix from destination field
+// names when matching source fields that have no exact counterpart.
+//
+// Example:
+//
+//	// IDField → IDFieldDTO
+//	mapper.Copy(&dto, src, mapper.WithDestSuffix("DTO"))
+func WithDestSuffix(suffixes ...string) Option {
+	return func(c *Config) {
+		c.DestSuffixes = append(c.DestSuffixes, suffixes...)
+	}
+}
+
 // WithFieldNameMapper sets a custom function for transforming field names
 // before matching. This is useful for converting between different naming
 // conventions such as snake_case, camelCase, etc.
@@ -179,6 +705,90 @@ func WithErrorHandler(handler ErrorHandlerFunc) Option {
 	}
 }
 
+// WithFieldErrorHandler registers an error handler that receives the full
+// context of the failing field, including its path from the root (e.g.
+// "User.Addresses[2].City"), rather than only the leaf field names. It
+// takes precedence over WithErrorHandler.
+//
+// Example:
+//
+//	mapper.WithFieldErrorHandler(func(err error, field mapper.FieldContext) error {
+//	    log.Printf("mapping %s failed: %v", field.Path, err)
+//	    return nil
+//	})
+func WithFieldErrorHandler(handler FieldErrorHandlerFunc) Option {
+	return func(c *Config) {
+		c.FieldErrorHandler = handler
+	}
+}
+
+// WithWarningHandler registers a handler for non-fatal mapping warnings,
+// such as lossy numeric conversions or map keys dropped for lack of a
+// destination field. Warnings never fail the mapping. The handler may be
+// called concurrently and must be safe for concurrent use.
+//
+// Example:
+//
+//	mapper.WithWarningHandler(func(w mapper.Warning) {
+//	    log.Printf("mapping warning: %s", w)
+//	})
+func WithWarningHandler(handler WarningHandlerFunc) Option {
+	return func(c *Config) {
+		c.WarningHandler = handler
+	}
+}
+
+// WithBeforeMap registers a hook called with the destination pointer and
+// source before each Map call. Returning an error aborts the mapping.
+//
+// Example:
+//
+//	mapper.Copy(&dst, src,
+//	    mapper.WithBeforeMap(func(dst, src any) error {
+//	        return validate(src)
+//	    }))
+func WithBeforeMap(hook MapHookFunc) Option {
+	return func(c *Config) {
+		c.BeforeMap = hook
+	}
+}
+
+// WithAfterMap registers a hook called with the destination pointer and
+// source after each successful Map call, e.g. to compute derived fields.
+// Its error is returned from Map. Destination types can implement
+// AfterMapper for per-struct callbacks instead.
+//
+// Example:
+//
+//	mapper.Copy(&dst, src,
+//	    mapper.WithAfterMap(func(dst, src any) error {
+//	        dst.(*OrderDTO).Total = computeTotal(src.(Order))
+//	        return nil
+//	    }))
+func WithAfterMap(hook MapHookFunc) Option {
+	return func(c *Config) {
+		c.AfterMap = hook
+	}
+}
+
+// WithValidator registers a function validating the destination pointer
+// of each Map call once its fields are mapped, e.g. a struct tag
+// validator. Its error is reported as a ValidationError together with the
+// errors of nested Validator destinations and the other field errors.
+//
+// Example:
+//
+//	validate := validator.New()
+//	mapper.Copy(&dto, req,
+//	    mapper.WithValidator(func(dst any) error {
+//	        return validate.Struct(dst)
+//	    }))
+func WithValidator(validate ValidatorFunc) Option {
+	return func(c *Config) {
+		c.Validator = validate
+	}
+}
+
 // WithSkipCircularCheck disables circular reference detection.
 //
 // ⚠️ Use with caution: only disable this if you are certain that
@@ -193,8 +803,52 @@ func WithSkipCircularCheck(skip bool) Option {
 	}
 }
 
+// WithCyclePolicy selects how circular references are handled: CycleError
+// (the default), CycleSkipField, CycleAlias, CycleReuse, CycleNilOut or
+// CycleTruncateAtDepth(n).
+//
+// Example:
+//
+//	mapper.Copy(&dst, graph, mapper.WithCyclePolicy(mapper.CycleAlias))
+func WithCyclePolicy(policy CyclePolicy) Option {
+	return func(c *Config) {
+		c.CyclePolicy = policy
+	}
+}
+
+// WithCycleHandling selects how circular and shared references are
+// handled; it is equivalent to WithCyclePolicy. CycleReuse maps every
+// source reference once, rewiring cycles and shared pointers in the
+// destination, while CycleNilOut breaks cycles with nil references.
+//
+// Example:
+//
+//	mapper.Copy(&dst, graph, mapper.WithCycleHandling(mapper.CycleReuse))
+func WithCycleHandling(policy CyclePolicy) Option {
+	return WithCyclePolicy(policy)
+}
+
+// WithStringConversion enables conversion between strings and numeric or
+// bool fields using strconv, e.g. "42" → int, 12.5 → "12.5", "true" → bool,
+// for stringly-typed API DTOs. Empty strings convert to zero values;
+// unparsable strings fail with ErrTypeMismatch, which is passed to the
+// error handler. Without it, integers convert to strings as runes, per Go
+// conversion rules.
+//
+// Example:
+//
+//	mapper.Copy(&dst, apiDTO, mapper.WithStringConversion(true))
+func WithStringConversion(enable bool) Option {
+	return func(c *Config) {
+		c.StringConversion = enable
+	}
+}
+
 // WithTimeLayout specifies a custom time format for serializing or parsing
-// time.Time values during mapping.
+// time.Time values during mapping. It enables built-in conversions:
+// time.Time fields map to strings formatted with the layout and to integer
+// fields as Unix timestamps in seconds, and strings and integers map back
+// to time.Time. Empty strings and zero times map to each other.
 //
 // Example:
 //
@@ -205,8 +859,39 @@ func WithTimeLayout(layout string) Option {
 	}
 }
 
+// WithClock sets the clock used for time-related defaults, such as
+// destination fields tagged `mapper:",default=now"`, and returned by
+// Mapper.Now for AfterMap hooks. Inject a fixed clock to make mappings that
+// stamp times testable.
+//
+// Example:
+//
+//	fixed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+//	m := mapper.NewMapper(mapper.WithClock(func() time.Time { return fixed }))
+func WithClock(clock func() time.Time) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithTimeZone converts time.Time values into loc while mapping, and
+// parses strings and Unix timestamps in loc. Like WithTimeLayout it enables
+// the built-in time conversions, using RFC 3339 unless a layout is set.
+//
+// Example:
+//
+//	berlin, _ := time.LoadLocation("Europe/Berlin")
+//	mapper.Copy(&dst, src, mapper.WithTimeZone(berlin))
+func WithTimeZone(loc *time.Location) Option {
+	return func(c *Config) {
+		c.TimeZone = loc
+	}
+}
+
 // WithMaxSliceCapacity defines an upper limit for slice allocation during mapping.
-// This prevents excessive memory usage when mapping large slices.
+// This prevents excessive memory usage when mapping large slices. Longer
+// source slices fail with ErrSliceTooLarge, reported at their path, unless
+// WithSliceOverflowPolicy(SliceOverflowTruncate) is set.
 //
 // Example:
 //
@@ -217,9 +902,30 @@ func WithMaxSliceCapacity(capacity int) Option {
 	}
 }
 
+// WithSliceOverflowPolicy selects how slices longer than the limit set with
+// WithMaxSliceCapacity are mapped: SliceOverflowError (the default) or
+// SliceOverflowTruncate.
+//
+// Example:
+//
+//	mapper.Copy(&dst, untrusted,
+//	    mapper.WithMaxSliceCapacity(1000),
+//	    mapper.WithSliceOverflowPolicy(mapper.SliceOverflowTruncate))
+func WithSliceOverflowPolicy(policy SliceOverflowPolicy) Option {
+	return func(c *Config) {
+		c.SliceOverflowPolicy = policy
+	}
+}
+
 // WithAllowPrivateFields enables mapping of unexported (private) struct fields.
 // ⚠️ This should be used cautiously, as it breaks Go's encapsulation guarantees.
 //
+// Unexported fields are read and set through package unsafe, including
+// those of types declared in other packages, and match by name like
+// exported ones. It overrides WithIgnoreUnexported. Builds with the
+// gomap_safe tag, and mappers with WithNoUnsafe(true), fail with
+// ErrUnsafeDisabled instead.
+//
 // Example:
 //
 //	mapper.Copy(&dst, src, mapper.WithAllowPrivateFields(true))
@@ -228,3 +934,257 @@ func WithAllowPrivateFields(allow bool) Option {
 		c.AllowPrivateFields = allow
 	}
 }
+
+// WithConversionAudit enables auditing of implicit conversions: those
+// between basic types, and the string, unit, money, civil and time layout
+// conversions enabled by other options. A sampled fraction (0..1] of conversions is reported to the hook,
+// flagging narrowing ones, so risky conversions hiding in DTO layers can be
+// found in production with bounded overhead.
+//
+// Example:
+//
+//	mapper.Copy(&dst, src,
+//	    mapper.WithConversionAudit(0.01, func(e mapper.ConversionEvent) {
+//	        if e.Narrowing {
+//	            metrics.Inc("mapper.narrowing", e.SrcType.String(), e.DstType.String())
+//	        }
+//	    }))
+func WithConversionAudit(sampleRate float64, hook ConversionHookFunc) Option {
+	return func(c *Config) {
+		c.ConversionSampleRate = sampleRate
+		c.ConversionHook = hook
+	}
+}
+
+// WithCivilTime enables conversions between time.Time, civil date/time
+// component structs such as Date and TimeOfDay (or any struct with integer
+// Year/Month/Day or Hour/Minute/Second fields) and ISO 8601 strings
+// ("2006-01-02", "15:04:05"). Times of day parse with or without seconds,
+// and format without them for structs that have no Second field. As with
+// WithTimeLayout, empty strings, zero times and zero civil values map to
+// each other, so the zero TimeOfDay (midnight) formats as "".
+//
+// Example:
+//
+//	type EventDTO struct {
+//	    Day mapper.Date
+//	}
+//	mapper.Copy(&dst, event, mapper.WithCivilTime(true))
+func WithCivilTime(enable bool) Option {
+	return func(c *Config) {
+		c.CivilTime = enable
+	}
+}
+
+// WithTextMarshaling converts source values implementing
+// encoding.TextMarshaler into string destinations, and source strings into
+// destinations implementing encoding.TextUnmarshaler, which covers UUIDs,
+// decimal types, net.IP and custom enums without hand-written converters.
+// Time conversions configured with WithTimeLayout take precedence.
+//
+// Example:
+//
+//	type Host struct{ Addr net.IP }
+//	type HostDTO struct{ Addr string }
+//	mapper.Copy(&dto, host, mapper.WithTextMarshaling(true))
+func WithTextMarshaling(enable bool) Option {
+	return func(c *Config) {
+		c.TextMarshaling = enable
+	}
+}
+
+// WithStringerFallback renders source values implementing fmt.Stringer
+// into string destinations with their String method, as a last resort once
+// converters and built-in conversions such as WithTextMarshaling have
+// declined them. Enums and custom ID types thus render into display DTOs
+// without converters; numeric types no longer convert into their rune.
+// Sources of string kind still convert directly, and error values are
+// mapped through their Error method regardless of this option.
+//
+// Example:
+//
+//	type Status int // implements fmt.Stringer
+//	type OrderView struct{ Status string }
+//	mapper.Copy(&view, order, mapper.WithStringerFallback(true))
+func WithStringerFallback(enable bool) Option {
+	return func(c *Config) {
+		c.StringerFallback = enable
+	}
+}
+
+// WithJSONBridge bridges JSON blobs and structured values: json.RawMessage
+// and []byte sources are unmarshaled into struct and map destinations, and
+// struct and map sources are marshaled into json.RawMessage and []byte
+// destinations, with encoding/json. Entities storing JSON columns can then
+// hydrate typed DTO sub-structures directly.
+//
+// Example:
+//
+//	type ProductRow struct {
+//	    Attributes json.RawMessage
+//	}
+//	type ProductDTO struct {
+//	    Attributes Attributes
+//	}
+//	mapper.Copy(&dto, row, mapper.WithJSONBridge(true))
+func WithJSONBridge(enable bool) Option {
+	return func(c *Config) {
+		c.JSONBridge = enable
+	}
+}
+
+// WithNullableSupport enables conversions between the database/sql
+// nullable types (sql.NullString, sql.NullInt64, sql.NullTime, sql.Null[T],
+// ...) and plain or pointer fields, in both directions. Invalid values map
+// onto nil pointers and zero values, and nil pointers onto invalid values.
+//
+// Example:
+//
+//	type UserRow struct {
+//	    Email sql.NullString
+//	}
+//	type UserDTO struct {
+//	    Email *string
+//	}
+//	mapper.Copy(&dto, row, mapper.WithNullableSupport(true))
+func WithNullableSupport(enable bool) Option {
+	return func(c *Config) {
+		c.NullableSupport = enable
+	}
+}
+
+// WithMoney enables the money adapter: structs shaped like Money
+// ({Amount int64, Currency string}, amounts in minor units) are converted
+// to and from decimal strings ("12.99 USD") and float fields, using scale
+// minor-unit digits and the given rounding mode for excess precision.
+//
+// Example:
+//
+//	mapper.Copy(&dto, order, mapper.WithMoney(mapper.DefaultMoneyScale, mapper.RoundHalfEven))
+func WithMoney(scale int, rounding RoundingMode) Option {
+	return func(c *Config) {
+		c.MoneySupport = true
+		c.MoneyScale = scale
+		c.MoneyRounding = rounding
+	}
+}
+
+// WithIsolateSource makes the mapper snapshot the source (including maps
+// and slices shared by reference) before mapping, so the destination is
+// built from one consistent view of the source rather than from data that
+// may change while converters and field matching run. The snapshot holds
+// exported fields only (see Mapper.Snapshot), so unexported source fields
+// read as zero, even with WithAllowPrivateFields.
+//
+// Example:
+//
+//	mapper.Copy(&dst, liveStats, mapper.WithIsolateSource(true))
+func WithIsolateSource(isolate bool) Option {
+	return func(c *Config) {
+		c.IsolateSource = isolate
+	}
+}
+
+// WithTranslator routes the values of fields tagged with an i18n option
+// through translate, with the locale set by ContextWithLocale or, failing
+// that, defaultLocale. The translation is then mapped onto the destination
+// field, so DTOs can carry localized labels for enums and codes.
+//
+// Example:
+//
+//	type OrderDTO struct {
+//	    Status string `mapper:",i18n=order.status"`
+//	}
+//	m := mapper.NewMapper(mapper.WithTranslator(catalog.Translate, "en"))
+//	err := m.MapContext(mapper.ContextWithLocale(ctx, "de"), &dto, order)
+func WithTranslator(translate TranslatorFunc, defaultLocale string) Option {
+	return func(c *Config) {
+		c.Translator = translate
+		c.DefaultLocale = defaultLocale
+	}
+}
+
+// WithVisibility sets the roles of the caller. Fields tagged with a roles
+// option (on the source or destination side) are only mapped for callers
+// holding one of the listed roles, and are left untouched otherwise, so one
+// mapping can emit a different field subset per role. Roles can also be set
+// per call with ContextWithVisibility.
+//
+// Example:
+//
+//	type AccountDTO struct {
+//	    Name    string
+//	    Email   string `mapper:",roles=admin;owner"`
+//	    Balance int64  `mapper:",roles=admin"`
+//	}
+//	mapper.Copy(&dto, account, mapper.WithVisibility("owner"))
+func WithVisibility(roles ...string) Option {
+	return func(c *Config) {
+		c.Visibility = append(c.Visibility, roles...)
+	}
+}
+
+// WithNoUnsafe guarantees that the mapper never uses package unsafe, for
+// environments whose security review forbids it. Features that need it
+// (private field access, atomic unsafe.Pointer fields) fail with
+// ErrUnsafeDisabled instead of silently degrading. Builds with the
+// gomap_safe tag enforce the same guarantee at compile time.
+//
+// Example:
+//
+//	m := mapper.NewMapper(mapper.WithNoUnsafe(true))
+func WithNoUnsafe(noUnsafe bool) Option {
+	return func(c *Config) {
+		c.NoUnsafe = noUnsafe
+	}
+}
+
+// WithStringInterning makes the mapper share the instances of repeated
+// destination strings of up to maxLen bytes (status codes, country names,
+// string enums) across all the values it maps, cutting memory for large
+// batch conversions. The interner has a fixed size, so memory stays bounded
+// for high-cardinality strings. It lives in the Mapper, so reuse one Mapper
+// (or TypedMapper) for the whole batch.
+//
+// Example:
+//
+//	rows, err := mapper.MapSlice[OrderRow, Order](batch, mapper.WithStringInterning(32))
+func WithStringInterning(maxLen int) Option {
+	return func(c *Config) {
+		c.InternMaxLen = maxLen
+	}
+}
+
+// WithChannelSnapshot maps source channel fields onto destination slice
+// fields by draining up to limit items already buffered in the channel,
+// without blocking. This consumes the items, so it is meant for debug and
+// introspection DTOs of pipeline objects. Without it, channels of the same
+// type are shared and others are left untouched.
+//
+// Example:
+//
+//	type Worker struct {
+//	    Queue chan Job
+//	}
+//	type WorkerDebug struct {
+//	    Queue []JobDTO
+//	}
+//	mapper.Copy(&debug, worker, mapper.WithChannelSnapshot(100))
+func WithChannelSnapshot(limit int) Option {
+	return func(c *Config) {
+		c.ChannelSnapshotLimit = limit
+	}
+}
+
+// WithParallelism makes MapSlice map large slices with up to n goroutines.
+// Items are still written to their own index, so the result keeps the
+// order of the source.
+//
+// Example:
+//
+//	dtos, err := mapper.MapSlice[User, UserDTO](users, mapper.WithParallelism(runtime.GOMAXPROCS(0)))
+func WithParallelism(n int) Option {
+	return func(c *Config) {
+		c.Parallelism = n
+	}
+}
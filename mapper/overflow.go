@@ -0,0 +1,98 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements capture and re-emission of unknown (unmatched) fields.
+package mapper
+
+import "reflect"
+
+// OverflowTagOption marks a map[string]T struct field as the overflow
+// field of its struct, e.g. `mapper:",overflow"`.
+//
+// When the struct is a mapping destination, source fields without a
+// matching destination field are captured into the overflow map keyed by
+// source field name. When the struct is a mapping source, the captured
+// entries are re-emitted into matching destination fields (or carried over
+// into the destination's own overflow field), so typed pass-through structs
+// can round-trip values they do not declare.
+const OverflowTagOption = "overflow"
+
+// isOverflowField reports whether the given struct field is tagged as an
+// overflow field and has a string-keyed map type.
+func (ctx *context) isOverflowField(field reflect.StructField) bool {
+	if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+		return false
+	}
+	return ctx.hasTagOption(field, OverflowTagOption)
+}
+
+// overflowIndex returns the index of the overflow field of the struct
+// type t, or -1 if it has none.
+func (ctx *context) overflowIndex(t reflect.Type) int {
+	for i := 0; i < t.NumField(); i++ {
+		if ctx.isOverflowField(t.Field(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// captureOverflow stores a deep copy of an unmatched source value into the
+// destination overflow map under the given name.
+func (ctx *context) captureOverflow(overflow reflect.Value, name string, value reflect.Value) error {
+	if !overflow.CanSet() {
+		return nil
+	}
+
+	elemType := overflow.Type().Elem()
+	cp := reflect.New(value.Type()).Elem()
+	if err := ctx.mapValue(cp, value); err != nil {
+		return err
+	}
+	if !cp.Type().AssignableTo(elemType) {
+		if !cp.Type().ConvertibleTo(elemType) {
+			return nil
+		}
+		cp = cp.Convert(elemType)
+	}
+
+	if overflow.IsNil() {
+		overflow.Set(reflect.MakeMap(overflow.Type()))
+	}
+	overflow.SetMapIndex(reflect.ValueOf(name).Convert(overflow.Type().Key()), cp)
+	return nil
+}
+
+// emitOverflow re-emits the entries of a source overflow map into the
+// destination struct. Entries matching a destination field are mapped into
+// that field; the rest are carried into the destination overflow field at
+// dstOverflowIndex when there is one (dstOverflowIndex >= 0).
+func (ctx *context) emitOverflow(dst, srcOverflow reflect.Value, dstOverflowIndex int) error {
+	if srcOverflow.Len() == 0 {
+		return nil
+	}
+
+	iter := srcOverflow.MapRange()
+	for iter.Next() {
+		name := iter.Key().String()
+		value := iter.Value()
+
+		dstField, found := ctx.findDstField(dst.Type(), name)
+		if found && !(len(dstField.Index) == 1 && dstField.Index[0] == dstOverflowIndex) {
+			dstValue := dst.FieldByIndex(dstField.Index)
+			if !dstValue.CanSet() {
+				continue
+			}
+			if err := ctx.mapValue(dstValue, value); err != nil {
+				ctx.addError(ctx.mapError("mapStruct", dstValue, value, name, dstField.Name, err))
+			}
+			continue
+		}
+
+		if dstOverflowIndex >= 0 {
+			if err := ctx.captureOverflow(dst.Field(dstOverflowIndex), name, value); err != nil {
+				ctx.addError(ctx.mapError("mapStruct", dst.Field(dstOverflowIndex), value, name, "", err))
+			}
+		}
+	}
+
+	return nil
+}
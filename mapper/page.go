@@ -0,0 +1,43 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements mapping of pagination envelopes.
+package mapper
+
+// Page is a common pagination envelope: a page of items with offset and
+// cursor metadata. Unused metadata fields are left zero.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size,omitempty"`
+	Total      int64  `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// MapPage maps the items of a page into D, copying its metadata unchanged.
+// A nil Items slice stays nil.
+//
+// Other envelope shapes map with Copy as usual, since their metadata fields
+// match by name and item slices are mapped element by element.
+//
+// Example:
+//
+//	page, err := repo.ListUsers(ctx, cursor)
+//	dtos, err := mapper.MapPage[User, UserDTO](page)
+func MapPage[S, D any](page Page[S], opts ...Option) (Page[D], error) {
+	out := Page[D]{
+		Page:       page.Page,
+		PageSize:   page.PageSize,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	if page.Items == nil {
+		return out, nil
+	}
+
+	out.Items = make([]D, 0, len(page.Items))
+	if err := NewMapper(opts...).Map(&out.Items, page.Items); err != nil {
+		return Page[D]{}, err
+	}
+	return out, nil
+}
@@ -0,0 +1,131 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements tracking of the source field path being mapped.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of the source path: a field name (or dotted
+// path), a slice or array index, or a map key. Element segments are only
+// formatted when the path is rendered.
+type pathSegment struct {
+	// name is the field name, or "" for element segments
+	name string
+
+	// index is the slice or array index of an element segment
+	index int
+
+	// key is the map key of a map entry segment, if valid
+	key reflect.Value
+}
+
+// rootPathName returns the name of the root path segment for a source
+// type: its type name, after dereferencing pointers. Unnamed slice, array
+// and map types are named after their element type, so elements of an
+// []Item root have paths such as "Item[3].Price". Anonymous structs have
+// no root segment.
+func rootPathName(t reflect.Type) string {
+	for t.Name() == "" {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return ""
+		}
+	}
+	return t.Name()
+}
+
+// pushPath appends a field name segment to the current path.
+func (ctx *context) pushPath(name string) {
+	ctx.path = append(ctx.path, pathSegment{name: name})
+}
+
+// pushIndex appends a slice or array index segment to the current path.
+func (ctx *context) pushIndex(i int) {
+	ctx.path = append(ctx.path, pathSegment{index: i})
+}
+
+// pushKey appends a map key segment to the current path.
+func (ctx *context) pushKey(key reflect.Value) {
+	ctx.path = append(ctx.path, pathSegment{key: key})
+}
+
+// popPath removes the last segment of the current path.
+func (ctx *context) popPath() {
+	ctx.path = ctx.path[:len(ctx.path)-1]
+}
+
+// fieldPath returns the path of the value being mapped, e.g.
+// "Order.Items[3].Price" or `Order.Tags["gift"]`. The root segment is the
+// source type name (see rootPathName).
+func (ctx *context) fieldPath() string {
+	var b strings.Builder
+	for i, seg := range ctx.path {
+		switch {
+		case i == 0 && seg.name == "":
+			// Anonymous root
+		case seg.name != "":
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(seg.name)
+		case seg.key.IsValid():
+			if seg.key.Kind() == reflect.String {
+				fmt.Fprintf(&b, "[%q]", seg.key.String())
+			} else {
+				fmt.Fprintf(&b, "[%v]", seg.key)
+			}
+		default:
+			b.WriteString("[" + strconv.Itoa(seg.index) + "]")
+		}
+	}
+	return b.String()
+}
+
+// fieldNames returns the field name segments of the current path, leaving
+// out indexes and map keys. The first name is the root segment, empty for
+// anonymous roots. Rules keyed by path (field converters, ignore patterns)
+// match against these names.
+func (ctx *context) fieldNames() []string {
+	names := make([]string, 0, len(ctx.path))
+	for i, seg := range ctx.path {
+		if i == 0 || seg.name != "" {
+			names = append(names, seg.name)
+		}
+	}
+	return names
+}
+
+// fieldConverter returns the converter registered for the field being
+// mapped, matched by its full path ("Order.Total") or by its path relative
+// to the root ("Total"). Indexes and map keys are not part of the match, so
+// "Order.Items.Price" applies to the price of every item.
+func (ctx *context) fieldConverter() (ConverterFunc, bool) {
+	return matchFieldPath(ctx, ctx.config.FieldConverters)
+}
+
+// matchFieldPath returns the rule keyed by the path of the field being
+// mapped, like fieldConverter.
+func matchFieldPath[V any](ctx *context, rules map[string]V) (V, bool) {
+	var zero V
+	if len(rules) == 0 || len(ctx.path) < 2 {
+		return zero, false
+	}
+
+	names := ctx.fieldNames()
+	if len(names) < 2 {
+		return zero, false
+	}
+	if names[0] != "" {
+		if rule, ok := rules[strings.Join(names, ".")]; ok {
+			return rule, true
+		}
+	}
+	rule, ok := rules[strings.Join(names[1:], ".")]
+	return rule, ok
+}
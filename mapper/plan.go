@@ -0,0 +1,414 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements compiled, cached struct mapping plans.
+package mapper
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// planKey identifies a compiled plan. Plans are cached per Mapper and
+// compiled from its configuration, so the type pair identifies a plan as
+// long as the configuration does not change. Register, the only way to
+// change it, discards the cached plans, and must not run concurrently with
+// mappings.
+//
+// The key deliberately carries no configuration hash. Plans capture the
+// converters, member resolvers and naming hooks of the configuration,
+// which are functions: closures sharing code cannot be told apart, so a
+// hash over them would either let mappers with different closures share a
+// plan or reduce to the identity of the configuration, which the
+// per-Mapper cache already provides. Callers mapping with the same options
+// repeatedly should therefore build one Mapper rather than pass per-call
+// options to Copy, which compiles its plans afresh.
+type planKey struct {
+	src reflect.Type
+	dst reflect.Type
+}
+
+// fieldPlan describes how one source field is mapped.
+type fieldPlan struct {
+	// srcIndex is the index sequence of the field in the source struct.
+	// It spans several structs for dotted source paths ("Address.City").
+	srcIndex []int
+
+	// srcName is the name (or dotted path) of the source field.
+	srcName string
+
+	// dstIndex is the index sequence of the matched destination field,
+	// or nil if the field has no destination counterpart. Intermediate
+	// nil pointers along the sequence are allocated when mapping.
+	dstIndex []int
+
+	// dstName is the name of the matched destination field.
+	dstName string
+
+	// srcAtomic and dstAtomic report whether the source and destination
+	// fields are tagged for atomic access.
+	srcAtomic bool
+	dstAtomic bool
+
+	// unit is the unit conversion annotated on the source or destination
+	// field, or nil.
+	unit *unitConversion
+
+	// srcRoles and dstRoles are the roles the source and destination
+	// fields are restricted to, or nil.
+	srcRoles []string
+	dstRoles []string
+
+	// i18nKey is the message key the value is translated under, or "".
+	i18nKey string
+
+	// converter is the name of the converter selected by the field tags,
+	// or "".
+	converter string
+
+	// numeric reports whether the source or destination field is tagged
+	// with NumericTagOption.
+	numeric bool
+
+	// shallow reports whether the source or destination field is tagged
+	// with ShallowTagOption.
+	shallow bool
+
+	// keepRaw is the sibling destination field receiving the raw source
+	// value, or nil.
+	keepRaw *rawCapture
+
+	// required and omitEmpty report whether the source or destination
+	// field is tagged with RequiredTagOption or OmitEmptyTagOption.
+	required  bool
+	omitEmpty bool
+}
+
+// structPlan is the precomputed field-to-field plan for a struct type pair.
+type structPlan struct {
+	// fields lists the source fields to map, in declaration order, or in
+	// destination declaration order with MatchByDestination. Fields
+	// skipped by configuration (unexported, untagged, overflow) are
+	// omitted.
+	fields []fieldPlan
+
+	// srcOverflow and dstOverflow are the indexes of the overflow fields
+	// of the source and destination structs, or -1.
+	srcOverflow int
+	dstOverflow int
+
+	// members lists the split and combine rules applying to the pair,
+	// mapped after the regular fields.
+	members []memberPlan
+
+	// defaults lists the destination fields tagged with a default.
+	defaults []fieldDefault
+
+	// missing lists the required destination fields that no source field
+	// populates.
+	missing []reflect.StructField
+}
+
+// structPlan returns the plan for mapping srcType onto dstType, compiling
+// and caching it on first use.
+func (ctx *context) structPlan(srcType, dstType reflect.Type) *structPlan {
+	if ctx.plans == nil {
+		return ctx.compilePlan(srcType, dstType)
+	}
+
+	key := planKey{src: srcType, dst: dstType}
+	if plan, ok := ctx.plans.Load(key); ok {
+		return plan.(*structPlan)
+	}
+
+	plan, _ := ctx.plans.LoadOrStore(key, ctx.compilePlan(srcType, dstType))
+	return plan.(*structPlan)
+}
+
+// compilePlan builds the field plan for a struct type pair by resolving
+// field visibility, tags and name matching once.
+func (ctx *context) compilePlan(srcType, dstType reflect.Type) *structPlan {
+	plan := &structPlan{
+		srcOverflow: ctx.overflowIndex(srcType),
+		dstOverflow: ctx.overflowIndex(dstType),
+	}
+
+	if ctx.config.MatchBy == MatchByDestination {
+		plan.fields = ctx.compileDestinationFields(srcType, dstType, plan)
+	} else {
+		plan.fields = ctx.compileSourceFields(srcType, dstType, plan)
+	}
+
+	plan.fields = append(plan.fields, ctx.compileSourcePaths(srcType, dstType)...)
+	plan.members = ctx.compileMembers(srcType, dstType)
+	plan.defaults = ctx.fieldDefaults(dstType)
+	plan.missing = ctx.missingRequired(plan, dstType)
+	return plan
+}
+
+// compileSourceFields plans the fields of a struct type pair driven by the
+// source fields, in their declaration order.
+func (ctx *context) compileSourceFields(srcType, dstType reflect.Type, plan *structPlan) []fieldPlan {
+	var fields []fieldPlan
+	for i := 0; i < srcType.NumField(); i++ {
+		srcField := srcType.Field(i)
+
+		// Overflow fields are handled by emitOverflow
+		if i == plan.srcOverflow {
+			continue
+		}
+
+		field, ok := ctx.planField(srcField, dstType)
+		if !ok {
+			continue
+		}
+
+		// Expand embedded structs that cannot be mapped as a whole into
+		// their promoted fields
+		if srcField.Anonymous && ctx.expandEmbedded(field, dstType) {
+			fields = append(fields, ctx.planPromoted(srcType, srcField, dstType)...)
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// expandEmbedded reports whether the embedded source field planned as
+// field is mapped through its promoted fields rather than as a whole: when
+// the destination has no counterpart and flattening is enabled or the
+// destination embeds structs of its own (e.g. Base where the source embeds
+// *BaseModel), or when the counterpart is an unexported embedded struct
+// that can only be populated field by field.
+func (ctx *context) expandEmbedded(field fieldPlan, dstType reflect.Type) bool {
+	if field.dstIndex == nil {
+		return ctx.config.FlattenEmbedded || hasEmbeddedStruct(dstType)
+	}
+	dstField := dstType.FieldByIndex(field.dstIndex)
+	return dstField.Anonymous && dstField.PkgPath != ""
+}
+
+// hasEmbeddedStruct reports whether t embeds a struct or struct pointer.
+func hasEmbeddedStruct(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous {
+			if ft := f.Type; ft.Kind() == reflect.Struct || (ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// planField plans a single source field against the destination type. It
+// reports false when the field is skipped by configuration.
+func (ctx *context) planField(srcField reflect.StructField, dstType reflect.Type) (fieldPlan, bool) {
+	if ctx.skipField(srcField) {
+		return fieldPlan{}, false
+	}
+
+	field := fieldPlan{
+		srcIndex:  srcField.Index,
+		srcName:   srcField.Name,
+		srcAtomic: ctx.hasTagOption(srcField, AtomicTagOption),
+		srcRoles:  ctx.fieldRoles(srcField),
+		required:  ctx.hasTagOption(srcField, RequiredTagOption),
+		omitEmpty: ctx.hasTagOption(srcField, OmitEmptyTagOption),
+	}
+	name := ctx.getDestFieldName(srcField)
+	if dstField, index, found := ctx.resolvePath(dstType, name); found {
+		field.dstIndex = index
+		field.dstName = dstField.Name
+		field.dstAtomic = ctx.hasTagOption(dstField, AtomicTagOption)
+		field.unit = ctx.fieldUnitConversion(srcField, dstField)
+		field.dstRoles = ctx.fieldRoles(dstField)
+		field.i18nKey = ctx.fieldI18nKey(srcField, dstField)
+		field.converter = ctx.fieldConverterName(srcField, dstField)
+		field.numeric = ctx.hasTagOption(srcField, NumericTagOption) || ctx.hasTagOption(dstField, NumericTagOption)
+		field.shallow = ctx.hasTagOption(srcField, ShallowTagOption) || ctx.hasTagOption(dstField, ShallowTagOption)
+		field.keepRaw = ctx.fieldRawCapture(srcField, dstField, dstType, index)
+		field.required = field.required || ctx.hasTagOption(dstField, RequiredTagOption)
+		field.omitEmpty = field.omitEmpty || ctx.hasTagOption(dstField, OmitEmptyTagOption)
+	} else if dstField, ok := dstType.FieldByName(name); ok && ctx.excludedField(dstField) {
+		// Excluded destination fields are not captured as overflow either
+		return fieldPlan{}, false
+	}
+	return field, true
+}
+
+// skipField reports whether a source field is excluded from mapping by
+// configuration: unexported fields, fields of ignored types and, with a tag
+// name, untagged or "-" tagged fields.
+func (ctx *context) skipField(srcField reflect.StructField) bool {
+	if ctx.unmappableSource(srcField) {
+		return true
+	}
+
+	// Tag filtering
+	if ctx.config.TagName != "" {
+		tag := srcField.Tag.Get(ctx.config.TagName)
+		if tag == "" || tag == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// unmappableSource reports whether a source field is excluded from mapping
+// whatever its tags: unexported fields, unless private fields are allowed,
+// and fields of ignored types.
+func (ctx *context) unmappableSource(srcField reflect.StructField) bool {
+	if ctx.config.IgnoreUnexported && !ctx.config.AllowPrivateFields && srcField.PkgPath != "" && !srcField.Anonymous {
+		return true
+	}
+	return ctx.ignoredType(srcField.Type)
+}
+
+// planPromoted plans the fields promoted to srcType through the embedded
+// field embedded, following Go's promotion rules: a promoted field is only
+// mapped if srcType.FieldByName selects it, so fields shadowed by a
+// shallower field or ambiguous at the same depth are left out. Promoted
+// fields without a destination counterpart are dropped rather than
+// captured as overflow.
+func (ctx *context) planPromoted(srcType reflect.Type, embedded reflect.StructField, dstType reflect.Type) []fieldPlan {
+	var (
+		fields []fieldPlan
+		whole  [][]int // nested embedded structs mapped as a whole
+	)
+	for _, srcField := range reflect.VisibleFields(srcType) {
+		if len(srcField.Index) < 2 || srcField.Index[0] != embedded.Index[0] || hasIndexPrefix(whole, srcField.Index) {
+			continue
+		}
+		if selected, ok := srcType.FieldByName(srcField.Name); !ok || !slices.Equal(selected.Index, srcField.Index) {
+			continue
+		}
+
+		field, ok := ctx.planField(srcField, dstType)
+		if !ok || field.dstIndex == nil {
+			continue
+		}
+		if srcField.Anonymous {
+			whole = append(whole, srcField.Index)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// hasIndexPrefix reports whether index lies within a field whose index
+// sequence is one of prefixes.
+func hasIndexPrefix(prefixes [][]int, index []int) bool {
+	for _, prefix := range prefixes {
+		if len(index) > len(prefix) && slices.Equal(index[:len(prefix)], prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileSourcePaths plans the destination fields populated from dotted
+// source paths, declared either in the destination field's tag
+// (`mapper:"Address.City"`) or as a dotted key of the field mapping table.
+// They are mapped after the regular fields and take precedence over them.
+func (ctx *context) compileSourcePaths(srcType, dstType reflect.Type) []fieldPlan {
+	var fields []fieldPlan
+	add := func(srcPath string, dstField reflect.StructField, dstIndex []int) {
+		srcField, srcIndex, found := ctx.resolvePath(srcType, srcPath)
+		if !found || srcField.PkgPath != "" {
+			return
+		}
+		fields = append(fields, ctx.pairFields(srcPath, srcField, srcIndex, dstField, dstType, dstIndex))
+	}
+
+	if ctx.config.TagName != "" || ctx.config.MatchBy == MatchByDestination {
+		for i := 0; i < dstType.NumField(); i++ {
+			dstField := dstType.Field(i)
+			if srcPath := ctx.fieldTag(dstField).name; strings.Contains(srcPath, ".") {
+				add(srcPath, dstField, dstField.Index)
+			}
+		}
+	}
+
+	for srcPath, dstPath := range ctx.config.FieldMappings {
+		if !strings.Contains(srcPath, ".") {
+			continue
+		}
+		if dstField, dstIndex, found := ctx.resolvePath(dstType, dstPath); found {
+			add(srcPath, dstField, dstIndex)
+		}
+	}
+
+	return fields
+}
+
+// pairFields plans the mapping of the source field srcField, at srcIndex
+// and named srcName, onto the destination field dstField at dstIndex.
+func (ctx *context) pairFields(srcName string, srcField reflect.StructField, srcIndex []int, dstField reflect.StructField, dstType reflect.Type, dstIndex []int) fieldPlan {
+	return fieldPlan{
+		srcIndex:  srcIndex,
+		srcName:   srcName,
+		dstIndex:  dstIndex,
+		dstName:   dstField.Name,
+		srcAtomic: ctx.hasTagOption(srcField, AtomicTagOption),
+		dstAtomic: ctx.hasTagOption(dstField, AtomicTagOption),
+		unit:      ctx.fieldUnitConversion(srcField, dstField),
+		srcRoles:  ctx.fieldRoles(srcField),
+		dstRoles:  ctx.fieldRoles(dstField),
+		i18nKey:   ctx.fieldI18nKey(srcField, dstField),
+		converter: ctx.fieldConverterName(srcField, dstField),
+		numeric:   ctx.hasTagOption(srcField, NumericTagOption) || ctx.hasTagOption(dstField, NumericTagOption),
+		shallow:   ctx.hasTagOption(srcField, ShallowTagOption) || ctx.hasTagOption(dstField, ShallowTagOption),
+		keepRaw:   ctx.fieldRawCapture(srcField, dstField, dstType, dstIndex),
+		required:  ctx.hasTagOption(srcField, RequiredTagOption) || ctx.hasTagOption(dstField, RequiredTagOption),
+		omitEmpty: ctx.hasTagOption(srcField, OmitEmptyTagOption) || ctx.hasTagOption(dstField, OmitEmptyTagOption),
+	}
+}
+
+// resolvePath resolves a field name or dotted field path ("Address.City")
+// against a struct type. It returns the final field and the index sequence
+// leading to it; intermediate fields must be exported structs or pointers
+// to structs.
+func (ctx *context) resolvePath(t reflect.Type, path string) (reflect.StructField, []int, bool) {
+	name, rest, nested := strings.Cut(path, ".")
+	field, found := ctx.findDstField(t, name)
+	if !found || !nested {
+		return field, field.Index, found
+	}
+
+	next := field.Type
+	if next.Kind() == reflect.Ptr {
+		next = next.Elem()
+	}
+	if field.PkgPath != "" || next.Kind() != reflect.Struct {
+		return reflect.StructField{}, nil, false
+	}
+
+	last, index, found := ctx.resolvePath(next, rest)
+	if !found {
+		return reflect.StructField{}, nil, false
+	}
+	return last, append(append([]int(nil), field.Index...), index...), true
+}
+
+// fieldByIndexAlloc returns the nested field of v at index, allocating
+// nil intermediate pointers. It returns the zero Value when an
+// intermediate pointer is nil and cannot be set.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// planCache holds the compiled plans of a Mapper.
+type planCache = sync.Map
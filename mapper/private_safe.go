@@ -0,0 +1,13 @@
+//go:build gomap_safe
+
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file stubs the unexported field access of gomap_safe builds.
+package mapper
+
+import "reflect"
+
+// exposeField returns v unchanged: unexported fields need package unsafe,
+// and mappings with AllowPrivateFields fail before reaching it.
+func exposeField(v reflect.Value) reflect.Value {
+	return v
+}
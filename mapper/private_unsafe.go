@@ -0,0 +1,20 @@
+//go:build !gomap_safe
+
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements access to unexported struct fields.
+package mapper
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// exposeField returns an unrestricted view of the unexported struct field
+// v, so it can be read and set like an exported one. Values that are not
+// restricted, or not addressable, are returned unchanged.
+func exposeField(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.CanInterface() || !v.CanAddr() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
@@ -0,0 +1,17 @@
+module github.com/fbarikzehi/gomap/mapper/protomap
+
+go 1.24.9
+
+require (
+	github.com/fbarikzehi/gomap v0.0.0
+	github.com/stretchr/testify v1.11.1
+	google.golang.org/protobuf v1.36.9
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/fbarikzehi/gomap => ../..
@@ -0,0 +1,284 @@
+// Package protomap maps between protobuf-generated messages and plain Go
+// structs. It registers converters for the well-known types and split and
+// combine rules for oneof fields, so gRPC handlers can map requests and
+// responses onto domain types without hand-written converters.
+//
+// protomap is a separate Go module: importing the core mapper never pulls
+// google.golang.org/protobuf into a build.
+//
+// Example:
+//
+//	m := mapper.NewMapper(
+//	    protomap.WithWellKnownTypes(),
+//	    protomap.WithOneofs(&pb.Notification{}),
+//	)
+//	var n Notification
+//	err := m.Map(&n, req.GetNotification())
+package protomap
+
+import (
+	"reflect"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/fbarikzehi/gomap/mapper"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	timestampType = reflect.TypeOf(&timestamppb.Timestamp{})
+	pbDuration    = reflect.TypeOf(&durationpb.Duration{})
+)
+
+// wrapperTypes lists the wrapperspb messages with the Go type of their
+// Value field.
+var wrapperTypes = []struct {
+	wrapper reflect.Type
+	value   reflect.Type
+}{
+	{reflect.TypeOf(&wrapperspb.DoubleValue{}), reflect.TypeOf(float64(0))},
+	{reflect.TypeOf(&wrapperspb.FloatValue{}), reflect.TypeOf(float32(0))},
+	{reflect.TypeOf(&wrapperspb.Int64Value{}), reflect.TypeOf(int64(0))},
+	{reflect.TypeOf(&wrapperspb.UInt64Value{}), reflect.TypeOf(uint64(0))},
+	{reflect.TypeOf(&wrapperspb.Int32Value{}), reflect.TypeOf(int32(0))},
+	{reflect.TypeOf(&wrapperspb.UInt32Value{}), reflect.TypeOf(uint32(0))},
+	{reflect.TypeOf(&wrapperspb.BoolValue{}), reflect.TypeOf(false)},
+	{reflect.TypeOf(&wrapperspb.StringValue{}), reflect.TypeOf("")},
+	{reflect.TypeOf(&wrapperspb.BytesValue{}), reflect.TypeOf([]byte(nil))},
+}
+
+// WithWellKnownTypes registers converters between the protobuf well-known
+// types and their Go counterparts:
+//
+//   - *timestamppb.Timestamp ↔ time.Time and *time.Time
+//   - *durationpb.Duration ↔ time.Duration and *time.Duration
+//   - *wrapperspb.StringValue, Int64Value, ... ↔ *string, *int64, ...
+//     (BytesValue ↔ []byte), a nil wrapper being a nil pointer
+//
+// The converters are pair converters, so they only apply between these
+// types: other time.Time, time.Duration and pointer fields keep their
+// regular mapping and any converters registered for them. Invalid
+// timestamps and durations fail the field.
+//
+// Example:
+//
+//	var event Event // CreatedAt time.Time, TTL time.Duration, Note *string
+//	err := mapper.Copy(&event, pbEvent, protomap.WithWellKnownTypes())
+func WithWellKnownTypes() mapper.Option {
+	opts := []mapper.Option{
+		mapper.WithPairConverter(timestampType, timeType, fromTimestamp),
+		mapper.WithPairConverter(timestampType, reflect.PointerTo(timeType), fromTimestamp),
+		mapper.WithPairConverter(timeType, timestampType, toTimestamp),
+		mapper.WithPairConverter(reflect.PointerTo(timeType), timestampType, toTimestamp),
+		mapper.WithPairConverter(pbDuration, durationType, fromDuration),
+		mapper.WithPairConverter(pbDuration, reflect.PointerTo(durationType), fromDuration),
+		mapper.WithPairConverter(durationType, pbDuration, toDuration),
+		mapper.WithPairConverter(reflect.PointerTo(durationType), pbDuration, toDuration),
+	}
+	for _, w := range wrapperTypes {
+		opts = append(opts,
+			mapper.WithPairConverter(w.wrapper, nullableOf(w.value), unwrap),
+			mapper.WithPairConverter(nullableOf(w.value), w.wrapper, wrapper(w.wrapper)))
+	}
+
+	return func(c *mapper.Config) {
+		for _, opt := range opts {
+			opt(c)
+		}
+	}
+}
+
+// deref follows a pointer source, which the mapper never passes as nil.
+func deref(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Ptr {
+		return v.Elem()
+	}
+	return v
+}
+
+func fromTimestamp(v reflect.Value) (reflect.Value, error) {
+	ts := v.Interface().(*timestamppb.Timestamp)
+	if err := ts.CheckValid(); err != nil {
+		return v, err
+	}
+	return reflect.ValueOf(ts.AsTime()), nil
+}
+
+func toTimestamp(v reflect.Value) (reflect.Value, error) {
+	return reflect.ValueOf(timestamppb.New(deref(v).Interface().(time.Time))), nil
+}
+
+func fromDuration(v reflect.Value) (reflect.Value, error) {
+	d := v.Interface().(*durationpb.Duration)
+	if err := d.CheckValid(); err != nil {
+		return v, err
+	}
+	return reflect.ValueOf(d.AsDuration()), nil
+}
+
+func toDuration(v reflect.Value) (reflect.Value, error) {
+	return reflect.ValueOf(durationpb.New(deref(v).Interface().(time.Duration))), nil
+}
+
+// nullableOf returns the Go type standing for an optional value of type t:
+// a pointer, or t itself for slices.
+func nullableOf(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Slice {
+		return t
+	}
+	return reflect.PointerTo(t)
+}
+
+// nullable returns a copy of v of the type returned by nullableOf, so it
+// maps onto pointer destinations without sharing the message's storage.
+func nullable(v reflect.Value) reflect.Value {
+	if t := nullableOf(v.Type()); t != v.Type() {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p
+	}
+	return v
+}
+
+// unwrap converts a wrapperspb message to its value.
+func unwrap(v reflect.Value) (reflect.Value, error) {
+	return nullable(v.Elem().FieldByName("Value")), nil
+}
+
+// wrapper returns a converter from a value to a wrapperspb message.
+func wrapper(wrapperType reflect.Type) mapper.ConverterFunc {
+	return func(v reflect.Value) (reflect.Value, error) {
+		w := reflect.New(wrapperType.Elem())
+		w.Elem().FieldByName("Value").Set(deref(v))
+		return w, nil
+	}
+}
+
+// WithOneofs registers the oneof fields of the given messages. A oneof is
+// mapped onto Go structs as one field per case, named like the field of
+// the generated case wrapper: the set case is mapped onto its field and
+// the other fields are left untouched. The reverse direction sets the
+// oneof from the non-nil case field, the last one winning if several are
+// set; case fields should therefore be pointers, slices or maps.
+//
+// The rules match struct pairs by field name, like WithFieldSplit and
+// WithFieldCombine, so messages whose oneofs share both the oneof name and
+// a case name must not be registered on the same mapper. Case values
+// mapped onto a message are mapped with the mapper's configuration.
+//
+// Example:
+//
+//	// message Notification { oneof target { Email email = 1; Phone sms = 2; } }
+//	type Notification struct {
+//	    Email *Email
+//	    Sms   *Phone
+//	}
+//
+//	m := mapper.NewMapper(protomap.WithOneofs(&pb.Notification{}))
+func WithOneofs(msgs ...proto.Message) mapper.Option {
+	return func(c *mapper.Config) {
+		for _, msg := range msgs {
+			for _, oneof := range oneofsOf(msg) {
+				for _, cs := range oneof.cases {
+					mapper.WithFieldSplit(oneof.field, []string{cs.field}, splitCase(cs.wrapper))(c)
+					mapper.WithFieldCombine([]string{cs.field}, oneof.field, combineCase(c, cs.wrapper))(c)
+				}
+			}
+		}
+	}
+}
+
+// oneofField describes the Go field of a oneof and its cases.
+type oneofField struct {
+	field string
+	cases []oneofCase
+}
+
+// oneofCase describes a generated case wrapper, e.g. *pb.Notification_Email
+// with its single field Email.
+type oneofCase struct {
+	field   string
+	wrapper reflect.Type
+}
+
+// oneofsOf returns the oneof fields of msg. The case wrapper types are
+// discovered by setting each case on a fresh message.
+func oneofsOf(msg proto.Message) []oneofField {
+	pm := msg.ProtoReflect()
+	desc := pm.Descriptor()
+	goType := reflect.TypeOf(msg).Elem()
+
+	var fields []oneofField
+	for i := 0; i < desc.Oneofs().Len(); i++ {
+		od := desc.Oneofs().Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+		var goField reflect.StructField
+		for j := 0; j < goType.NumField(); j++ {
+			if goType.Field(j).Tag.Get("protobuf_oneof") == string(od.Name()) {
+				goField = goType.Field(j)
+				break
+			}
+		}
+		if goField.Name == "" {
+			continue
+		}
+
+		oneof := oneofField{field: goField.Name}
+		for j := 0; j < od.Fields().Len(); j++ {
+			fd := od.Fields().Get(j)
+			m := pm.New()
+			m.Set(fd, m.NewField(fd))
+			set := reflect.ValueOf(m.Interface()).Elem().FieldByIndex(goField.Index).Elem()
+			oneof.cases = append(oneof.cases, oneofCase{
+				field:   set.Elem().Type().Field(0).Name,
+				wrapper: set.Type(),
+			})
+		}
+		fields = append(fields, oneof)
+	}
+	return fields
+}
+
+// splitCase returns the split rule extracting one case of a oneof.
+func splitCase(wrapperType reflect.Type) mapper.SplitFunc {
+	return func(src any) ([]any, error) {
+		if src == nil || reflect.TypeOf(src) != wrapperType {
+			return []any{nil}, nil
+		}
+		return []any{nullable(reflect.ValueOf(src).Elem().Field(0)).Interface()}, nil
+	}
+}
+
+// combineCase returns the combine rule setting a oneof to one case. The
+// case value is mapped into the wrapper with the configuration c.
+func combineCase(c *mapper.Config, wrapperType reflect.Type) mapper.CombineFunc {
+	return func(srcs []any) (any, error) {
+		v := reflect.ValueOf(srcs[0])
+		if !v.IsValid() || isNil(v) {
+			return nil, nil
+		}
+		w := reflect.New(wrapperType.Elem())
+		err := mapper.Copy(w.Elem().Field(0).Addr().Interface(), srcs[0], func(cfg *mapper.Config) {
+			*cfg = *c
+		})
+		if err != nil {
+			return nil, err
+		}
+		return w.Interface(), nil
+	}
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
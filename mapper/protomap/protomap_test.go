@@ -0,0 +1,157 @@
+package protomap_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/fbarikzehi/gomap/mapper"
+	"github.com/fbarikzehi/gomap/mapper/protomap"
+)
+
+type pbEvent struct {
+	CreatedAt *timestamppb.Timestamp
+	DeletedAt *timestamppb.Timestamp
+	TTL       *durationpb.Duration
+	Note      *wrapperspb.StringValue
+	Retries   *wrapperspb.Int32Value
+	Payload   *wrapperspb.BytesValue
+}
+
+type event struct {
+	CreatedAt time.Time
+	DeletedAt *time.Time
+	TTL       time.Duration
+	Note      *string
+	Retries   *int32
+	Payload   []byte
+}
+
+func TestWellKnownTypes(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	src := pbEvent{
+		CreatedAt: timestamppb.New(created),
+		TTL:       durationpb.New(90 * time.Second),
+		Note:      wrapperspb.String("urgent"),
+		Payload:   wrapperspb.Bytes([]byte("raw")),
+	}
+
+	var dst event
+	require.NoError(t, mapper.Copy(&dst, src, protomap.WithWellKnownTypes()))
+	assert.True(t, created.Equal(dst.CreatedAt))
+	assert.Nil(t, dst.DeletedAt)
+	assert.Equal(t, 90*time.Second, dst.TTL)
+	require.NotNil(t, dst.Note)
+	assert.Equal(t, "urgent", *dst.Note)
+	assert.Nil(t, dst.Retries)
+	assert.Equal(t, []byte("raw"), dst.Payload)
+
+	retries := int32(3)
+	dst.DeletedAt = &created
+	dst.Retries = &retries
+	var back pbEvent
+	require.NoError(t, mapper.Copy(&back, dst, protomap.WithWellKnownTypes()))
+	assert.True(t, created.Equal(back.CreatedAt.AsTime()))
+	assert.True(t, created.Equal(back.DeletedAt.AsTime()))
+	assert.Equal(t, 90*time.Second, back.TTL.AsDuration())
+	assert.Equal(t, "urgent", back.Note.GetValue())
+	assert.Equal(t, int32(3), back.Retries.GetValue())
+	assert.Equal(t, []byte("raw"), back.Payload.GetValue())
+
+	invalid := pbEvent{CreatedAt: &timestamppb.Timestamp{Nanos: -1}}
+	assert.Error(t, mapper.Copy(&dst, invalid, protomap.WithWellKnownTypes()))
+}
+
+func TestWellKnownTypesKeepOtherConverters(t *testing.T) {
+	type pbAudit struct {
+		At     *timestamppb.Timestamp
+		Actor  *wrapperspb.StringValue
+		Reason string
+	}
+	type audit struct {
+		At     time.Time
+		Actor  *string
+		Reason string
+	}
+	type auditView struct {
+		At     string
+		Reason string
+	}
+
+	// A mapper shared by the protobuf layer and views formatting times
+	m := mapper.NewMapper(
+		mapper.WithContextConverter(reflect.TypeOf(time.Time{}), func(_ context.Context, v reflect.Value) (reflect.Value, error) {
+			return reflect.ValueOf(v.Interface().(time.Time).Format(time.DateOnly)), nil
+		}),
+		mapper.WithCustomConverter(reflect.TypeOf(""), func(v reflect.Value) (reflect.Value, error) {
+			return reflect.ValueOf(strings.TrimSpace(v.String())), nil
+		}),
+		protomap.WithWellKnownTypes(),
+	)
+
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	var a audit
+	require.NoError(t, m.Map(&a, pbAudit{At: timestamppb.New(at), Actor: wrapperspb.String("ada"), Reason: "  typo  "}))
+	assert.True(t, at.Equal(a.At))
+	require.NotNil(t, a.Actor)
+	assert.Equal(t, "ada", *a.Actor)
+	assert.Equal(t, "typo", a.Reason)
+
+	var view auditView
+	require.NoError(t, m.Map(&view, a))
+	assert.Equal(t, auditView{At: "2024-05-01", Reason: "typo"}, view)
+
+	var back pbAudit
+	require.NoError(t, m.Map(&back, a))
+	assert.True(t, at.Equal(back.At.AsTime()))
+	assert.Equal(t, "ada", back.Actor.GetValue())
+}
+
+type value struct {
+	NumberValue *float64
+	StringValue *string
+	BoolValue   *bool
+	ListValue   *list
+}
+
+type list struct {
+	Values []value
+}
+
+func TestOneofs(t *testing.T) {
+	opts := []mapper.Option{protomap.WithWellKnownTypes(), protomap.WithOneofs(&structpb.Value{})}
+
+	var dst value
+	require.NoError(t, mapper.Copy(&dst, structpb.NewStringValue("hi"), opts...))
+	require.NotNil(t, dst.StringValue)
+	assert.Equal(t, "hi", *dst.StringValue)
+	assert.Nil(t, dst.NumberValue)
+	assert.Nil(t, dst.BoolValue)
+
+	src, err := structpb.NewValue([]any{1.5, true})
+	require.NoError(t, err)
+	dst = value{}
+	require.NoError(t, mapper.Copy(&dst, src, opts...))
+	require.NotNil(t, dst.ListValue)
+	require.Len(t, dst.ListValue.Values, 2)
+	assert.Equal(t, 1.5, *dst.ListValue.Values[0].NumberValue)
+	assert.True(t, *dst.ListValue.Values[1].BoolValue)
+
+	back := &structpb.Value{}
+	require.NoError(t, mapper.Copy(back, dst, opts...))
+	assert.Equal(t, []any{1.5, true}, back.AsInterface())
+
+	n := 2.0
+	back = &structpb.Value{}
+	require.NoError(t, mapper.Copy(back, value{NumberValue: &n}, opts...))
+	assert.Equal(t, 2.0, back.GetNumberValue())
+}
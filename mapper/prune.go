@@ -0,0 +1,46 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements pruning of empty destination values.
+package mapper
+
+import "reflect"
+
+// EmptyCollectionPolicy selects how WithPruneEmpty treats empty maps and
+// slices.
+//
+// The zero value is EmptyCollectionKeep.
+type EmptyCollectionPolicy int
+
+const (
+	// EmptyCollectionKeep leaves empty maps and slices as mapped.
+	EmptyCollectionKeep EmptyCollectionPolicy = iota
+
+	// EmptyCollectionNil sets empty maps and slices to nil.
+	EmptyCollectionNil
+)
+
+// pruneEmpty sets the struct pointer fields of dst whose pointees are zero
+// back to nil, along with empty maps and slices under EmptyCollectionNil.
+// Nested structs are pruned first, as they are mapped, so a pointer to a
+// struct that only held pruned values is pruned as well.
+func (ctx *context) pruneEmpty(dst reflect.Value) {
+	if !ctx.config.PruneEmpty || ctx.structural {
+		return
+	}
+
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Ptr:
+			if !field.IsNil() && field.Type().Elem().Kind() == reflect.Struct && isZero(ctx.config, field.Elem()) {
+				field.SetZero()
+			}
+		case reflect.Map, reflect.Slice:
+			if ctx.config.EmptyCollectionPolicy == EmptyCollectionNil && !field.IsNil() && field.Len() == 0 {
+				field.SetZero()
+			}
+		}
+	}
+}
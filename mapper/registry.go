@@ -0,0 +1,86 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the registry of named mapping profiles.
+package mapper
+
+import (
+	gocontext "context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds named mapping profiles, each a Mapper built from the
+// registry's base options followed by the profile's own. Services with
+// several mapping conventions (public API, internal events, storage)
+// register them once at startup and select them by name. A Registry is
+// safe for concurrent use; its zero value has no base options.
+//
+// Example:
+//
+//	reg := mapper.NewRegistry(mapper.WithCaseSensitive(false))
+//	reg.Register("api", mapper.WithJSONTag(true), mapper.WithVisibility("public"))
+//	reg.Register("internal", mapper.WithDeepCopy(true))
+//
+//	err := reg.Map("api", &dto, user)
+type Registry struct {
+	mu       sync.RWMutex
+	base     []Option
+	profiles map[string]*Mapper
+}
+
+// NewRegistry returns an empty registry whose profiles all start from the
+// base options.
+func NewRegistry(base ...Option) *Registry {
+	return &Registry{base: base}
+}
+
+// Register builds the profile name from the base options followed by opts,
+// replacing any profile registered under the same name.
+func (r *Registry) Register(name string, opts ...Option) {
+	m := NewMapper(append(append([]Option(nil), r.base...), opts...)...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.profiles == nil {
+		r.profiles = make(map[string]*Mapper)
+	}
+	r.profiles[name] = m
+}
+
+// Mapper returns the Mapper of the profile name, e.g. for typed or
+// collection helpers, and whether it is registered.
+func (r *Registry) Mapper(name string) (*Mapper, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.profiles[name]
+	return m, ok
+}
+
+// Profiles returns the names of the registered profiles in sorted order.
+func (r *Registry) Profiles() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Map maps src into dst with the profile name. It returns ErrUnknownProfile
+// if no such profile is registered.
+func (r *Registry) Map(name string, dst, src interface{}) error {
+	return r.MapContext(gocontext.Background(), name, dst, src)
+}
+
+// MapContext maps src into dst with the profile name like
+// Mapper.MapContext. It returns ErrUnknownProfile if no such profile is
+// registered.
+func (r *Registry) MapContext(goctx gocontext.Context, name string, dst, src interface{}) error {
+	m, ok := r.Mapper(name)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+	}
+	return m.MapContext(goctx, dst, src)
+}
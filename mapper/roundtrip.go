@@ -0,0 +1,204 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements detection of values lost in round-trip mappings.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// FieldLoss is a source value that did not survive a round trip.
+type FieldLoss struct {
+	// Path is the path of the value in the source, e.g. "Order.Total" or
+	// `Order.Tags["gift"]`.
+	Path string
+
+	// Original is the sample value, and RoundTrip the value mapped back
+	// from the destination. Either is nil for values missing on that side.
+	Original  interface{}
+	RoundTrip interface{}
+}
+
+// String returns a readable description of the loss.
+func (l FieldLoss) String() string {
+	return fmt.Sprintf("%s: %v became %v", l.Path, l.Original, l.RoundTrip)
+}
+
+// LossReport lists the values lost in a round trip, in field order.
+type LossReport struct {
+	Losses []FieldLoss
+}
+
+// Lossless reports whether every value survived the round trip.
+func (r LossReport) Lossless() bool {
+	return len(r.Losses) == 0
+}
+
+// Paths returns the paths of the lost values.
+func (r LossReport) Paths() []string {
+	paths := make([]string, len(r.Losses))
+	for i, loss := range r.Losses {
+		paths[i] = loss.Path
+	}
+	return paths
+}
+
+// CheckRoundTrip maps sample onto D and back onto S with the given options
+// and reports the values that differ from sample afterwards: fields with no
+// counterpart in D, conversions that drop precision, unmapped map keys and
+// so on. It returns an error only if either mapping fails.
+//
+// Exported fields are compared recursively; unexported fields are ignored
+// and time.Time values are compared with Equal. Use it in tests to pin down
+// which conversions of a DTO are lossy.
+//
+// Example:
+//
+//	report, err := mapper.CheckRoundTrip[Order, OrderDTO](sampleOrder)
+//	require.NoError(t, err)
+//	assert.Equal(t, []string{"Order.InternalNotes"}, report.Paths())
+func CheckRoundTrip[S, D any](sample S, opts ...Option) (LossReport, error) {
+	m := withDefaults(opts)
+
+	var dst D
+	if err := m.Map(&dst, sample); err != nil {
+		return LossReport{}, err
+	}
+	var back S
+	if err := m.Map(&back, dst); err != nil {
+		return LossReport{}, err
+	}
+
+	c := lossComparer{seen: make(map[visitKey]bool)}
+	original := reflect.ValueOf(&sample).Elem()
+	c.compare(rootPathName(original.Type()), original, reflect.ValueOf(&back).Elem())
+	return LossReport{Losses: c.losses}, nil
+}
+
+var timeValueType = reflect.TypeOf(time.Time{})
+
+// lossComparer compares a sample with its round-tripped copy.
+type lossComparer struct {
+	losses []FieldLoss
+
+	// seen holds the sample pointers already compared, so cyclic samples
+	// terminate
+	seen map[visitKey]bool
+}
+
+// lose records that the value at path did not survive.
+func (c *lossComparer) lose(path string, original, roundTrip reflect.Value) {
+	c.losses = append(c.losses, FieldLoss{
+		Path:      path,
+		Original:  interfaceOf(original),
+		RoundTrip: interfaceOf(roundTrip),
+	})
+}
+
+// interfaceOf returns the value held by v, or nil.
+func interfaceOf(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// compare records the differences between the sample value a and its
+// round-tripped copy b, which have the same type.
+func (c *lossComparer) compare(path string, a, b reflect.Value) {
+	if a.Type() == timeValueType {
+		if !a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+			c.lose(path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				c.lose(path, a, b)
+			}
+			return
+		}
+		key := visitKey{ptr: a.Pointer(), typ: a.Type()}
+		if c.seen[key] {
+			return
+		}
+		c.seen[key] = true
+		c.compare(path, a.Elem(), b.Elem())
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() || a.Elem().Type() != b.Elem().Type() {
+			if !a.IsNil() || !b.IsNil() {
+				c.lose(path, a, b)
+			}
+			return
+		}
+		c.compare(path, a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			c.compare(path+"."+field.Name, a.Field(i), b.Field(i))
+		}
+
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && (a.IsNil() != b.IsNil() || a.Len() != b.Len()) {
+			c.lose(path, a, b)
+			return
+		}
+		for i := 0; i < a.Len(); i++ {
+			c.compare(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i))
+		}
+
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() {
+			c.lose(path, a, b)
+			return
+		}
+		for _, key := range sortedKeys(a, b) {
+			keyPath := path + mapKeySegment(key)
+			original, roundTrip := a.MapIndex(key), b.MapIndex(key)
+			if original.IsValid() && roundTrip.IsValid() {
+				c.compare(keyPath, original, roundTrip)
+			} else {
+				c.lose(keyPath, original, roundTrip)
+			}
+		}
+
+	default:
+		if a.CanInterface() && !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			c.lose(path, a, b)
+		}
+	}
+}
+
+// sortedKeys returns the keys of the maps a and b, ordered by their path
+// segment so reports are stable.
+func sortedKeys(a, b reflect.Value) []reflect.Value {
+	keys := a.MapKeys()
+	for _, key := range b.MapKeys() {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return mapKeySegment(keys[i]) < mapKeySegment(keys[j])
+	})
+	return keys
+}
+
+// mapKeySegment formats a map key as a path segment, like fieldPath.
+func mapKeySegment(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return fmt.Sprintf("[%q]", key.String())
+	}
+	return fmt.Sprintf("[%v]", key)
+}
@@ -0,0 +1,27 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements shallow copying of reference values.
+package mapper
+
+import "reflect"
+
+// ShallowTagOption assigns a pointer, slice or map field directly instead
+// of copying it, so the destination shares the source's pointee, backing
+// array or map, e.g. `mapper:",shallow"`. It may be set on the source or
+// the destination field, and applies only when both fields have the same
+// type; fields of different types are mapped as usual.
+const ShallowTagOption = "shallow"
+
+// share assigns src to dst by reference if src is a pointer, slice or map
+// of dst's type, and reports whether it did.
+func (ctx *context) share(dst, src reflect.Value) bool {
+	switch src.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+	default:
+		return false
+	}
+	if !dst.CanSet() || dst.Type() != src.Type() {
+		return false
+	}
+	dst.Set(src)
+	return true
+}
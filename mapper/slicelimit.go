@@ -0,0 +1,36 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the slice capacity limit.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SliceOverflowPolicy selects how slices longer than the limit set with
+// WithMaxSliceCapacity are mapped.
+//
+// The zero value is SliceOverflowError.
+type SliceOverflowPolicy int
+
+const (
+	// SliceOverflowError fails the slice with ErrSliceTooLarge, leaving
+	// the destination untouched.
+	SliceOverflowError SliceOverflowPolicy = iota
+
+	// SliceOverflowTruncate maps only the first MaxSliceCapacity elements.
+	SliceOverflowTruncate
+)
+
+// sliceLength returns the number of elements of src to map onto the
+// destination slice dst, applying MaxSliceCapacity.
+func (ctx *context) sliceLength(dst, src reflect.Value) (int, error) {
+	limit := ctx.config.MaxSliceCapacity
+	if limit <= 0 || dst.Kind() != reflect.Slice || src.Len() <= limit {
+		return src.Len(), nil
+	}
+	if ctx.config.SliceOverflowPolicy == SliceOverflowTruncate {
+		return limit, nil
+	}
+	return 0, ctx.mapError("mapSlice", dst, src, "", "", fmt.Errorf("%w: %d elements exceed the limit of %d", ErrSliceTooLarge, src.Len(), limit))
+}
@@ -0,0 +1,86 @@
+// Package sqlmap maps database/sql query results onto structs with the
+// mapper's field matching rules: tag names, case-insensitive matching,
+// field mappings and the field name mapper. It is a lightweight
+// row-to-struct mapper built on the standard library alone.
+//
+// Example:
+//
+//	rows, err := db.QueryContext(ctx, "SELECT id, full_name, created_at FROM users")
+//	if err != nil {
+//	    return err
+//	}
+//	defer rows.Close()
+//
+//	var users []User
+//	err = sqlmap.ScanRows(rows, &users, mapper.WithFieldNameMapper(snakeToCamel))
+package sqlmap
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/fbarikzehi/gomap/mapper"
+)
+
+// ScanRows scans every remaining row of rows and appends it to *dst as a
+// new T. Each row is mapped from its columns, keyed by column name, so
+// columns match struct fields the way map keys do: by tag name (with
+// WithTagName or WithJSONTag), then by field name. Column names are passed
+// through the configured FieldNameMapper first, and matching is case
+// insensitive unless WithCaseSensitive(true) is given. Columns without a
+// matching field are ignored, or captured by an overflow field.
+//
+// ScanRows does not close rows. It returns the first scan or mapping
+// error, prefixed with the row number, or rows.Err().
+func ScanRows[T any](rows *sql.Rows, dst *[]T, opts ...mapper.Option) error {
+	if rows == nil || dst == nil {
+		return mapper.ErrNilPointer
+	}
+
+	opts = append([]mapper.Option{mapper.WithCaseSensitive(false)}, opts...)
+	cfg := &mapper.Config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	m := mapper.NewMapper(opts...)
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	keys := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column
+		if cfg.FieldNameMapper != nil {
+			keys[i] = cfg.FieldNameMapper(column)
+		}
+	}
+
+	values := make([]interface{}, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+
+	for n := 0; rows.Next(); n++ {
+		if err := rows.Scan(targets...); err != nil {
+			return fmt.Errorf("row %d: %w", n, err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, value := range values {
+			// Drivers may reuse byte buffers between rows
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			row[keys[i]] = value
+		}
+
+		var item T
+		if err := m.Map(&item, row); err != nil {
+			return fmt.Errorf("row %d: %w", n, err)
+		}
+		*dst = append(*dst, item)
+	}
+	return rows.Err()
+}
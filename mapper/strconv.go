@@ -0,0 +1,98 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements string ↔ number and bool conversion.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// convertString converts between strings and numeric or bool values using
+// strconv. It reports whether the pair of values was handled. Empty
+// strings convert to the zero value; other unparsable strings and values
+// out of the destination's range fail with ErrTypeMismatch.
+func (ctx *context) convertString(dst, src reflect.Value) (bool, error) {
+	switch {
+	case src.Kind() == reflect.String && dst.Kind() != reflect.String:
+		handled, err := ctx.parseString(dst, src.String())
+		if handled && err == nil {
+			// Decimal strings round to the nearest binary float
+			ctx.auditConversion(src.Type(), dst.Type(), dst.CanFloat())
+		}
+		return handled, err
+	case dst.Kind() == reflect.String && src.Kind() != reflect.String:
+		s, ok := formatBasic(src)
+		if !ok {
+			return false, nil
+		}
+		dst.SetString(s)
+		ctx.auditConversion(src.Type(), dst.Type(), false)
+		return true, nil
+	}
+	return false, nil
+}
+
+// parseString parses s into a numeric or bool destination.
+func (ctx *context) parseString(dst reflect.Value, s string) (bool, error) {
+	s = strings.TrimSpace(s)
+
+	var err error
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if s != "" {
+			n, err = strconv.ParseInt(s, 10, dst.Type().Bits())
+		}
+		if err == nil {
+			dst.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		if s != "" {
+			n, err = strconv.ParseUint(s, 10, dst.Type().Bits())
+		}
+		if err == nil {
+			dst.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if s != "" {
+			f, err = strconv.ParseFloat(s, dst.Type().Bits())
+		}
+		if err == nil {
+			dst.SetFloat(f)
+		}
+	case reflect.Bool:
+		var b bool
+		if s != "" {
+			b, err = strconv.ParseBool(s)
+		}
+		if err == nil {
+			dst.SetBool(b)
+		}
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return true, invalidValue(fmt.Errorf("%w: cannot convert %q to %s", ErrTypeMismatch, s, dst.Type()))
+	}
+	return true, nil
+}
+
+// formatBasic formats a numeric or bool value as a string.
+func formatBasic(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	}
+	return "", false
+}
@@ -0,0 +1,64 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements element-wise mapping of streams and iterators.
+package mapper
+
+import (
+	gocontext "context"
+	"fmt"
+	"iter"
+)
+
+// MapStream maps the values received from in with m, one at a time, and
+// sends them on the returned channel, so record pipelines (message
+// consumers, database cursors) hold one element in flight instead of a
+// whole slice. It behaves like TypedMapper.MapChan; Go methods cannot
+// introduce the element type parameters, hence the function form.
+//
+// Example:
+//
+//	dtos, errs := mapper.MapStream[Event, EventDTO](m, ctx, events)
+//	for dto := range dtos {
+//	    publish(dto)
+//	}
+//	if err := <-errs; err != nil {
+//	    ...
+//	}
+func MapStream[S, D any](m *Mapper, goctx gocontext.Context, in <-chan S) (<-chan D, <-chan error) {
+	return (&TypedMapper[S, D]{mapper: m}).MapChan(goctx, in)
+}
+
+// MapSeq lazily maps the values of seq into new D values, configured with
+// the provided options. See TypedMapper.MapSeq.
+//
+// Example:
+//
+//	for dto, err := range mapper.MapSeq[Row, RowDTO](rows.All()) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+func MapSeq[S, D any](seq iter.Seq[S], opts ...Option) iter.Seq2[D, error] {
+	return New[S, D](opts...).MapSeq(seq)
+}
+
+// MapSeq lazily maps the values of seq into new D values as they are
+// pulled. Each mapped value is yielded with a nil error; a failing item is
+// yielded with its error, prefixed with its index, and ends the sequence.
+func (t *TypedMapper[S, D]) MapSeq(seq iter.Seq[S]) iter.Seq2[D, error] {
+	return func(yield func(D, error) bool) {
+		i := 0
+		for item := range seq {
+			dst, err := t.Map(item)
+			if err != nil {
+				var zero D
+				yield(zero, fmt.Errorf("item %d: %w", i, err))
+				return
+			}
+			if !yield(dst, nil) {
+				return
+			}
+			i++
+		}
+	}
+}
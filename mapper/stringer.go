@@ -0,0 +1,30 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the fmt.Stringer fallback for string destinations.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// mapStringer renders a source implementing fmt.Stringer into a string
+// destination with its String method. Sources of string kind convert
+// directly instead. It reports whether the value was handled.
+func (ctx *context) mapStringer(dst, src reflect.Value) bool {
+	if dst.Kind() != reflect.String || src.Kind() == reflect.String || !dst.CanSet() {
+		return false
+	}
+
+	if !src.Type().Implements(stringerType) && src.CanAddr() && src.Addr().Type().Implements(stringerType) {
+		src = src.Addr()
+	}
+	if !src.Type().Implements(stringerType) || isTypedNil(src) || !src.CanInterface() {
+		return false
+	}
+
+	dst.SetString(src.Interface().(fmt.Stringer).String())
+	ctx.internString(dst)
+	return true
+}
@@ -0,0 +1,256 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements mapping between structs and string-keyed maps.
+package mapper
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// structToMap maps the fields of a struct into a string-keyed map, keyed
+// by destination field name as resolved for struct-to-struct mapping (field
+// mappings, tags, name mapper). Fields of embedded structs are promoted
+// into the map, and overflow entries are carried over. When the map holds
+// interface values, nested structs become nested maps of the same type.
+func (ctx *context) structToMap(dst, src reflect.Value) error {
+	if dst.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+	if dst.IsNil() {
+		if !dst.CanSet() {
+			return nil
+		}
+		dst.Set(reflect.MakeMap(dst.Type()))
+	}
+
+	srcType := src.Type()
+	overflow := ctx.overflowIndex(srcType)
+	for i := 0; i < srcType.NumField(); i++ {
+		srcField := srcType.Field(i)
+		srcValue := src.Field(i)
+
+		if i == overflow {
+			iter := srcValue.MapRange()
+			for iter.Next() {
+				key := reflect.ValueOf(iter.Key().String()).Convert(dst.Type().Key())
+				if !dst.MapIndex(key).IsValid() {
+					ctx.setMapEntry(dst, key, iter.Value(), fieldPlan{srcName: iter.Key().String(), dstName: key.String()})
+				}
+			}
+			continue
+		}
+
+		if ctx.skipField(srcField) || !ctx.visible(ctx.fieldRoles(srcField)) {
+			continue
+		}
+
+		// Promote the fields of embedded structs, as encoding/json does
+		if srcField.Anonymous && ctx.tagName(srcField) == "" {
+			if embedded, ok := derefStruct(srcValue); ok {
+				if err := ctx.structToMap(dst, embedded); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if srcField.PkgPath != "" {
+			continue
+		}
+
+		if ctx.hasTagOption(srcField, AtomicTagOption) {
+			loaded, err := ctx.loadAtomicField(srcValue)
+			if err != nil {
+				ctx.addError(ctx.mapError("mapStruct", dst, srcValue, srcField.Name, "", err))
+				continue
+			}
+			srcValue = loaded
+		}
+
+		name, _, _ := strings.Cut(ctx.getDestFieldName(srcField), ",")
+		key := reflect.ValueOf(name).Convert(dst.Type().Key())
+		ctx.pushPath(srcField.Name)
+		ctx.setMapEntry(dst, key, srcValue, fieldPlan{
+			srcName: srcField.Name,
+			dstName: name,
+			i18nKey: ctx.fieldI18nKey(srcField, reflect.StructField{}),
+		})
+		ctx.popPath()
+	}
+
+	return nil
+}
+
+// setMapEntry maps a struct field value into a map entry.
+func (ctx *context) setMapEntry(dst, key, srcValue reflect.Value, field fieldPlan) {
+	if ctx.ignoredField(field.dstName) {
+		return
+	}
+
+	slot := reflect.New(dst.Type().Elem()).Elem()
+
+	nested, isStruct := derefStruct(srcValue)
+	switch {
+	case slot.Kind() == reflect.Interface && isStruct && nested.Type() != timeType && !ctx.isCustomType(nested.Type()):
+		entry := reflect.MakeMap(dst.Type())
+		if err := ctx.structToMap(entry, nested); err != nil {
+			ctx.addError(ctx.mapError("mapStruct", entry, nested, field.srcName, field.dstName, err))
+			return
+		}
+		slot.Set(entry)
+	default:
+		ctx.setField(slot, srcValue, field)
+	}
+
+	dst.SetMapIndex(key, slot)
+}
+
+// isCustomType reports whether t has a converter registered, in which case
+// values of t are not expanded into nested maps.
+func (ctx *context) isCustomType(t reflect.Type) bool {
+	_, custom := ctx.config.CustomConverters[t]
+	_, contextual := ctx.config.ContextConverters[t]
+	if custom || contextual {
+		return true
+	}
+	for pair := range ctx.config.PairConverters {
+		if pair.Source == t {
+			return true
+		}
+	}
+	return false
+}
+
+// mapToStruct populates a struct (or pointer to struct) from a string-keyed
+// map. Keys match destination fields by tag name (when a tag name or JSON
+// tags are configured), then by field name or dotted path, honoring field
+// mappings and case-insensitive matching. Unmatched keys are captured into
+// the destination's overflow field, if any.
+func (ctx *context) mapToStruct(dst, src reflect.Value) error {
+	if src.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			if !dst.CanSet() || dst.Type().Elem().Kind() != reflect.Struct {
+				return nil
+			}
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	if dst.Kind() != reflect.Struct || dst.Type() == timeType {
+		return nil
+	}
+
+	dstType := dst.Type()
+	overflow := ctx.overflowIndex(dstType)
+
+	iter := src.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		name := key
+		if mapped, ok := ctx.config.FieldMappings[key]; ok {
+			name = mapped
+		}
+
+		dstField, index, found := ctx.fieldForKey(dstType, name)
+		if !found || (len(index) == 1 && index[0] == overflow) {
+			if overflow >= 0 {
+				if err := ctx.captureOverflow(dst.Field(overflow), key, iter.Value()); err != nil {
+					ctx.addError(ctx.mapError("mapMap", dst.Field(overflow), iter.Value(), key, "", err))
+				}
+			} else {
+				ctx.warn(WarningUnknownKey, "key %q matches no field of %s", key, dstType)
+			}
+			continue
+		}
+
+		if !ctx.visible(ctx.fieldRoles(dstField)) {
+			continue
+		}
+
+		dstValue := fieldByIndexAlloc(dst, index)
+		if !dstValue.CanSet() {
+			continue
+		}
+
+		ctx.pushPath(key)
+		ctx.setField(dstValue, iter.Value(), fieldPlan{
+			srcName:   key,
+			dstName:   dstField.Name,
+			dstAtomic: ctx.hasTagOption(dstField, AtomicTagOption),
+			unit:      ctx.fieldUnitConversion(reflect.StructField{}, dstField),
+			i18nKey:   ctx.fieldI18nKey(reflect.StructField{}, dstField),
+		})
+		ctx.popPath()
+	}
+
+	return nil
+}
+
+// fieldForKey finds the destination field for a map key, first by tag
+// name and then by field name or dotted path.
+func (ctx *context) fieldForKey(dstType reflect.Type, key string) (reflect.StructField, []int, bool) {
+	if ctx.config.TagName != "" || ctx.config.hasEncodingTags() {
+		for i := 0; i < dstType.NumField(); i++ {
+			field := dstType.Field(i)
+			name := ctx.tagName(field)
+			if name == "" || ctx.excludedField(field) {
+				continue
+			}
+			if name == key || (!ctx.config.CaseSensitive && reflectutil.EqualFold(name, key)) {
+				return field, field.Index, true
+			}
+		}
+	}
+	return ctx.resolvePath(dstType, key)
+}
+
+// tagName returns the name given to a field by its configured mapping tag
+// or encoding tag, or "" if it has none.
+func (ctx *context) tagName(field reflect.StructField) string {
+	if ctx.config.TagName != "" {
+		if name := parseTag(field.Tag.Get(ctx.config.TagName)).name; name != "" && name != "-" {
+			return name
+		}
+	}
+	return ctx.encodingTagName(field)
+}
+
+// hasEncodingTags reports whether fields are matched by JSON, YAML or BSON
+// tag names.
+func (c *Config) hasEncodingTags() bool {
+	return c.UseJSONTag || c.UseYAMLTag || c.UseBSONTag
+}
+
+// encodingTagName returns the name given to a field by its JSON tag with
+// UseJSONTag, its YAML tag with UseYAMLTag or its BSON tag with UseBSONTag,
+// in that order, or "" if it has none.
+func (ctx *context) encodingTagName(field reflect.StructField) string {
+	if ctx.config.UseJSONTag {
+		if name := keyTagName(field, "json"); name != "" {
+			return name
+		}
+	}
+	if ctx.config.UseYAMLTag {
+		if name := keyTagName(field, "yaml"); name != "" {
+			return name
+		}
+	}
+	if ctx.config.UseBSONTag {
+		return keyTagName(field, "bson")
+	}
+	return ""
+}
+
+// keyTagName returns the name given to a field by the tag key, without
+// options such as omitempty or flow, or "" if it has none.
+func keyTagName(field reflect.StructField, key string) string {
+	name := parseTag(field.Tag.Get(key)).name
+	if name == "-" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,127 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the required, omitempty and default tag options.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// fieldDefault is a destination field tagged with DefaultTagOption.
+type fieldDefault struct {
+	index []int
+	name  string
+	value string
+}
+
+// fieldDefaults returns the exported fields of t tagged with a default.
+func (ctx *context) fieldDefaults(t reflect.Type) []fieldDefault {
+	var defaults []fieldDefault
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if value, ok := ctx.tagOptionValue(field, DefaultTagOption); ok {
+			defaults = append(defaults, fieldDefault{index: field.Index, name: field.Name, value: value})
+		}
+	}
+	return defaults
+}
+
+// applyDefaults sets the default fields of dst that are still zero after
+// mapping, under the configuration's zero rules. Defaults that cannot be parsed into their field are reported
+// as field errors.
+func (ctx *context) applyDefaults(dst reflect.Value, defaults []fieldDefault) {
+	for _, d := range defaults {
+		field := dst.FieldByIndex(d.index)
+		if !field.CanSet() || !isZero(ctx.config, field) {
+			continue
+		}
+		if err := ctx.setDefault(field, d.value); err != nil {
+			ctx.fieldError(err, field, reflect.Value{}, "", d.name)
+		}
+	}
+}
+
+// setDefault parses value into dst.
+func (ctx *context) setDefault(dst reflect.Value, value string) error {
+	switch {
+	case dst.Kind() == reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := ctx.setDefault(elem.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case dst.Type() == timeType:
+		if value == DefaultNow {
+			dst.Set(reflect.ValueOf(ctx.now()))
+			return nil
+		}
+		t, err := time.ParseInLocation(ctx.timeLayout(), value, ctx.timeZone())
+		if err != nil {
+			return invalidValue(fmt.Errorf("%w: default %q: %v", ErrTypeMismatch, value, err))
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	case dst.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return invalidValue(fmt.Errorf("%w: default %q: %v", ErrTypeMismatch, value, err))
+		}
+		dst.SetInt(int64(d))
+		return nil
+	case dst.Kind() == reflect.String:
+		dst.SetString(value)
+		return nil
+	}
+
+	if handled, err := ctx.parseString(dst, value); handled {
+		return err
+	}
+	return fmt.Errorf("%w: no default for %s", ErrUnsupportedType, dst.Type())
+}
+
+// missingRequired returns the exported fields of dstType tagged with
+// RequiredTagOption that no planned field or member rule populates.
+func (ctx *context) missingRequired(plan *structPlan, dstType reflect.Type) []reflect.StructField {
+	var missing []reflect.StructField
+	for i := 0; i < dstType.NumField(); i++ {
+		field := dstType.Field(i)
+		if field.PkgPath != "" || !ctx.hasTagOption(field, RequiredTagOption) || plan.populates(field.Index[0]) {
+			continue
+		}
+		missing = append(missing, field)
+	}
+	return missing
+}
+
+// populates reports whether a planned field or member rule writes into the
+// destination field with top-level index i.
+func (plan *structPlan) populates(i int) bool {
+	for _, field := range plan.fields {
+		if field.dstIndex != nil && field.dstIndex[0] == i {
+			return true
+		}
+	}
+	for _, member := range plan.members {
+		for _, index := range member.dstIndexes {
+			if index[0] == i {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reportMissing reports the required destination fields without a source
+// counterpart.
+func (ctx *context) reportMissing(dst, src reflect.Value, missing []reflect.StructField) {
+	for _, field := range missing {
+		ctx.fieldError(fmt.Errorf("%w: no source for %s", ErrRequiredField, field.Name), dst, src, "", field.Name)
+	}
+}
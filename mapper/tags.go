@@ -0,0 +1,118 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements parsing of mapper struct tags and their options.
+package mapper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Tag options understood on mapper struct tags, e.g.
+// `mapper:"count,required"` or `mapper:",default=10"`.
+const (
+	// RequiredTagOption makes a zero or missing source value an
+	// ErrRequiredField error. It may be set on the source or the
+	// destination field.
+	RequiredTagOption = "required"
+
+	// OmitEmptyTagOption leaves the destination untouched when the source
+	// value is zero. It may be set on the source or the destination field.
+	OmitEmptyTagOption = "omitempty"
+
+	// DefaultTagOption fills a destination field that is still zero after
+	// mapping, under the mapper's zero rules, with the option value, parsed into the field's type: strings,
+	// numbers, bools, durations, pointers to them, and DefaultNow for time
+	// fields. Values containing commas are quoted: `default='a, b'`.
+	DefaultTagOption = "default"
+)
+
+// fieldTag is a parsed mapper struct tag: a field name followed by
+// comma-separated flag and key=value options.
+type fieldTag struct {
+	name    string
+	options []string
+}
+
+// parseTag parses a mapper struct tag. Options are separated by commas
+// outside single quotes, and the quotes around an option value are
+// removed, so `mapper:"title,default='Hello, world'"` has the default
+// "Hello, world".
+func parseTag(tag string) fieldTag {
+	var (
+		parts  []string
+		part   strings.Builder
+		quoted bool
+	)
+	for _, r := range tag {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			part.WriteRune(r)
+		case r == ',' && !quoted:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteRune(r)
+		}
+	}
+	parts = append(parts, part.String())
+
+	parsed := fieldTag{name: strings.TrimSpace(parts[0])}
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		if k, v, ok := strings.Cut(opt, "="); ok {
+			v = strings.TrimSpace(v)
+			if len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'' {
+				v = v[1 : len(v)-1]
+			}
+			opt = strings.TrimSpace(k) + "=" + v
+		}
+		parsed.options = append(parsed.options, opt)
+	}
+	return parsed
+}
+
+// has reports whether the tag carries the flag option.
+func (t fieldTag) has(option string) bool {
+	for _, opt := range t.options {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the value of the key=value option key.
+func (t fieldTag) value(key string) (string, bool) {
+	for _, opt := range t.options {
+		if k, v, ok := strings.Cut(opt, "="); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// tagKey returns the struct tag key consulted for mapper tag options.
+func (ctx *context) tagKey() string {
+	if ctx.config.TagName != "" {
+		return ctx.config.TagName
+	}
+	return DefaultTagName
+}
+
+// fieldTag returns the parsed mapper tag of field.
+func (ctx *context) fieldTag(field reflect.StructField) fieldTag {
+	return parseTag(field.Tag.Get(ctx.tagKey()))
+}
+
+// hasTagOption reports whether the mapper tag of field carries the given
+// option after its name, e.g. `mapper:"name,option"` or `mapper:",option"`.
+func (ctx *context) hasTagOption(field reflect.StructField, option string) bool {
+	return ctx.fieldTag(field).has(option)
+}
+
+// tagOptionValue returns the value of a key=value option of the mapper tag
+// of field, e.g. "m->km" for `mapper:"distance,unit=m->km"`.
+func (ctx *context) tagOptionValue(field reflect.StructField, key string) (string, bool) {
+	return ctx.fieldTag(field).value(key)
+}
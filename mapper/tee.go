@@ -0,0 +1,49 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements mapping one source into several destinations.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Tee maps src into each of dsts, e.g. the old and new DTO shapes served
+// side by side during an API migration, with the default mapper (see
+// SetDefault). See Mapper.Tee.
+//
+// Example:
+//
+//	var v1 UserV1
+//	var v2 UserV2
+//	err := mapper.Tee(user, &v1, &v2)
+func Tee(src interface{}, dsts ...interface{}) error {
+	return Default().Tee(src, dsts...)
+}
+
+// Tee maps src into each of dsts from one consistent view of it: src is
+// traversed once into a snapshot (see Mapper.Snapshot), which is then
+// mapped into every destination, so changes made to src meanwhile, e.g. by
+// converters or other goroutines holding its source locks, never make the
+// destinations disagree. A failing destination does not stop the others:
+// the errors are returned together as MappingErrors, each prefixed with
+// the index of its destination, so callers can tell which shape failed.
+func (m *Mapper) Tee(src interface{}, dsts ...interface{}) error {
+	if src == nil {
+		return ErrNilPointer
+	}
+	snapshot, err := m.snapshotSource(reflect.ValueOf(src))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, dst := range dsts {
+		if err := m.Map(dst, snapshot.Interface()); err != nil {
+			errs = append(errs, fmt.Errorf("destination %d: %w", i, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &MappingErrors{errs: errs}
+	}
+	return nil
+}
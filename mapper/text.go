@@ -0,0 +1,71 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements conversions through encoding.TextMarshaler and
+// encoding.TextUnmarshaler.
+package mapper
+
+import (
+	"encoding"
+	"reflect"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// mapText converts values implementing encoding.TextMarshaler into string
+// destinations, and strings into destinations implementing
+// encoding.TextUnmarshaler (through a pointer receiver, or as a pointer
+// destination). It reports whether the value was handled.
+func (ctx *context) mapText(dst, src reflect.Value) (bool, error) {
+	if !dst.CanSet() || src.Type() == dst.Type() {
+		return false, nil
+	}
+
+	if dst.Kind() == reflect.String {
+		marshaler, ok := textMarshalerOf(src)
+		if !ok {
+			return false, nil
+		}
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return true, err
+		}
+		dst.SetString(string(text))
+		return true, nil
+	}
+
+	if src.Kind() != reflect.String {
+		return false, nil
+	}
+	target := dst
+	if dst.Kind() == reflect.Ptr {
+		target = reflect.New(dst.Type().Elem()).Elem()
+	}
+	if !reflect.PointerTo(target.Type()).Implements(textUnmarshalerType) {
+		return false, nil
+	}
+	if err := target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(src.String())); err != nil {
+		return true, err
+	}
+	if dst.Kind() == reflect.Ptr {
+		dst.Set(target.Addr())
+	}
+	return true, nil
+}
+
+// textMarshalerOf returns src as an encoding.TextMarshaler, through a
+// pointer to a copy when only the pointer type implements it.
+func textMarshalerOf(src reflect.Value) (encoding.TextMarshaler, bool) {
+	if !src.CanInterface() {
+		return nil, false
+	}
+	if src.Type().Implements(textMarshalerType) {
+		marshaler, ok := src.Interface().(encoding.TextMarshaler)
+		return marshaler, ok
+	}
+	if reflect.PointerTo(src.Type()).Implements(textMarshalerType) {
+		return addressable(src).Addr().Interface().(encoding.TextMarshaler), true
+	}
+	return nil, false
+}
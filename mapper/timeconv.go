@@ -0,0 +1,91 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements layout- and zone-driven time.Time conversions.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// timeConversions reports whether built-in time conversions are enabled.
+func (ctx *context) timeConversions() bool {
+	return ctx.config.TimeLayout != "" || ctx.config.TimeZone != nil
+}
+
+// timeLayout returns the configured layout, defaulting to RFC 3339.
+func (ctx *context) timeLayout() string {
+	if ctx.config.TimeLayout != "" {
+		return ctx.config.TimeLayout
+	}
+	return time.RFC3339
+}
+
+// timeZone returns the configured location, defaulting to UTC.
+func (ctx *context) timeZone() *time.Location {
+	if ctx.config.TimeZone != nil {
+		return ctx.config.TimeZone
+	}
+	return time.UTC
+}
+
+// mapTime converts between time.Time and strings (formatted and parsed
+// with the configured layout) or integer Unix timestamps in seconds, and
+// moves time.Time values into the configured time zone. It reports whether
+// the pair of values was handled.
+func (ctx *context) mapTime(dst, src reflect.Value) (bool, error) {
+	srcTime := src.Type() == timeType
+	dstTime := dst.Type() == timeType
+	if !srcTime && !dstTime {
+		return false, nil
+	}
+
+	if srcTime {
+		t := src.Interface().(time.Time)
+		if ctx.config.TimeZone != nil {
+			t = t.In(ctx.config.TimeZone)
+		}
+
+		switch {
+		case dstTime:
+			if ctx.config.TimeZone == nil {
+				return false, nil
+			}
+			dst.Set(reflect.ValueOf(t))
+		case dst.Kind() == reflect.String:
+			if !t.IsZero() {
+				dst.SetString(t.Format(ctx.timeLayout()))
+			} else {
+				dst.SetString("")
+			}
+		case dst.CanInt():
+			dst.SetInt(t.Unix())
+		default:
+			return false, nil
+		}
+		if !dstTime {
+			// Layouts and Unix seconds may drop precision and the zone
+			ctx.auditConversion(src.Type(), dst.Type(), true)
+		}
+		return true, nil
+	}
+
+	var t time.Time
+	switch {
+	case src.Kind() == reflect.String:
+		if src.String() != "" {
+			parsed, err := time.ParseInLocation(ctx.timeLayout(), src.String(), ctx.timeZone())
+			if err != nil {
+				return true, invalidValue(fmt.Errorf("%w: %v", ErrTypeMismatch, err))
+			}
+			t = parsed
+		}
+	case src.CanInt():
+		t = time.Unix(src.Int(), 0).In(ctx.timeZone())
+	default:
+		return false, nil
+	}
+	dst.Set(reflect.ValueOf(t))
+	ctx.auditConversion(src.Type(), dst.Type(), false)
+	return true, nil
+}
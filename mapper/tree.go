@@ -0,0 +1,148 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements assembly of nested trees from flat rows.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TreeOptions names the fields used by BuildTree. Empty names default to
+// "ID", "ParentID" and "Children".
+type TreeOptions struct {
+	// KeyField is the source field identifying a row.
+	KeyField string
+
+	// ParentField is the source field holding the parent's key. Rows whose
+	// parent key is zero, nil or unknown become roots.
+	ParentField string
+
+	// ChildrenField is the destination field of type []D or []*D that
+	// receives a node's children.
+	ChildrenField string
+}
+
+// BuildTree maps flat rows (with ParentID-style fields) into a tree of D
+// nodes and returns its roots. Each row is converted with the mapper
+// configured by opts; children keep the order of their rows.
+//
+// Returns ErrCircularReference if some rows cannot be reached from a root
+// because their parent keys form a cycle.
+//
+// Example:
+//
+//	type CategoryDTO struct {
+//	    ID       int
+//	    Name     string
+//	    Children []CategoryDTO
+//	}
+//	roots, err := mapper.BuildTree[CategoryRow, CategoryDTO](rows, mapper.TreeOptions{})
+func BuildTree[S, D any](rows []S, tree TreeOptions, opts ...Option) ([]D, error) {
+	if tree.KeyField == "" {
+		tree.KeyField = "ID"
+	}
+	if tree.ParentField == "" {
+		tree.ParentField = "ParentID"
+	}
+	if tree.ChildrenField == "" {
+		tree.ChildrenField = "Children"
+	}
+
+	nodeType := reflect.TypeOf((*D)(nil)).Elem()
+	childrenField, ok := nodeType.FieldByName(tree.ChildrenField)
+	if !ok || childrenField.Type.Kind() != reflect.Slice ||
+		(childrenField.Type.Elem() != nodeType && childrenField.Type.Elem() != reflect.PointerTo(nodeType)) {
+		return nil, fmt.Errorf("%w: %s has no []%s or []*%s field %s", ErrTypeMismatch, nodeType, nodeType, nodeType, tree.ChildrenField)
+	}
+
+	m := NewMapper(opts...)
+	nodes := make([]D, len(rows))
+	keys := make([]reflect.Value, len(rows))
+	index := make(map[interface{}]int, len(rows))
+	for i := range rows {
+		if err := m.Map(&nodes[i], rows[i]); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+
+		key, err := treeField(reflect.ValueOf(rows[i]), tree.KeyField)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		if !key.IsValid() || !key.Type().Comparable() {
+			return nil, fmt.Errorf("row %d: %w: key field %s is nil or not comparable", i, ErrTypeMismatch, tree.KeyField)
+		}
+		keys[i] = key
+		index[key.Interface()] = i
+	}
+
+	var roots []int
+	children := make([][]int, len(rows))
+	for i := range rows {
+		parent, err := treeField(reflect.ValueOf(rows[i]), tree.ParentField)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+
+		parentIndex, found := -1, false
+		if parent.IsValid() && !parent.IsZero() {
+			if parent, ok := convertKey(parent, keys[i].Type()); ok {
+				parentIndex, found = index[parent.Interface()]
+			}
+		}
+		if found {
+			children[parentIndex] = append(children[parentIndex], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	placed := 0
+	var build func(i int) reflect.Value
+	build = func(i int) reflect.Value {
+		placed++
+		node := reflect.New(nodeType)
+		node.Elem().Set(reflect.ValueOf(nodes[i]))
+		if kids := children[i]; len(kids) > 0 {
+			slice := reflect.MakeSlice(childrenField.Type, 0, len(kids))
+			for _, child := range kids {
+				built := build(child)
+				if childrenField.Type.Elem().Kind() != reflect.Ptr {
+					built = built.Elem()
+				}
+				slice = reflect.Append(slice, built)
+			}
+			node.Elem().FieldByIndex(childrenField.Index).Set(slice)
+		}
+		return node
+	}
+
+	result := make([]D, 0, len(roots))
+	for _, root := range roots {
+		result = append(result, build(root).Elem().Interface().(D))
+	}
+	if placed != len(rows) {
+		return nil, fmt.Errorf("%w: %d rows are not reachable from a root", ErrCircularReference, len(rows)-placed)
+	}
+	return result, nil
+}
+
+// treeField reads a named field of a row, dereferencing pointers. It
+// returns the zero Value for nil pointer fields.
+func treeField(row reflect.Value, name string) (reflect.Value, error) {
+	row, ok := derefStruct(row)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w: rows must be structs", ErrUnsupportedType)
+	}
+
+	f := row.FieldByName(name)
+	if !f.IsValid() || !f.CanInterface() {
+		return reflect.Value{}, fmt.Errorf("%w: %s has no exported field %s", ErrTypeMismatch, row.Type(), name)
+	}
+	for f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return reflect.Value{}, nil
+		}
+		f = f.Elem()
+	}
+	return f, nil
+}
@@ -0,0 +1,38 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file defines the generic, type-safe mapper API.
+package mapper
+
+// TypedMapper is a type-safe Mapper for a fixed source and destination
+// type pair. It avoids interface{} plumbing at call sites and lets the
+// compiler catch mismatched arguments.
+//
+// A TypedMapper is safe for concurrent use.
+type TypedMapper[S, D any] struct {
+	mapper *Mapper
+}
+
+// New creates a TypedMapper mapping values of type S into values of type D,
+// configured with the provided options.
+//
+// Example:
+//
+//	users := mapper.New[UserEntity, UserDTO](mapper.WithJSONTag(true))
+//	dto, err := users.Map(entity)
+func New[S, D any](opts ...Option) *TypedMapper[S, D] {
+	return &TypedMapper[S, D]{mapper: NewMapper(opts...)}
+}
+
+// Map maps src into a new value of type D and returns it.
+func (t *TypedMapper[S, D]) Map(src S) (D, error) {
+	var dst D
+	err := t.mapper.Map(&dst, src)
+	return dst, err
+}
+
+// MapInto maps src into the existing destination value pointed to by dst.
+func (t *TypedMapper[S, D]) MapInto(dst *D, src S) error {
+	if dst == nil {
+		return ErrNilPointer
+	}
+	return t.mapper.Map(dst, src)
+}
@@ -0,0 +1,63 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements the handling of interfaces holding typed nils.
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// TypedNilPolicy selects how a source interface holding a typed nil (e.g.
+// an error interface holding a nil *MyError) is mapped.
+//
+// The zero value is TypedNilAsNil.
+type TypedNilPolicy int
+
+const (
+	// TypedNilAsNil treats the value as nil: the destination is set to its
+	// zero value, so interface destinations compare equal to nil.
+	TypedNilAsNil TypedNilPolicy = iota
+
+	// TypedNilKeep preserves the typed nil, so interface destinations hold
+	// a nil pointer of the source's dynamic type and compare non-nil.
+	TypedNilKeep
+
+	// TypedNilSkip leaves the destination untouched.
+	TypedNilSkip
+
+	// TypedNilError fails the field with ErrTypedNil.
+	TypedNilError
+)
+
+// isTypedNil reports whether src is a non-nil interface holding a nil value.
+func isTypedNil(src reflect.Value) bool {
+	if src.Kind() != reflect.Interface || src.IsNil() {
+		return false
+	}
+	elem := src.Elem()
+	return reflectutil.IsNillable(elem.Kind()) && elem.IsNil()
+}
+
+// mapTypedNil applies the TypedNilPolicy to a source interface holding a
+// typed nil. It reports whether the value was handled.
+func (ctx *context) mapTypedNil(dst, src reflect.Value) (bool, error) {
+	if !isTypedNil(src) {
+		return false, nil
+	}
+
+	switch ctx.config.TypedNilPolicy {
+	case TypedNilKeep:
+		return false, nil
+	case TypedNilSkip:
+		return true, nil
+	case TypedNilError:
+		return true, fmt.Errorf("%w: %s", ErrTypedNil, src.Elem().Type())
+	}
+
+	if !ctx.config.IgnoreNilFields && dst.CanSet() {
+		dst.SetZero()
+	}
+	return true, nil
+}
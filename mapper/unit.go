@@ -0,0 +1,220 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements tag-driven unit conversion of numeric fields.
+package mapper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// UnitTagOption converts a numeric field between units while mapping, e.g.
+// `mapper:"distance,unit=m->km"` or `mapper:",unit=c->f"`. It is read from
+// the source field's tag, or from the destination field's tag when the
+// source field has none. Units must be registered with RegisterUnit and
+// share a dimension.
+const UnitTagOption = "unit"
+
+// unit is a linear unit: a value v in this unit equals v*factor+offset in
+// the base unit of its dimension.
+type unit struct {
+	dimension string
+	factor    float64
+	offset    float64
+}
+
+var (
+	unitsMu sync.RWMutex
+	units   = map[string]unit{
+		// Length, base meter
+		"mm": {"length", 0.001, 0},
+		"cm": {"length", 0.01, 0},
+		"m":  {"length", 1, 0},
+		"km": {"length", 1000, 0},
+		"in": {"length", 0.0254, 0},
+		"ft": {"length", 0.3048, 0},
+		"mi": {"length", 1609.344, 0},
+
+		// Mass, base kilogram
+		"g":  {"mass", 0.001, 0},
+		"kg": {"mass", 1, 0},
+		"lb": {"mass", 0.45359237, 0},
+
+		// Temperature, base kelvin
+		"k": {"temperature", 1, 0},
+		"c": {"temperature", 1, 273.15},
+		"f": {"temperature", 5.0 / 9, 273.15 - 32*5.0/9},
+
+		// Duration, base second
+		"ms":  {"duration", 0.001, 0},
+		"s":   {"duration", 1, 0},
+		"min": {"duration", 60, 0},
+		"h":   {"duration", 3600, 0},
+
+		// Speed, base meters per second
+		"mps": {"speed", 1, 0},
+		"kph": {"speed", 1000.0 / 3600, 0},
+		"mph": {"speed", 1609.344 / 3600, 0},
+	}
+)
+
+// exactConversions holds the transforms of temperature pairs, whose
+// composition through kelvin would round, e.g. 100°C to 211.99999999999997°F.
+// Entries are dropped when RegisterUnit replaces one of their units.
+var exactConversions = map[[2]string]affine{
+	{"c", "f"}: {0, 9, 5, 32},
+	{"f", "c"}: {-32, 5, 9, 0},
+	{"c", "k"}: {0, 1, 1, 273.15},
+	{"k", "c"}: {-273.15, 1, 1, 0},
+	{"f", "k"}: {459.67, 5, 9, 0},
+	{"k", "f"}: {-273.15, 9, 5, 32},
+}
+
+// affine is the transform v -> (v+shift)*num/den + offset. The ratio is
+// kept as two factors so ratios such as 9/5 do not round before applying.
+type affine struct {
+	shift  float64
+	num    float64
+	den    float64
+	offset float64
+}
+
+func (a affine) apply(v float64) float64 {
+	return (v+a.shift)*a.num/a.den + a.offset
+}
+
+// RegisterUnit registers (or replaces) a linear unit for UnitTagOption
+// conversions. A value v in the unit equals v*factor+offset in the base
+// unit of the dimension; units convert only within the same dimension.
+// Units are looked up when fields are mapped, so registering one affects
+// every mapper, including plans they already cached.
+//
+// Example:
+//
+//	mapper.RegisterUnit("nmi", "length", 1852, 0)     // nautical miles
+//	mapper.RegisterUnit("hpa", "pressure", 100, 0)    // hectopascal, base Pa
+func RegisterUnit(name, dimension string, factor, offset float64) {
+	name = strings.ToLower(name)
+
+	unitsMu.Lock()
+	defer unitsMu.Unlock()
+	units[name] = unit{dimension: dimension, factor: factor, offset: offset}
+	for pair := range exactConversions {
+		if pair[0] == name || pair[1] == name {
+			delete(exactConversions, pair)
+		}
+	}
+}
+
+// unitConversion converts values between two units, which are resolved
+// when the field is mapped. err is set when the conversion annotation is
+// invalid and is reported when the field is mapped.
+type unitConversion struct {
+	spec string
+	from string
+	to   string
+	err  error
+}
+
+// parseUnitConversion parses a "from->to" unit annotation.
+func parseUnitConversion(spec string) *unitConversion {
+	fromName, toName, ok := strings.Cut(spec, "->")
+	if !ok {
+		return &unitConversion{err: fmt.Errorf("%w: invalid unit conversion %q", ErrUnsupportedType, spec)}
+	}
+	return &unitConversion{
+		spec: spec,
+		from: strings.ToLower(strings.TrimSpace(fromName)),
+		to:   strings.ToLower(strings.TrimSpace(toName)),
+	}
+}
+
+// transform resolves the units of the conversion into the transform of
+// values from the source unit to the destination unit.
+func (c *unitConversion) transform() (affine, error) {
+	if c.err != nil {
+		return affine{}, c.err
+	}
+
+	unitsMu.RLock()
+	defer unitsMu.RUnlock()
+	if exact, ok := exactConversions[[2]string{c.from, c.to}]; ok {
+		return exact, nil
+	}
+	from, fromOK := units[c.from]
+	to, toOK := units[c.to]
+
+	switch {
+	case !fromOK || !toOK:
+		return affine{}, fmt.Errorf("%w: unknown unit in %q", ErrUnsupportedType, c.spec)
+	case from.dimension != to.dimension:
+		return affine{}, fmt.Errorf("%w: cannot convert %s (%s) to %s (%s)", ErrTypeMismatch, c.from, from.dimension, c.to, to.dimension)
+	}
+	return affine{shift: (from.offset - to.offset) / from.factor, num: from.factor, den: to.factor}, nil
+}
+
+// fieldUnitConversion returns the unit conversion annotated on the source
+// field, or else on the destination field, or nil.
+func (ctx *context) fieldUnitConversion(srcField reflect.StructField, dstField reflect.StructField) *unitConversion {
+	if spec, ok := ctx.tagOptionValue(srcField, UnitTagOption); ok {
+		return parseUnitConversion(spec)
+	}
+	if spec, ok := ctx.tagOptionValue(dstField, UnitTagOption); ok {
+		return parseUnitConversion(spec)
+	}
+	return nil
+}
+
+// mapUnit converts a numeric source value into a numeric destination using
+// the field's unit conversion. Pointers on either side are followed; nil
+// sources leave the destination untouched.
+func (ctx *context) mapUnit(dst, src reflect.Value, conv *unitConversion) error {
+	transform, err := conv.transform()
+	if err != nil {
+		return err
+	}
+
+	for src.Kind() == reflect.Ptr {
+		if src.IsNil() {
+			return nil
+		}
+		src = src.Elem()
+	}
+	for dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	if !isNumericKind(src.Kind()) || !isNumericKind(dst.Kind()) {
+		return fmt.Errorf("%w: unit conversion needs numeric fields, got %s and %s", ErrTypeMismatch, src.Type(), dst.Type())
+	}
+
+	v := transform.apply(numericFloat(src))
+	switch {
+	case dst.CanInt():
+		v = math.Round(v)
+		if dst.OverflowInt(int64(v)) {
+			return invalidValue(fmt.Errorf("%w: %v overflows %s", ErrTypeMismatch, v, dst.Type()))
+		}
+		dst.SetInt(int64(v))
+	case dst.CanUint():
+		v = math.Round(v)
+		if v < 0 || dst.OverflowUint(uint64(v)) {
+			return invalidValue(fmt.Errorf("%w: %v overflows %s", ErrTypeMismatch, v, dst.Type()))
+		}
+		dst.SetUint(uint64(v))
+	default:
+		dst.SetFloat(v)
+	}
+
+	// Only float64 destinations keep the scaled value of sources that
+	// float64 represents exactly
+	narrowing := dst.Kind() != reflect.Float64 || reflectutil.IsNarrowingConversion(src.Type(), dst.Type())
+	ctx.auditConversion(src.Type(), dst.Type(), narrowing)
+	return nil
+}
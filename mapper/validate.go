@@ -0,0 +1,66 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements validation of mapped destinations.
+package mapper
+
+import "reflect"
+
+// Validator is implemented by destination types that check their own
+// invariants. Validate is called once the struct has been mapped and its
+// AfterMap hook has run, including when the struct is nested in a larger
+// mapping. Its error is reported as a ValidationError at the struct's
+// path and collected with the other field errors, so one Map call reports
+// every invalid destination.
+//
+// Example:
+//
+//	func (d *SignupDTO) Validate() error {
+//	    if d.Email == "" {
+//	        return errors.New("email is required")
+//	    }
+//	    return nil
+//	}
+type Validator interface {
+	Validate() error
+}
+
+// ValidatorFunc validates the destination pointer passed to Map once the
+// mapping completes.
+type ValidatorFunc func(dst interface{}) error
+
+var validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+
+// validationFailure marks errors returned by validators until they are
+// categorized.
+type validationFailure struct {
+	err error
+}
+
+func (e *validationFailure) Error() string { return e.err.Error() }
+func (e *validationFailure) Unwrap() error { return e.err }
+
+// validate invokes Validate on the destination struct if it implements
+// Validator. Structural copies (source snapshots) are not validated.
+func (ctx *context) validate(dst reflect.Value) {
+	if ctx.structural || !dst.CanAddr() {
+		return
+	}
+
+	ptr := dst.Addr()
+	if !ptr.Type().Implements(validatorType) || !ptr.CanInterface() {
+		return
+	}
+	if err := ptr.Interface().(Validator).Validate(); err != nil {
+		ctx.fieldError(&validationFailure{err: err}, dst, reflect.Value{}, "", "")
+	}
+}
+
+// validateRoot runs the configured ValidatorFunc on the destination
+// pointer of a Map call.
+func (ctx *context) validateRoot(dst interface{}) {
+	if ctx.config.Validator == nil {
+		return
+	}
+	if err := ctx.config.Validator(dst); err != nil {
+		ctx.fieldError(&validationFailure{err: err}, reflect.ValueOf(dst).Elem(), reflect.Value{}, "", "")
+	}
+}
@@ -0,0 +1,71 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements role-based field visibility.
+package mapper
+
+import (
+	gocontext "context"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// RolesTagOption restricts a field to callers with one of the listed roles,
+// separated by semicolons, e.g. `mapper:",roles=admin;owner"`. The option
+// may be set on the source or the destination field; when both carry it,
+// the caller needs a role from each list.
+const RolesTagOption = "roles"
+
+// visibilityKey is the context.Context key of the roles set with
+// ContextWithVisibility.
+type visibilityKey struct{}
+
+// ContextWithVisibility returns a copy of goctx carrying the caller roles
+// for MapContext, in addition to those set with WithVisibility. It lets a
+// single Mapper serve callers with different roles.
+//
+// Example:
+//
+//	err := m.MapContext(mapper.ContextWithVisibility(ctx, user.Role), &dto, account)
+func ContextWithVisibility(goctx gocontext.Context, roles ...string) gocontext.Context {
+	return gocontext.WithValue(goctx, visibilityKey{}, roles)
+}
+
+// fieldRoles returns the roles a field is restricted to, or nil if it is
+// visible to every caller.
+func (ctx *context) fieldRoles(field reflect.StructField) []string {
+	value, ok := ctx.tagOptionValue(field, RolesTagOption)
+	if !ok {
+		return nil
+	}
+	roles := strings.Split(value, ";")
+	for i := range roles {
+		roles[i] = strings.TrimSpace(roles[i])
+	}
+	return roles
+}
+
+// visible reports whether the caller holds a role from each of the given
+// restrictions. Nil restrictions are always satisfied.
+func (ctx *context) visible(restrictions ...[]string) bool {
+	for _, roles := range restrictions {
+		if roles != nil && !ctx.hasRole(roles) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasRole reports whether the caller holds one of roles.
+func (ctx *context) hasRole(roles []string) bool {
+	for _, role := range roles {
+		if slices.Contains(ctx.config.Visibility, role) {
+			return true
+		}
+		if ctx.goctx != nil {
+			if caller, ok := ctx.goctx.Value(visibilityKey{}).([]string); ok && slices.Contains(caller, role) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements non-fatal mapping warnings.
+package mapper
+
+import (
+	gocontext "context"
+	"fmt"
+	"reflect"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// WarningKind classifies a mapping warning.
+type WarningKind string
+
+const (
+	// WarningLossyConversion reports a conversion that changed the value,
+	// such as an integer overflowing a narrower type or a float losing its
+	// fraction.
+	WarningLossyConversion WarningKind = "lossy-conversion"
+
+	// WarningUnknownKey reports a map key that matched no destination
+	// field and was dropped.
+	WarningUnknownKey WarningKind = "unknown-key"
+)
+
+// Warning is a non-fatal but suspicious event of a mapping. Warnings do not
+// fail the mapping.
+type Warning struct {
+	Kind WarningKind
+
+	// Path is the source field path the warning occurred at, e.g.
+	// "Order.Total".
+	Path string
+
+	Message string
+}
+
+// String returns a readable description of the warning.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s at %s: %s", w.Kind, w.Path, w.Message)
+}
+
+// WarningHandlerFunc receives the warnings of a mapping as they occur.
+type WarningHandlerFunc func(w Warning)
+
+// Report describes the outcome of a mapping beyond its error.
+type Report struct {
+	// Warnings lists the warnings raised, in the order they occurred.
+	Warnings []Warning
+}
+
+// MapWithReport maps src into dst like Map and returns a report of the
+// warnings raised. The report is returned even when mapping fails.
+//
+// Example:
+//
+//	report, err := m.MapWithReport(&dto, order)
+//	for _, w := range report.Warnings {
+//	    log.Println(w)
+//	}
+func (m *Mapper) MapWithReport(dst, src interface{}) (*Report, error) {
+	report := &Report{}
+	err := m.mapContext(gocontext.Background(), dst, src, report)
+	return report, err
+}
+
+// warn raises a warning at the current path, passing it to the configured
+// WarningHandler and recording it in the report, if any.
+func (ctx *context) warn(kind WarningKind, format string, args ...interface{}) {
+	if ctx.config.WarningHandler == nil && ctx.report == nil {
+		return
+	}
+
+	w := Warning{Kind: kind, Path: ctx.fieldPath(), Message: fmt.Sprintf(format, args...)}
+	if ctx.config.WarningHandler != nil {
+		ctx.config.WarningHandler(w)
+	}
+	if ctx.report != nil {
+		ctx.mu.Lock()
+		ctx.report.Warnings = append(ctx.report.Warnings, w)
+		ctx.mu.Unlock()
+	}
+}
+
+// warnLossy raises WarningLossyConversion when converting src to the type
+// of dst changed its value.
+func (ctx *context) warnLossy(dst, src reflect.Value) {
+	if ctx.config.WarningHandler == nil && ctx.report == nil {
+		return
+	}
+	if !reflectutil.IsNarrowingConversion(src.Type(), dst.Type()) {
+		return
+	}
+	if dst.Type().ConvertibleTo(src.Type()) && dst.Convert(src.Type()).Equal(src) {
+		return
+	}
+	ctx.warn(WarningLossyConversion, "%v (%s) converted to %v (%s)", src, src.Type(), dst, dst.Type())
+}
@@ -0,0 +1,49 @@
+// Package mapper provides reflection-based object-to-object mapping utilities.
+// This file implements zero-value detection with per-type overrides.
+package mapper
+
+import (
+	"reflect"
+
+	"github.com/fbarikzehi/gomap/internal/reflectutil"
+)
+
+// ZeroCheckerFunc reports whether a value should be considered zero.
+type ZeroCheckerFunc func(v reflect.Value) bool
+
+// Zeroer is implemented by types that define their own notion of zero,
+// such as time.Time.
+type Zeroer interface {
+	IsZero() bool
+}
+
+var zeroerType = reflect.TypeOf((*Zeroer)(nil)).Elem()
+
+// isZero reports whether v is zero under the configuration's rules:
+// a registered per-type ZeroCheckerFunc wins, then an IsZero method on the
+// value, then reflect's structural zero check.
+func isZero(cfg *Config, v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	if checker, ok := cfg.ZeroCheckers[v.Type()]; ok {
+		return checker(v)
+	}
+
+	if v.Type().Implements(zeroerType) && v.Kind() != reflect.Interface {
+		if reflectutil.IsNillable(v.Kind()) && v.IsNil() {
+			return true
+		}
+		return v.Interface().(Zeroer).IsZero()
+	}
+
+	return reflectutil.IsZeroValue(v)
+}
+
+// IsZero reports whether v is zero according to this mapper's zero rules,
+// the same ones used for ZeroFields and other zero-dependent behavior. It is
+// intended for conditions and converters that must agree with the mapper.
+func (m *Mapper) IsZero(v interface{}) bool {
+	return isZero(m.config, reflect.ValueOf(v))
+}
@@ -82,3 +82,53 @@ func BenchmarkDeepStruct(b *testing.B) {
 		_ = mapper.Copy(&dst, src)
 	}
 }
+
+func BenchmarkMapOfStructPointers(b *testing.B) {
+	m := mapper.NewMapper()
+	src := make(map[int64]*BenchPerson, 100)
+	for i := int64(0); i < 100; i++ {
+		src[i] = &BenchPerson{
+			ID:      i,
+			Name:    "John Doe",
+			Address: &BenchAddress{City: "New York"},
+			Tags:    []string{"tag1", "tag2"},
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst map[int64]*BenchPerson
+		_ = m.Map(&dst, src)
+	}
+}
+
+func BenchmarkMapOfStructValues(b *testing.B) {
+	m := mapper.NewMapper()
+	src := make(map[string]BenchAddress, 100)
+	for i := 0; i < 100; i++ {
+		src[string(rune('a'+i%26))+string(rune('a'+i/26))] = BenchAddress{City: "New York", Zip: "10001"}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst map[string]BenchAddress
+		_ = m.Map(&dst, src)
+	}
+}
+
+func BenchmarkSliceRoot(b *testing.B) {
+	m := mapper.NewMapper()
+	src := make([]BenchPerson, 100)
+	for i := range src {
+		src[i] = BenchPerson{ID: int64(i), Name: "John Doe", Address: &BenchAddress{City: "New York"}}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst []BenchPerson
+		_ = m.Map(&dst, &src)
+	}
+}
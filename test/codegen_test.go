@@ -0,0 +1,168 @@
+package gomap_test
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fbarikzehi/gomap/internal/codegen"
+)
+
+const codegenModels = `package models
+
+import "time"
+
+type Address struct {
+	City string
+}
+
+type AddressDTO struct {
+	City string
+}
+
+type User struct {
+	ID       int64
+	FullName string ` + "`mapper:\"Name\"`" + `
+	Password string ` + "`mapper:\"-\"`" + `
+	Age      int32
+	Created  time.Time
+	Home     *Address
+	Tags     []string
+}
+
+type UserDTO struct {
+	ID       int64
+	Name     string
+	Password string
+	Age      int
+	Created  string
+	Home     *AddressDTO
+	Tags     []string
+}
+
+type Contact struct {
+	Name   string ` + "`json:\"full_name,omitempty\"`" + `
+	Mobile string ` + "`yaml:\"phone,flow\"`" + `
+}
+
+type ContactDTO struct {
+	FullName string ` + "`json:\"full_name\"`" + `
+	Phone    string ` + "`yaml:\"phone\"`" + `
+}
+
+func formatTime(t time.Time) string { return t.Format(time.RFC3339) }
+`
+
+func TestCodegenGenerate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), []byte(codegenModels), 0o600))
+
+	code, err := codegen.Generate(codegen.Options{
+		Dir:        dir,
+		Src:        "models.User",
+		Dst:        "models.UserDTO",
+		Converters: map[string]string{"time.Time->string": "formatTime"},
+	})
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "gen.go", code, 0)
+	require.NoError(t, err)
+
+	src := string(code)
+	assert.Contains(t, src, "func MapUserToUserDTO(src User) (UserDTO, error)")
+	assert.Contains(t, src, "dst.Name = src.FullName")
+	assert.Contains(t, src, "dst.Age = int(src.Age)")
+	assert.Contains(t, src, "dst.Created = formatTime(src.Created)")
+	assert.Contains(t, src, "func MapAddressToAddressDTO(src Address) (AddressDTO, error)")
+	assert.NotContains(t, src, "dst.Password")
+
+	_, err = codegen.Generate(codegen.Options{Dir: dir, Src: "models.Missing", Dst: "models.UserDTO"})
+	assert.Error(t, err)
+}
+
+func TestCodegenEncodingTags(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), []byte(codegenModels), 0o600))
+
+	code, err := codegen.Generate(codegen.Options{Dir: dir, Src: "Contact", Dst: "ContactDTO", UseJSONTag: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "dst.FullName = src.Name")
+
+	code, err = codegen.Generate(codegen.Options{Dir: dir, Src: "Contact", Dst: "ContactDTO"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(code), "dst.FullName")
+
+	code, err = codegen.Generate(codegen.Options{Dir: dir, Src: "Contact", Dst: "ContactDTO", UseYAMLTag: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "dst.Phone = src.Mobile")
+	assert.NotContains(t, string(code), "dst.FullName")
+}
+
+func TestCodegenPairings(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), []byte(codegenModels), 0o600))
+
+	types, err := codegen.Structs(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Address", "AddressDTO", "Contact", "ContactDTO", "User", "UserDTO"}, types)
+
+	pairings, err := codegen.Pairings(codegen.Options{Dir: dir, Src: "User", Dst: "UserDTO"})
+	require.NoError(t, err)
+
+	byName := make(map[string]codegen.Pairing)
+	for _, p := range pairings {
+		byName[p.Source] = p
+	}
+	assert.NotContains(t, byName, "Password")
+	assert.Equal(t, "Name", byName["FullName"].Destination)
+	assert.Equal(t, "int32", byName["Age"].SourceBasic)
+	assert.Equal(t, "int", byName["Age"].DestinationBasic)
+	assert.Contains(t, byName["Age"].Code, "dst.Age = int(src.Age)")
+	assert.Equal(t, "Created", byName["Created"].Destination)
+	assert.Empty(t, byName["Created"].Code)
+	assert.Empty(t, byName["Created"].SourceBasic)
+}
+
+const codegenNumbers = `package models
+
+type Cents int64
+
+type Reading struct {
+	Count   int64
+	Ratio   float64
+	Size    uint
+	Total   Cents
+	Small   int8
+	Precise float32
+}
+
+type ReadingDTO struct {
+	Count   int8
+	Ratio   int
+	Size    int
+	Total   int16
+	Small   int64
+	Precise float64
+}
+`
+
+func TestCodegenNarrowing(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), []byte(codegenNumbers), 0o600))
+
+	code, err := codegen.Generate(codegen.Options{Dir: dir, Src: "Reading", Dst: "ReadingDTO"})
+	require.NoError(t, err)
+
+	src := string(code)
+	assert.Contains(t, src, "// Count: no mapping from int64 to int8")
+	assert.Contains(t, src, "// Ratio: no mapping from float64 to int")
+	assert.Contains(t, src, "// Size: no mapping from uint to int")
+	assert.Contains(t, src, "// Total: no mapping from Cents to int16")
+	assert.Contains(t, src, "dst.Small = int64(src.Small)")
+	assert.Contains(t, src, "dst.Precise = float64(src.Precise)")
+}
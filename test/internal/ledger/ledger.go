@@ -0,0 +1,21 @@
+// Package ledger declares types with unexported state, used by the tests of
+// private field mapping across packages.
+package ledger
+
+// Entry is a ledger entry whose identity and amount are unexported.
+type Entry struct {
+	id     int
+	amount int64
+	Memo   string
+}
+
+// NewEntry returns an entry with the given state.
+func NewEntry(id int, amount int64, memo string) Entry {
+	return Entry{id: id, amount: amount, Memo: memo}
+}
+
+// ID returns the entry's identifier.
+func (e Entry) ID() int { return e.id }
+
+// Amount returns the entry's amount.
+func (e Entry) Amount() int64 { return e.amount }
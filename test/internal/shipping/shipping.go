@@ -0,0 +1,41 @@
+// Package shipping declares types holding pointers, slices and maps, used by
+// the tests of generated plan source.
+package shipping
+
+// Customer places orders.
+type Customer struct {
+	Name string
+	Tags []string
+}
+
+// Item is an order line.
+type Item struct {
+	SKU string
+	Qty int
+}
+
+// Address is a shipping address.
+type Address struct {
+	City  string
+	Lines []string
+}
+
+// Order is an order as received.
+type Order struct {
+	ID       int
+	Customer *Customer
+	Items    []*Item
+	Grid     [][]int
+	Labels   map[string][]string
+	Ship     Address
+}
+
+// OrderRecord is an order as stored.
+type OrderRecord struct {
+	ID       int64
+	Customer *Customer
+	Items    []*Item
+	Grid     [][]int
+	Labels   map[string][]string
+	Ship     Address
+}
@@ -1,14 +1,31 @@
 package gomap_test
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/fbarikzehi/gomap/mapper"
+	"github.com/fbarikzehi/gomap/mapper/mapperutil"
+	"github.com/fbarikzehi/gomap/test/internal/ledger"
+	"github.com/fbarikzehi/gomap/test/internal/shipping"
 )
 
 type TestPerson struct {
@@ -97,3 +114,3766 @@ func TestCustomConverters(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEmpty(t, dst.Created)
 }
+
+func TestOverflowRoundTrip(t *testing.T) {
+	type Full struct {
+		ID    int
+		Name  string
+		Email string
+		Score float64
+	}
+
+	type Proxy struct {
+		ID      int
+		Name    string
+		Unknown map[string]interface{} `mapper:",overflow"`
+	}
+
+	src := Full{ID: 7, Name: "Ada", Email: "ada@example.com", Score: 9.5}
+
+	var proxy Proxy
+	require.NoError(t, mapper.Copy(&proxy, src))
+	assert.Equal(t, 7, proxy.ID)
+	assert.Equal(t, map[string]interface{}{"Email": "ada@example.com", "Score": 9.5}, proxy.Unknown)
+
+	var back Full
+	require.NoError(t, mapper.Copy(&back, proxy))
+	assert.Equal(t, src, back)
+}
+
+func TestConversionAudit(t *testing.T) {
+	type Src struct {
+		Count int64
+		Ratio float64
+		Name  string
+	}
+
+	type Dst struct {
+		Count int32
+		Ratio float64
+		Name  string
+	}
+
+	var events []mapper.ConversionEvent
+	var dst Dst
+	err := mapper.Copy(&dst, Src{Count: 5, Ratio: 0.5, Name: "x"},
+		mapper.WithConversionAudit(1, func(e mapper.ConversionEvent) {
+			events = append(events, e)
+		}))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, reflect.TypeOf(int64(0)), events[0].SrcType)
+	assert.Equal(t, reflect.TypeOf(int32(0)), events[0].DstType)
+	assert.True(t, events[0].Narrowing)
+}
+
+func TestConversionAuditBuiltins(t *testing.T) {
+	type Src struct {
+		Count  string
+		Day    time.Time
+		Meters int32
+		Price  string
+	}
+
+	type Dst struct {
+		Count  int
+		Day    mapper.Date
+		Meters float64 `mapper:",unit=m->km"`
+		Price  mapper.Money
+	}
+
+	narrowing := map[reflect.Type]bool{}
+	var dst Dst
+	err := mapper.Copy(&dst, Src{Count: "3", Day: time.Now(), Meters: 1500, Price: "9.995 USD"},
+		mapper.WithStringConversion(true),
+		mapper.WithCivilTime(true),
+		mapper.WithMoney(2, mapper.RoundHalfEven),
+		mapper.WithConversionAudit(1, func(e mapper.ConversionEvent) {
+			narrowing[e.DstType] = e.Narrowing
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, map[reflect.Type]bool{
+		reflect.TypeOf(0):              false,
+		reflect.TypeOf(mapper.Date{}):  true,
+		reflect.TypeOf(float64(0)):     false,
+		reflect.TypeOf(mapper.Money{}): true,
+	}, narrowing)
+}
+
+func TestCivilTime(t *testing.T) {
+	type Event struct {
+		Day   time.Time
+		Start time.Time
+		Label mapper.Date
+	}
+
+	type EventDTO struct {
+		Day   mapper.Date
+		Start mapper.TimeOfDay
+		Label string
+	}
+
+	src := Event{
+		Day:   time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC),
+		Start: time.Date(2024, time.March, 9, 14, 30, 15, 0, time.UTC),
+		Label: mapper.Date{Year: 2024, Month: time.December, Day: 25},
+	}
+
+	var dto EventDTO
+	require.NoError(t, mapper.Copy(&dto, src, mapper.WithCivilTime(true)))
+	assert.Equal(t, mapper.Date{Year: 2024, Month: time.March, Day: 9}, dto.Day)
+	assert.Equal(t, mapper.TimeOfDay{Hour: 14, Minute: 30, Second: 15}, dto.Start)
+	assert.Equal(t, "2024-12-25", dto.Label)
+
+	var back Event
+	require.NoError(t, mapper.Copy(&back, dto, mapper.WithCivilTime(true)))
+	assert.True(t, src.Day.Equal(back.Day))
+	assert.Equal(t, src.Label, back.Label)
+}
+
+func TestCivilTimeWithoutSeconds(t *testing.T) {
+	type Slot struct {
+		Hour   int
+		Minute int
+	}
+	type Shift struct {
+		Opens  string
+		Closes string
+		Starts string
+	}
+	type ShiftDTO struct {
+		Opens  mapper.TimeOfDay
+		Closes Slot
+		Starts mapper.Date
+	}
+
+	var dto ShiftDTO
+	require.NoError(t, mapper.Copy(&dto, Shift{Opens: "14:30", Closes: "22:15:30", Starts: "2024-03-09"}, mapper.WithCivilTime(true)))
+	assert.Equal(t, mapper.TimeOfDay{Hour: 14, Minute: 30}, dto.Opens)
+	assert.Equal(t, Slot{Hour: 22, Minute: 15}, dto.Closes)
+
+	var back Shift
+	require.NoError(t, mapper.Copy(&back, dto, mapper.WithCivilTime(true)))
+	assert.Equal(t, Shift{Opens: "14:30:00", Closes: "22:15", Starts: "2024-03-09"}, back)
+
+	err := mapper.Copy(&dto, Shift{Opens: "14h30"}, mapper.WithCivilTime(true))
+	assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+
+	// Empty strings and zero values map to each other
+	dto = ShiftDTO{Opens: mapper.TimeOfDay{Hour: 9}, Starts: mapper.Date{Year: 2024, Month: 1, Day: 1}}
+	require.NoError(t, mapper.Copy(&dto, Shift{}, mapper.WithCivilTime(true)))
+	assert.Equal(t, ShiftDTO{}, dto)
+	back = Shift{Opens: "x", Starts: "y"}
+	require.NoError(t, mapper.Copy(&back, ShiftDTO{}, mapper.WithCivilTime(true)))
+	assert.Equal(t, Shift{}, back)
+
+	type Event struct {
+		At time.Time
+	}
+	var event Event
+	require.NoError(t, mapper.Copy(&event, struct{ At mapper.Date }{}, mapper.WithCivilTime(true)))
+	assert.True(t, event.At.IsZero())
+	var day struct{ At mapper.Date }
+	require.NoError(t, mapper.Copy(&day, Event{}, mapper.WithCivilTime(true)))
+	assert.Equal(t, mapper.Date{}, day.At)
+}
+
+func TestMoneyAdapter(t *testing.T) {
+	type Order struct {
+		Total    mapper.Money
+		Discount mapper.Money
+	}
+
+	type OrderDTO struct {
+		Total    string
+		Discount float64
+	}
+
+	opt := mapper.WithMoney(mapper.DefaultMoneyScale, mapper.RoundHalfEven)
+
+	var dto OrderDTO
+	src := Order{
+		Total:    mapper.Money{Amount: 1299, Currency: "USD"},
+		Discount: mapper.Money{Amount: -5, Currency: "USD"},
+	}
+	require.NoError(t, mapper.Copy(&dto, src, opt))
+	assert.Equal(t, "12.99 USD", dto.Total)
+	assert.Equal(t, -0.05, dto.Discount)
+
+	var back Order
+	require.NoError(t, mapper.Copy(&back, OrderDTO{Total: "0.125 EUR", Discount: 2.675}, opt))
+	assert.Equal(t, mapper.Money{Amount: 12, Currency: "EUR"}, back.Total)
+	assert.Equal(t, int64(268), back.Discount.Amount)
+
+	require.NoError(t, mapper.Copy(&back, OrderDTO{Total: "0.125"},
+		mapper.WithMoney(mapper.DefaultMoneyScale, mapper.RoundHalfUp)))
+	assert.Equal(t, int64(13), back.Total.Amount)
+
+	err := mapper.Copy(&back, OrderDTO{Total: "twelve"}, opt)
+	assert.ErrorContains(t, err, "invalid decimal amount")
+
+	// Rounding the largest amount up overflows instead of flipping its sign
+	roundUp := mapper.WithMoney(mapper.DefaultMoneyScale, mapper.RoundUp)
+	require.NoError(t, mapper.Copy(&back, OrderDTO{Total: "92233720368547758.07"}, roundUp))
+	assert.Equal(t, int64(math.MaxInt64), back.Total.Amount)
+	for _, total := range []string{"92233720368547758.071", "-92233720368547758.071"} {
+		err = mapper.Copy(&back, OrderDTO{Total: total}, roundUp)
+		assert.ErrorContains(t, err, "overflows int64", total)
+	}
+}
+
+func TestCollectionRoots(t *testing.T) {
+	src := []TestAddress{{City: "NY"}, {City: "LA"}}
+
+	var dst []TestAddress
+	require.NoError(t, mapper.Copy(&dst, &src))
+	assert.Equal(t, src, dst)
+
+	dst = []TestAddress{{City: "a"}, {City: "b"}, {City: "c"}}
+	require.NoError(t, mapper.Copy(&dst, src[:1]))
+	assert.Equal(t, []TestAddress{{City: "NY"}}, dst)
+
+	srcMap := map[string]*TestAddress{"home": {City: "Paris"}}
+	var dstMap map[string]TestAddress
+	require.NoError(t, mapper.Copy(&dstMap, &srcMap))
+	assert.Equal(t, map[string]TestAddress{"home": {City: "Paris"}}, dstMap)
+
+	assert.ErrorIs(t, mapper.Copy(&dstMap, src), mapper.ErrTypeMismatch)
+	assert.ErrorIs(t, mapper.Copy((*[]TestAddress)(nil), src), mapper.ErrNilPointer)
+}
+
+func TestIsolateSource(t *testing.T) {
+	src := map[string][]int{"a": {1, 2}}
+
+	var seen int
+	conv := func(v reflect.Value) (reflect.Value, error) {
+		// Mutating the caller's source mid-mapping must not leak into dst.
+		src["a"][0] = 99
+		seen++
+		return v, nil
+	}
+
+	var dst map[string][]int
+	err := mapper.Copy(&dst, src,
+		mapper.WithIsolateSource(true),
+		mapper.WithCustomConverter(reflect.TypeOf(""), conv))
+	require.NoError(t, err)
+	assert.Equal(t, 1, seen)
+	assert.Equal(t, []int{1, 2}, dst["a"])
+}
+
+type cycleNode struct {
+	Name string
+	Next *cycleNode
+}
+
+func TestCyclePolicies(t *testing.T) {
+	newRing := func() *cycleNode {
+		a := &cycleNode{Name: "a"}
+		b := &cycleNode{Name: "b", Next: a}
+		a.Next = b
+		return a
+	}
+
+	var dst cycleNode
+	assert.ErrorContains(t, mapper.Copy(&dst, newRing()), mapper.ErrCircularReference.Error())
+
+	dst = cycleNode{}
+	require.NoError(t, mapper.Copy(&dst, newRing(), mapper.WithCyclePolicy(mapper.CycleSkipField)))
+	assert.Equal(t, "b", dst.Next.Name)
+	assert.Nil(t, dst.Next.Next)
+
+	dst = cycleNode{}
+	require.NoError(t, mapper.Copy(&dst, newRing(), mapper.WithCyclePolicy(mapper.CycleAlias)))
+	assert.Same(t, &dst, dst.Next.Next)
+
+	dst = cycleNode{}
+	require.NoError(t, mapper.Copy(&dst, newRing(), mapper.WithCyclePolicy(mapper.CycleTruncateAtDepth(6))))
+	assert.Equal(t, "a", dst.Next.Next.Name)
+	assert.Nil(t, dst.Next.Next.Next)
+
+	// Shared, non-circular pointers are not cycles.
+	shared := &TestAddress{City: "NY"}
+	type Pair struct{ Home, Work *TestAddress }
+	var pair Pair
+	require.NoError(t, mapper.Copy(&pair, Pair{Home: shared, Work: shared}))
+	assert.Equal(t, "NY", pair.Work.City)
+}
+
+type dbHandle struct{ dsn string }
+
+func (*dbHandle) DoNotMap() {}
+
+type loggerHandle struct {
+	_     struct{} `mapper:"-"`
+	Level int
+}
+
+func TestDoNotMapMarkers(t *testing.T) {
+	type Service struct {
+		Name string
+		DB   *dbHandle
+	}
+
+	var dst Service
+	err := mapper.Copy(&dst, Service{Name: "svc", DB: &dbHandle{dsn: "x"}})
+	assert.ErrorContains(t, err, mapper.ErrDoNotMap.Error())
+
+	type WithLogger struct {
+		Log loggerHandle
+	}
+	var wl WithLogger
+	assert.Error(t, mapper.Copy(&wl, WithLogger{Log: loggerHandle{Level: 1}}))
+
+	// Nil handles are not copied and therefore allowed.
+	require.NoError(t, mapper.Copy(&dst, Service{Name: "svc"}))
+}
+
+func TestIsolateSourceDoNotMap(t *testing.T) {
+	type Service struct {
+		Name string
+		DB   *dbHandle
+		Log  loggerHandle
+	}
+	type ServiceDTO struct {
+		Name string
+	}
+	type ServiceCopy struct {
+		Name string
+		DB   *dbHandle
+	}
+
+	src := Service{Name: "svc", DB: &dbHandle{dsn: "x"}, Log: loggerHandle{Level: 1}}
+
+	// Handles without a destination do not fail the snapshot
+	var dto ServiceDTO
+	require.NoError(t, mapper.Copy(&dto, src, mapper.WithIsolateSource(true)))
+	assert.Equal(t, ServiceDTO{Name: "svc"}, dto)
+
+	var cp ServiceCopy
+	require.NoError(t, mapper.Copy(&cp, src, mapper.WithIsolateSource(true),
+		mapper.WithIgnoreTypes(reflect.TypeOf(dbHandle{}))))
+	assert.Equal(t, ServiceCopy{Name: "svc"}, cp)
+
+	// Mapped handles still fail, as without isolation
+	err := mapper.Copy(&cp, src, mapper.WithIsolateSource(true))
+	assert.ErrorIs(t, err, mapper.ErrDoNotMap)
+}
+
+func TestMapperUtil(t *testing.T) {
+	var p *TestAddress
+	assert.True(t, mapperutil.IsNil(reflect.ValueOf(p)))
+	assert.True(t, mapperutil.IsZeroValue(reflect.ValueOf(TestAddress{})))
+	assert.False(t, mapperutil.IsZeroValue(reflect.ValueOf(TestAddress{City: "NY"})))
+	assert.True(t, mapperutil.EqualFold("UserID", "userid"))
+
+	field, _ := reflect.TypeOf(struct {
+		Name string `mapper:"full_name"`
+	}{}).FieldByName("Name")
+	tag, ok := mapperutil.FieldTag(field, "mapper")
+	assert.True(t, ok)
+	assert.Equal(t, "full_name", tag)
+}
+
+func TestZeroCheckers(t *testing.T) {
+	type Price struct {
+		Total mapper.Money
+		Note  string
+	}
+
+	moneyType := reflect.TypeOf(mapper.Money{})
+	m := mapper.NewMapper(
+		mapper.WithZeroFields(true),
+		mapper.WithZeroChecker(moneyType, func(v reflect.Value) bool {
+			return v.FieldByName("Amount").Int() == 0
+		}),
+	)
+
+	assert.True(t, m.IsZero(mapper.Money{Currency: "USD"}))
+	// mapperutil only knows the default rules
+	assert.False(t, mapperutil.IsZeroValue(reflect.ValueOf(mapper.Money{Currency: "USD"})))
+	assert.True(t, m.IsZero(time.Time{}))
+	assert.False(t, m.IsZero(TestAddress{City: "NY"}))
+
+	dst := Price{Total: mapper.Money{Amount: 10, Currency: "EUR"}, Note: "keep"}
+	require.NoError(t, m.Map(&dst, Price{Total: mapper.Money{Currency: "USD"}, Note: "new"}))
+	assert.Equal(t, mapper.Money{}, dst.Total)
+	assert.Equal(t, "new", dst.Note)
+}
+
+func TestConverterSkip(t *testing.T) {
+	type Order struct {
+		Status int
+		Total  int
+	}
+
+	statusConverter := func(v reflect.Value) (reflect.Value, error) {
+		if v.Int() > 2 {
+			return reflect.Value{}, mapper.ErrSkipConversion
+		}
+		return reflect.ValueOf(int(v.Int()) * 10), nil
+	}
+
+	var dst Order
+	err := mapper.Copy(&dst, Order{Status: 1, Total: 5999},
+		mapper.WithCustomConverter(reflect.TypeOf(0), statusConverter))
+	require.NoError(t, err)
+	assert.Equal(t, Order{Status: 10, Total: 5999}, dst)
+}
+
+func TestConverterPrecedence(t *testing.T) {
+	type Src struct {
+		Code  string
+		Label string
+		Note  string
+	}
+	type Dst struct {
+		Code  int
+		Label string
+		Note  string
+	}
+
+	stringType := reflect.TypeOf("")
+	m := mapper.NewMapper(
+		mapper.WithCustomConverter(stringType, func(v reflect.Value) (reflect.Value, error) {
+			return reflect.ValueOf(strings.TrimSpace(v.String())), nil
+		}),
+		// Declines everything but notes, which the custom converter then trims
+		mapper.WithContextConverter(stringType, func(ctx context.Context, v reflect.Value) (reflect.Value, error) {
+			if field, _ := mapper.FieldFromContext(ctx); field.SrcField != "Note" {
+				return reflect.Value{}, mapper.ErrSkipConversion
+			}
+			return reflect.ValueOf(strings.ToUpper(v.String())), nil
+		}),
+		// Only applies to strings mapped onto ints
+		mapper.WithPairConverter(stringType, reflect.TypeOf(0), func(v reflect.Value) (reflect.Value, error) {
+			return reflect.ValueOf(len(strings.TrimSpace(v.String()))), nil
+		}),
+	)
+
+	var dst Dst
+	require.NoError(t, m.Map(&dst, Src{Code: " abc ", Label: "  x  ", Note: " n "}))
+	assert.Equal(t, Dst{Code: 3, Label: "x", Note: " N "}, dst)
+
+	plan, err := m.Plan(reflect.TypeOf(Src{}), reflect.TypeOf(Dst{}))
+	require.NoError(t, err)
+	require.Len(t, plan.Fields, 3)
+	assert.Equal(t, "pair", plan.Fields[0].Converter)
+	assert.Equal(t, "context", plan.Fields[1].Converter)
+}
+
+func TestConverterOutputPipeline(t *testing.T) {
+	type Src struct {
+		Amount int
+		Home   TestAddress
+	}
+
+	type Dst struct {
+		Amount float32
+		Home   TestAddress
+	}
+
+	type Other struct {
+		Amount []string
+	}
+
+	double := func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(float64(v.Int()) * 2), nil
+	}
+
+	var dst Dst
+	require.NoError(t, mapper.Copy(&dst, Src{Amount: 21, Home: TestAddress{City: "NY"}},
+		mapper.WithCustomConverter(reflect.TypeOf(0), double)))
+	assert.Equal(t, float32(42), dst.Amount)
+	assert.Equal(t, "NY", dst.Home.City)
+
+	var other Other
+	err := mapper.Copy(&other, Src{Amount: 1}, mapper.WithCustomConverter(reflect.TypeOf(0), double))
+	assert.ErrorContains(t, err, mapper.ErrTypeMismatch.Error())
+}
+
+func TestTypedMapper(t *testing.T) {
+	type PersonDTO struct {
+		Name string
+		Age  int
+	}
+
+	m := mapper.New[TestPerson, PersonDTO]()
+
+	dto, err := m.Map(TestPerson{Name: "Ada", Age: 36})
+	require.NoError(t, err)
+	assert.Equal(t, PersonDTO{Name: "Ada", Age: 36}, dto)
+
+	existing := PersonDTO{Name: "old"}
+	require.NoError(t, m.MapInto(&existing, TestPerson{Name: "Grace", Age: 45}))
+	assert.Equal(t, PersonDTO{Name: "Grace", Age: 45}, existing)
+
+	assert.ErrorIs(t, m.MapInto(nil, TestPerson{}), mapper.ErrNilPointer)
+}
+
+func TestAtomicFields(t *testing.T) {
+	type Stats struct {
+		Requests atomic.Int64 `mapper:",atomic"`
+		Errors   int32        `mapper:",atomic"`
+		Healthy  atomic.Bool  `mapper:",atomic"`
+	}
+
+	type StatsDTO struct {
+		Requests int64
+		Errors   int
+		Healthy  bool
+	}
+
+	type Mirror struct {
+		Requests atomic.Int64 `mapper:",atomic"`
+		Errors   int32
+	}
+
+	src := &Stats{Errors: 3}
+	src.Requests.Store(42)
+	src.Healthy.Store(true)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			src.Requests.Add(1)
+		}
+	}()
+
+	var dto StatsDTO
+	require.NoError(t, mapper.Copy(&dto, src))
+	<-done
+	assert.GreaterOrEqual(t, dto.Requests, int64(42))
+	assert.Equal(t, 3, dto.Errors)
+	assert.True(t, dto.Healthy)
+
+	var mirror Mirror
+	require.NoError(t, mapper.Copy(&mirror, StatsDTO{Requests: 7, Errors: 1}))
+	assert.Equal(t, int64(7), mirror.Requests.Load())
+	assert.Equal(t, int32(1), mirror.Errors)
+}
+
+type lockedCache struct {
+	mu      sync.RWMutex
+	Entries map[string]int
+}
+
+func TestSourceLocker(t *testing.T) {
+	type CacheDTO struct {
+		Entries map[string]int
+	}
+
+	cache := &lockedCache{Entries: map[string]int{"a": 1}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			cache.mu.Lock()
+			cache.Entries["b"] = i
+			cache.mu.Unlock()
+		}
+	}()
+
+	var locks int
+	m := mapper.NewMapper(mapper.WithSourceLocker(reflect.TypeOf(&lockedCache{}),
+		func(v any) { v.(*lockedCache).mu.RLock(); locks++ },
+		func(v any) { v.(*lockedCache).mu.RUnlock() }))
+
+	var dto CacheDTO
+	require.NoError(t, m.Map(&dto, cache))
+	<-done
+	assert.Equal(t, 1, locks)
+	assert.Equal(t, 1, dto.Entries["a"])
+}
+
+func TestFieldConverter(t *testing.T) {
+	type Line struct {
+		Qty   int
+		Price int
+	}
+	type Order struct {
+		Total int
+		Count int
+		Lines []Line
+	}
+	type LineDTO struct {
+		Qty   int
+		Price float64
+	}
+	type OrderDTO struct {
+		Total float64
+		Count int
+		Lines []LineDTO
+	}
+
+	cents := func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(float64(v.Int()) / 100), nil
+	}
+	src := Order{Total: 1299, Count: 3, Lines: []Line{{Qty: 2, Price: 250}}}
+
+	t.Run("full and relative paths", func(t *testing.T) {
+		var dst OrderDTO
+		err := mapper.Copy(&dst, &src,
+			mapper.WithFieldConverter("Order.Total", cents),
+			mapper.WithFieldConverter("Lines.Price", cents),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 12.99, dst.Total)
+		assert.Equal(t, 3, dst.Count)
+		assert.Equal(t, []LineDTO{{Qty: 2, Price: 2.5}}, dst.Lines)
+	})
+
+	t.Run("takes precedence over type converters", func(t *testing.T) {
+		var dst OrderDTO
+		err := mapper.Copy(&dst, src,
+			mapper.WithCustomConverter(reflect.TypeOf(0), func(v reflect.Value) (reflect.Value, error) {
+				return reflect.ValueOf(int(v.Int()) * 10), nil
+			}),
+			mapper.WithFieldConverter("Order.Total", cents),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 12.99, dst.Total)
+		assert.Equal(t, 30, dst.Count)
+	})
+
+	t.Run("skip falls through", func(t *testing.T) {
+		var dst OrderDTO
+		err := mapper.Copy(&dst, src,
+			mapper.WithFieldConverter("Order.Count", func(v reflect.Value) (reflect.Value, error) {
+				return reflect.Value{}, mapper.ErrSkipConversion
+			}),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 3, dst.Count)
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	cache := &lockedCache{Entries: map[string]int{"a": 1}}
+
+	var locks int
+	snap, err := mapper.Snapshot(cache, mapper.WithSourceLocker(reflect.TypeOf(&lockedCache{}),
+		func(v any) { v.(*lockedCache).mu.RLock(); locks++ },
+		func(v any) { v.(*lockedCache).mu.RUnlock() }))
+	require.NoError(t, err)
+	assert.Equal(t, 1, locks)
+
+	cp, ok := snap.(*lockedCache)
+	require.True(t, ok)
+	assert.NotSame(t, cache, cp)
+
+	cache.Entries["a"] = 2
+	assert.Equal(t, 1, cp.Entries["a"])
+
+	_, err = mapper.Snapshot(nil)
+	assert.ErrorIs(t, err, mapper.ErrNilPointer)
+}
+
+func TestSnapshotExportedOnly(t *testing.T) {
+	if !mapper.UnsafeEnabled {
+		t.Skip("private field access needs package unsafe")
+	}
+
+	// Unexported fields are left out, even for mappers mapping them
+	snap, err := mapper.Snapshot(ledger.NewEntry(7, 1250, "refund"), mapper.WithAllowPrivateFields(true))
+	require.NoError(t, err)
+	assert.Equal(t, ledger.NewEntry(0, 0, "refund"), snap)
+
+	var isolated ledger.Entry
+	require.NoError(t, mapper.Copy(&isolated, ledger.NewEntry(7, 1250, "refund"),
+		mapper.WithAllowPrivateFields(true), mapper.WithIsolateSource(true)))
+	assert.Equal(t, ledger.NewEntry(0, 0, "refund"), isolated)
+}
+
+func TestFieldMapping(t *testing.T) {
+	type Vendor struct {
+		FullName string `json:"full_name"`
+		YearsOld int
+	}
+	type Person struct {
+		Name string
+		Age  int
+	}
+	src := Vendor{FullName: "Ada", YearsOld: 36}
+
+	var dst Person
+	require.NoError(t, mapper.Copy(&dst, src,
+		mapper.WithJSONTag(true),
+		mapper.WithFieldMapping(map[string]string{"FullName": "Name", "YearsOld": "Age"})))
+	assert.Equal(t, Person{Name: "Ada", Age: 36}, dst)
+
+	m := mapper.NewMapper()
+	var before Person
+	require.NoError(t, m.Map(&before, src))
+	assert.Equal(t, Person{}, before)
+
+	m.Field("FullName").To("Name").Field("YearsOld").To("Age")
+	var after Person
+	require.NoError(t, m.Map(&after, src))
+	assert.Equal(t, Person{Name: "Ada", Age: 36}, after)
+}
+
+func TestAggregate(t *testing.T) {
+	type Row struct {
+		Region  string
+		Revenue float64
+		Units   int
+		Largest *int64
+		Note    string
+	}
+	type Totals struct {
+		Region  string
+		Revenue float64 `mapper:",sum"`
+		Units   int     `mapper:",avg"`
+		Largest *int64  `mapper:",max"`
+		Orders  int
+		Note    string
+	}
+
+	n := func(v int64) *int64 { return &v }
+	rows := []Row{
+		{Region: "EU", Revenue: 10.5, Units: 3, Largest: n(4), Note: "a"},
+		{Region: "EU", Revenue: 4.5, Units: 4, Note: "b"},
+		{Region: "EU", Revenue: 5, Units: 6, Largest: n(9), Note: "c"},
+	}
+
+	var totals Totals
+	require.NoError(t, mapper.Aggregate(&totals, rows, mapper.AggregateRules{"Note": mapper.AggregateLast}))
+	assert.Equal(t, "EU", totals.Region)
+	assert.Equal(t, 20.0, totals.Revenue)
+	assert.Equal(t, 4, totals.Units)
+	require.NotNil(t, totals.Largest)
+	assert.Equal(t, int64(9), *totals.Largest)
+	assert.Equal(t, "c", totals.Note)
+
+	t.Run("nil pointers are skipped", func(t *testing.T) {
+		type Max struct {
+			Largest *int64 `mapper:",max"`
+			Count   int
+		}
+		var max Max
+		require.NoError(t, mapper.Aggregate(&max, []Row{{}, {}}, mapper.AggregateRules{"Count": mapper.AggregateCount}))
+		assert.Nil(t, max.Largest)
+		assert.Equal(t, 2, max.Count)
+	})
+
+	t.Run("non-numeric sum", func(t *testing.T) {
+		var totals Totals
+		err := mapper.Aggregate(&totals, rows, mapper.AggregateRules{"Note": mapper.AggregateSum})
+		assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+	})
+}
+
+func TestGroupBy(t *testing.T) {
+	type Row struct {
+		OrderID int32
+		Product string
+		Qty     int
+	}
+	type LineDTO struct {
+		Product string
+		Qty     int
+	}
+	rows := []Row{
+		{OrderID: 1, Product: "pen", Qty: 2},
+		{OrderID: 2, Product: "ink", Qty: 1},
+		{OrderID: 1, Product: "pad", Qty: 5},
+	}
+
+	groups, err := mapper.GroupBy[Row, int64](rows, "OrderID")
+	require.NoError(t, err)
+	assert.Equal(t, map[int64][]Row{1: {rows[0], rows[2]}, 2: {rows[1]}}, groups)
+
+	lines, err := mapper.GroupByMapped[*Row, int32, LineDTO]([]*Row{&rows[0], &rows[1], &rows[2]}, "OrderID")
+	require.NoError(t, err)
+	assert.Equal(t, []LineDTO{{"pen", 2}, {"pad", 5}}, lines[1])
+	assert.Equal(t, []LineDTO{{"ink", 1}}, lines[2])
+
+	_, err = mapper.GroupBy[Row, string](rows, "OrderID")
+	assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+	_, err = mapper.GroupBy[Row, int](rows, "Missing")
+	assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+}
+
+func TestDottedFieldPaths(t *testing.T) {
+	type Address struct {
+		City string
+		Zip  string
+	}
+	type Customer struct {
+		Name    string `mapper:"Name"`
+		Address *Address
+	}
+	type CustomerRow struct {
+		Name string `mapper:"Name"`
+		City string `mapper:"Address.City"`
+		Zip  string `mapper:"Address.Zip"`
+	}
+
+	m := mapper.NewMapper(mapper.WithTagName("mapper"))
+
+	t.Run("flatten", func(t *testing.T) {
+		var row CustomerRow
+		require.NoError(t, m.Map(&row, Customer{Name: "Ada", Address: &Address{City: "London", Zip: "N1"}}))
+		assert.Equal(t, CustomerRow{Name: "Ada", City: "London", Zip: "N1"}, row)
+
+		row = CustomerRow{}
+		require.NoError(t, m.Map(&row, Customer{Name: "Bob"}))
+		assert.Equal(t, CustomerRow{Name: "Bob"}, row)
+	})
+
+	t.Run("unflatten allocates intermediate pointers", func(t *testing.T) {
+		var customer Customer
+		require.NoError(t, m.Map(&customer, CustomerRow{Name: "Ada", City: "London", Zip: "N1"}))
+		assert.Equal(t, "Ada", customer.Name)
+		require.NotNil(t, customer.Address)
+		assert.Equal(t, Address{City: "London", Zip: "N1"}, *customer.Address)
+	})
+
+	t.Run("mapping table", func(t *testing.T) {
+		type Flat struct {
+			Town string
+		}
+		var flat Flat
+		require.NoError(t, mapper.Copy(&flat, Customer{Address: &Address{City: "Paris"}},
+			mapper.WithFieldMapping(map[string]string{"Address.City": "Town"})))
+		assert.Equal(t, "Paris", flat.Town)
+
+		var customer Customer
+		require.NoError(t, mapper.Copy(&customer, flat,
+			mapper.WithFieldMapping(map[string]string{"Town": "Address.City"})))
+		require.NotNil(t, customer.Address)
+		assert.Equal(t, "Paris", customer.Address.City)
+	})
+}
+
+type embeddedModel struct {
+	ID   int64
+	Name string
+}
+
+type EmbeddedModel struct {
+	ID   int64
+	Name string
+}
+
+type embeddedAudit struct {
+	ID      int64
+	Version int
+}
+
+func TestFlattenEmbedded(t *testing.T) {
+	type User struct {
+		*embeddedModel
+		embeddedAudit
+		Name string
+	}
+	type UserRow struct {
+		ID      int64
+		Name    string
+		Version int
+	}
+
+	src := User{embeddedModel: &embeddedModel{ID: 7, Name: "shadowed"}, embeddedAudit: embeddedAudit{ID: 9, Version: 3}, Name: "Ada"}
+
+	var row UserRow
+	require.NoError(t, mapper.Copy(&row, src))
+	assert.Equal(t, UserRow{Name: "Ada"}, row)
+
+	row = UserRow{}
+	require.NoError(t, mapper.Copy(&row, src, mapper.WithFlattenEmbedded(true)))
+	// ID is ambiguous at depth one and Name is shadowed by User.Name
+	assert.Equal(t, UserRow{Name: "Ada", Version: 3}, row)
+
+	t.Run("nil embedded pointer", func(t *testing.T) {
+		type Account struct {
+			*embeddedModel
+		}
+		var row UserRow
+		require.NoError(t, mapper.Copy(&row, Account{}, mapper.WithFlattenEmbedded(true)))
+		assert.Equal(t, UserRow{}, row)
+	})
+
+	t.Run("flat to embedded", func(t *testing.T) {
+		type Account struct {
+			*EmbeddedModel
+			Version int
+		}
+		var account Account
+		require.NoError(t, mapper.Copy(&account, UserRow{ID: 4, Name: "Bob", Version: 2}))
+		require.NotNil(t, account.EmbeddedModel)
+		assert.Equal(t, EmbeddedModel{ID: 4, Name: "Bob"}, *account.EmbeddedModel)
+		assert.Equal(t, 2, account.Version)
+	})
+}
+
+func TestJoin(t *testing.T) {
+	type CustomerID int64
+	type Customer struct {
+		ID   CustomerID
+		Name string
+	}
+	type Order struct {
+		ID         int
+		CustomerID int64
+		ShipperID  *string
+	}
+	type CustomerDTO struct {
+		Name string
+	}
+	type OrderDTO struct {
+		ID       int
+		Customer *CustomerDTO
+		Shipper  string
+	}
+
+	customers := map[CustomerID]Customer{1: {ID: 1, Name: "Ada"}, 2: {ID: 2, Name: "Bob"}}
+	shippers := map[string]string{"ups": "UPS"}
+	ups := "ups"
+	orders := []Order{{ID: 10, CustomerID: 2, ShipperID: &ups}, {ID: 11, CustomerID: 3}}
+
+	dtos, err := mapper.Join[Order, OrderDTO](orders, []mapper.Lookup{
+		mapper.LookupBy("CustomerID", "Customer", customers),
+		mapper.LookupBy("ShipperID", "Shipper", shippers),
+	})
+	require.NoError(t, err)
+	require.Len(t, dtos, 2)
+	assert.Equal(t, OrderDTO{ID: 10, Customer: &CustomerDTO{Name: "Bob"}, Shipper: "UPS"}, dtos[0])
+	assert.Equal(t, OrderDTO{ID: 11}, dtos[1])
+
+	_, err = mapper.Join[Order, OrderDTO](orders, []mapper.Lookup{mapper.LookupBy("Missing", "Customer", customers)})
+	assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+}
+
+type hookedLine struct {
+	Net   int
+	Tax   int
+	Total int
+	src   interface{}
+}
+
+func (l *hookedLine) AfterMap(src interface{}) error {
+	l.Total = l.Net + l.Tax
+	l.src = src
+	return nil
+}
+
+type hookedInvoice struct {
+	Lines []hookedLine
+	Count int
+}
+
+func (i *hookedInvoice) AfterMap(interface{}) error {
+	if i.Count != len(i.Lines) {
+		return mapper.ErrTypeMismatch
+	}
+	return nil
+}
+
+func TestMapHooks(t *testing.T) {
+	type Line struct{ Net, Tax int }
+	type Invoice struct {
+		Lines []Line
+		Count int
+	}
+
+	var calls []string
+	m := mapper.NewMapper(
+		mapper.WithBeforeMap(func(dst, src any) error {
+			calls = append(calls, "before")
+			return nil
+		}),
+		mapper.WithAfterMap(func(dst, src any) error {
+			calls = append(calls, "after")
+			return nil
+		}),
+	)
+
+	var dst hookedInvoice
+	src := Invoice{Lines: []Line{{Net: 10, Tax: 2}}, Count: 1}
+	require.NoError(t, m.Map(&dst, src))
+	assert.Equal(t, []string{"before", "after"}, calls)
+	assert.Equal(t, 12, dst.Lines[0].Total)
+	assert.Equal(t, src.Lines[0], dst.Lines[0].src)
+
+	src.Count = 2
+	assert.ErrorIs(t, mapper.Copy(&dst, src), mapper.ErrTypeMismatch)
+
+	err := mapper.Copy(&dst, src, mapper.WithBeforeMap(func(dst, src any) error {
+		return mapper.ErrDoNotMap
+	}))
+	assert.ErrorIs(t, err, mapper.ErrDoNotMap)
+}
+
+func TestBuildTree(t *testing.T) {
+	type Row struct {
+		ID       int
+		ParentID *int
+		Name     string
+	}
+	type Node struct {
+		ID    int
+		Name  string
+		Nodes []*Node
+	}
+
+	id := func(v int) *int { return &v }
+	rows := []Row{
+		{ID: 1, Name: "root"},
+		{ID: 2, ParentID: id(1), Name: "a"},
+		{ID: 3, ParentID: id(2), Name: "a1"},
+		{ID: 4, ParentID: id(1), Name: "b"},
+		{ID: 5, ParentID: id(99), Name: "orphan"},
+	}
+
+	roots, err := mapper.BuildTree[Row, Node](rows, mapper.TreeOptions{ChildrenField: "Nodes"})
+	require.NoError(t, err)
+	require.Len(t, roots, 2)
+	assert.Equal(t, "root", roots[0].Name)
+	require.Len(t, roots[0].Nodes, 2)
+	assert.Equal(t, "a", roots[0].Nodes[0].Name)
+	assert.Equal(t, "a1", roots[0].Nodes[0].Nodes[0].Name)
+	assert.Equal(t, "b", roots[0].Nodes[1].Name)
+	assert.Equal(t, "orphan", roots[1].Name)
+
+	cyclic := []Row{{ID: 1, ParentID: id(2)}, {ID: 2, ParentID: id(1)}}
+	_, err = mapper.BuildTree[Row, Node](cyclic, mapper.TreeOptions{ChildrenField: "Nodes"})
+	assert.ErrorIs(t, err, mapper.ErrCircularReference)
+
+	_, err = mapper.BuildTree[Row, Node](rows, mapper.TreeOptions{})
+	assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+}
+
+func TestMapPage(t *testing.T) {
+	type User struct {
+		ID   int
+		Name string
+	}
+	type UserDTO struct {
+		Name string
+	}
+
+	page := mapper.Page[User]{
+		Items:      []User{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Bob"}},
+		PageSize:   2,
+		Total:      10,
+		NextCursor: "abc",
+	}
+	dtos, err := mapper.MapPage[User, UserDTO](page)
+	require.NoError(t, err)
+	assert.Equal(t, mapper.Page[UserDTO]{
+		Items:      []UserDTO{{"Ada"}, {"Bob"}},
+		PageSize:   2,
+		Total:      10,
+		NextCursor: "abc",
+	}, dtos)
+
+	empty, err := mapper.MapPage[User, UserDTO](mapper.Page[User]{Total: 0})
+	require.NoError(t, err)
+	assert.Nil(t, empty.Items)
+}
+
+func TestMapContext(t *testing.T) {
+	type localeKey struct{}
+	type Price struct{ Cents int64 }
+	type Item struct {
+		Name  string
+		Price Price
+	}
+	type ItemDTO struct {
+		Name  string
+		Price string
+	}
+
+	m := mapper.NewMapper(mapper.WithContextConverter(reflect.TypeOf(Price{}),
+		func(ctx context.Context, v reflect.Value) (reflect.Value, error) {
+			sep, _ := ctx.Value(localeKey{}).(string)
+			if sep == "" {
+				sep = "."
+			}
+			cents := v.Interface().(Price).Cents
+			return reflect.ValueOf(fmt.Sprintf("%d%s%02d", cents/100, sep, cents%100)), nil
+		}))
+
+	src := Item{Name: "pen", Price: Price{Cents: 1250}}
+
+	var dst ItemDTO
+	require.NoError(t, m.MapContext(context.WithValue(context.Background(), localeKey{}, ","), &dst, src))
+	assert.Equal(t, ItemDTO{Name: "pen", Price: "12,50"}, dst)
+
+	require.NoError(t, m.Map(&dst, src))
+	assert.Equal(t, "12.50", dst.Price)
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		items := make([]Item, 1000)
+		var dsts []ItemDTO
+		calls := 0
+		m := mapper.NewMapper(mapper.WithContextConverter(reflect.TypeOf(Price{}),
+			func(_ context.Context, v reflect.Value) (reflect.Value, error) {
+				if calls++; calls == 10 {
+					cancel()
+				}
+				return reflect.ValueOf(""), nil
+			}))
+		err := m.MapContext(ctx, &dsts, items)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 10, calls)
+	})
+}
+
+func TestUnitConversion(t *testing.T) {
+	type Reading struct {
+		Distance float64 `mapper:",unit=m->km"`
+		TempC    float64
+		Duration *int    `mapper:",unit=s->min"`
+		Depth    float64 `mapper:",unit=m->fathom"`
+	}
+	type ReadingDTO struct {
+		Distance float64
+		TempC    int `mapper:",unit=c->f"`
+		Duration int
+		Depth    float64
+	}
+
+	secs := 150
+	var dst ReadingDTO
+	err := mapper.Copy(&dst, Reading{Distance: 1500, TempC: 100, Duration: &secs})
+	assert.ErrorContains(t, err, "unknown unit")
+
+	mapper.RegisterUnit("fathom", "length", 1.8288, 0)
+	dst = ReadingDTO{}
+	require.NoError(t, mapper.Copy(&dst, Reading{Distance: 1500, TempC: 100, Duration: &secs, Depth: 18.288}))
+	assert.InDelta(t, 1.5, dst.Distance, 1e-9)
+	assert.Equal(t, 212, dst.TempC)
+	assert.Equal(t, 3, dst.Duration)
+	assert.InDelta(t, 10, dst.Depth, 1e-9)
+
+	type Temps struct {
+		Reading string `mapper:",unit=c->km"`
+	}
+	err = mapper.Copy(&Temps{}, Temps{Reading: "x"})
+	assert.ErrorContains(t, err, mapper.ErrTypeMismatch.Error())
+}
+
+func TestUnitConversionTemperatures(t *testing.T) {
+	type Celsius struct {
+		Temp float64 `mapper:",unit=c->f"`
+	}
+	type Fahrenheit struct {
+		Temp float64 `mapper:",unit=f->c"`
+	}
+	type Kelvin struct {
+		Temp float64 `mapper:",unit=k->c"`
+	}
+
+	var f Fahrenheit
+	require.NoError(t, mapper.Copy(&f, Celsius{Temp: 100}))
+	assert.Equal(t, 212.0, f.Temp)
+	require.NoError(t, mapper.Copy(&f, Celsius{Temp: -40}))
+	assert.Equal(t, -40.0, f.Temp)
+
+	var c Celsius
+	require.NoError(t, mapper.Copy(&c, Fahrenheit{Temp: 212}))
+	assert.Equal(t, 100.0, c.Temp)
+	require.NoError(t, mapper.Copy(&c, Kelvin{Temp: 273.15}))
+	assert.Equal(t, 0.0, c.Temp)
+}
+
+func TestRegisterUnitAfterPlanning(t *testing.T) {
+	type Leg struct {
+		Length float64 `mapper:",unit=m->league"`
+	}
+
+	m := mapper.NewMapper()
+	var dst Leg
+	assert.ErrorContains(t, m.Map(&dst, Leg{Length: 4828.032}), "unknown unit")
+
+	// The plan cached above picks up the unit
+	mapper.RegisterUnit("league", "length", 4828.032, 0)
+	require.NoError(t, m.Map(&dst, Leg{Length: 4828.032}))
+	assert.InDelta(t, 1, dst.Length, 1e-9)
+}
+
+func TestTimeLayoutConversions(t *testing.T) {
+	type Event struct {
+		At      time.Time
+		Created time.Time
+		Due     string
+		Stamp   int64
+	}
+	type EventDTO struct {
+		At      string
+		Created int64
+		Due     time.Time
+		Stamp   time.Time
+	}
+
+	at := time.Date(2024, 3, 10, 22, 30, 0, 0, time.UTC)
+	src := Event{At: at, Created: at, Due: "2024-03-11 08:00", Stamp: at.Unix()}
+
+	var dst EventDTO
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithTimeLayout("2006-01-02 15:04")))
+	assert.Equal(t, "2024-03-10 22:30", dst.At)
+	assert.Equal(t, at.Unix(), dst.Created)
+	assert.True(t, time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC).Equal(dst.Due))
+	assert.True(t, at.Equal(dst.Stamp))
+
+	tokyo := time.FixedZone("JST", 9*3600)
+	dst = EventDTO{}
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithTimeLayout("2006-01-02 15:04"), mapper.WithTimeZone(tokyo)))
+	assert.Equal(t, "2024-03-11 07:30", dst.At)
+	assert.Equal(t, tokyo, dst.Due.Location())
+	assert.Equal(t, 8, dst.Due.Hour())
+
+	var iso struct{ At string }
+	require.NoError(t, mapper.Copy(&iso, src, mapper.WithTimeZone(time.UTC)))
+	assert.Equal(t, "2024-03-10T22:30:00Z", iso.At)
+
+	err := mapper.Copy(&dst, Event{Due: "soon"}, mapper.WithTimeLayout(time.RFC3339))
+	assert.ErrorContains(t, err, mapper.ErrTypeMismatch.Error())
+}
+
+func TestFingerprint(t *testing.T) {
+	type Profile struct {
+		Name     string         `json:"name"`
+		Tags     map[string]int `json:"tags"`
+		Seen     time.Time      `json:"seen"`
+		Next     *Profile       `json:"next"`
+		Password string         `json:"-"`
+		cache    map[string]string
+	}
+
+	seen := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := &Profile{Name: "ada", Tags: map[string]int{"x": 1, "y": 2, "z": 3}, Seen: seen, Password: "a"}
+	a.Next = a
+	b := &Profile{Name: "ada", Tags: map[string]int{"z": 3, "y": 2, "x": 1}, Seen: seen.In(time.FixedZone("X", 3600)), Password: "b", cache: map[string]string{"k": "v"}}
+	b.Next = b
+
+	opts := []mapper.Option{mapper.WithTagName("json")}
+	fa, err := mapper.Fingerprint(a, opts...)
+	require.NoError(t, err)
+	fb, err := mapper.Fingerprint(b, opts...)
+	require.NoError(t, err)
+	assert.Equal(t, fa, fb)
+
+	b.Tags["z"] = 4
+	fb, err = mapper.Fingerprint(b, opts...)
+	require.NoError(t, err)
+	assert.NotEqual(t, fa, fb)
+
+	withPassword, err := mapper.Fingerprint(a)
+	require.NoError(t, err)
+	assert.NotEqual(t, fa, withPassword)
+
+	_, err = mapper.Fingerprint(struct{ DB *dbHandle }{DB: &dbHandle{}})
+	assert.ErrorIs(t, err, mapper.ErrDoNotMap)
+}
+
+func TestStringConversion(t *testing.T) {
+	type Form struct {
+		Age    string
+		Score  string
+		Active string
+		Count  int
+		Ratio  float64
+	}
+	type Model struct {
+		Age    int8
+		Score  float64
+		Active bool
+		Count  string
+		Ratio  string
+	}
+
+	var dst Model
+	require.NoError(t, mapper.Copy(&dst, Form{Age: " 42", Score: "12.5", Active: "true", Count: 65, Ratio: 0.25},
+		mapper.WithStringConversion(true)))
+	assert.Equal(t, Model{Age: 42, Score: 12.5, Active: true, Count: "65", Ratio: "0.25"}, dst)
+
+	var failed []string
+	err := mapper.Copy(&dst, Form{Age: "300", Score: "x"},
+		mapper.WithStringConversion(true),
+		mapper.WithErrorHandler(func(err error, srcField, dstField string) error {
+			assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+			failed = append(failed, srcField)
+			return nil
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Age", "Score"}, failed)
+}
+
+func TestSliceElementMapping(t *testing.T) {
+	type Entity struct {
+		ID   int
+		Name string
+	}
+	type DTO struct {
+		Name string
+	}
+
+	t.Run("heterogeneous elements and arrays", func(t *testing.T) {
+		type Src struct {
+			Items  []Entity
+			Fixed  [3]int
+			Window []int
+		}
+		type Dst struct {
+			Items  []DTO
+			Fixed  []int64
+			Window [2]int
+		}
+
+		dst := Dst{Window: [2]int{7, 7}}
+		err := mapper.Copy(&dst, Src{Items: []Entity{{1, "a"}, {2, "b"}}, Fixed: [3]int{1, 2, 3}, Window: []int{5}})
+		require.NoError(t, err)
+		assert.Equal(t, []DTO{{"a"}, {"b"}}, dst.Items)
+		assert.Equal(t, []int64{1, 2, 3}, dst.Fixed)
+		assert.Equal(t, [2]int{5, 0}, dst.Window)
+
+		err = mapper.Copy(&dst, Src{Window: []int{1, 2, 3}})
+		assert.ErrorContains(t, err, "do not fit")
+	})
+
+	t.Run("single elements", func(t *testing.T) {
+		type Src struct {
+			Tags  string
+			Owner []Entity
+		}
+		type Dst struct {
+			Tags  []string
+			Owner DTO
+		}
+
+		var dst Dst
+		require.NoError(t, mapper.Copy(&dst, Src{Tags: "go", Owner: []Entity{{1, "ada"}}}))
+		assert.Equal(t, Dst{Tags: []string{"go"}, Owner: DTO{"ada"}}, dst)
+
+		err := mapper.Copy(&dst, Src{Owner: []Entity{{1, "a"}, {2, "b"}}})
+		assert.ErrorContains(t, err, mapper.ErrTypeMismatch.Error())
+	})
+
+	t.Run("per-index errors", func(t *testing.T) {
+		type Src struct{ Nums []any }
+		type Dst struct{ Nums []int }
+
+		err := mapper.NewMapper().Map(&Dst{}, Src{Nums: []any{1, &dbHandle{}}})
+		assert.ErrorContains(t, err, "[1]")
+		assert.ErrorContains(t, err, mapper.ErrDoNotMap.Error())
+
+		err = mapper.Copy(&Dst{}, struct{ Nums []Entity }{Nums: []Entity{{}}})
+		assert.ErrorContains(t, err, mapper.ErrTypeMismatch.Error())
+	})
+}
+
+func TestCanonical(t *testing.T) {
+	type Line struct {
+		SKU string
+		Qty int
+	}
+	type Order struct {
+		ID     int
+		Placed time.Time
+		Lines  []Line
+		Meta   map[string]any
+		Note   *string
+	}
+
+	placed := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	order := Order{
+		ID:     7,
+		Placed: placed,
+		Lines:  []Line{{"a", 1}},
+		Meta:   map[string]any{"z": 1, "a": []int{2}},
+	}
+
+	got, err := mapper.Canonical(order)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ID":7,"Lines":[{"Qty":1,"SKU":"a"}],"Meta":{"a":[2],"z":1},"Note":null,"Placed":"2024-05-01T08:00:00Z"}`, string(got))
+
+	utc := order
+	utc.Placed = placed.UTC()
+	again, err := mapper.Canonical(utc)
+	require.NoError(t, err)
+	assert.Equal(t, got, again)
+
+	node := &cycleNode{Name: "loop"}
+	node.Next = node
+	_, err = mapper.Canonical(node)
+	assert.ErrorIs(t, err, mapper.ErrCircularReference)
+}
+
+func TestMapStructConversion(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type User struct {
+		Base
+		Name    string   `json:"name,omitempty"`
+		Age     int      `json:"age"`
+		Address *Address `json:"address"`
+		Tags    []string `json:"tags"`
+		secret  string
+	}
+
+	user := User{Base: Base{ID: 1}, Name: "Ada", Age: 36, Address: &Address{City: "London"}, Tags: []string{"x"}, secret: "s"}
+
+	t.Run("struct to map", func(t *testing.T) {
+		var m map[string]any
+		require.NoError(t, mapper.Copy(&m, user, mapper.WithJSONTag(true)))
+		assert.Equal(t, map[string]any{
+			"id":      1,
+			"name":    "Ada",
+			"age":     36,
+			"address": map[string]any{"city": "London"},
+			"tags":    []string{"x"},
+		}, m)
+	})
+
+	t.Run("map to struct", func(t *testing.T) {
+		payload := map[string]any{
+			"ID":      float64(2),
+			"name":    "Bob",
+			"AGE":     float64(40),
+			"address": map[string]any{"city": "Paris"},
+			"tags":    []any{"a", "b"},
+		}
+		var dst User
+		require.NoError(t, mapper.Copy(&dst, payload, mapper.WithJSONTag(true), mapper.WithCaseSensitive(false)))
+		assert.Equal(t, User{Base: Base{ID: 2}, Name: "Bob", Age: 40, Address: &Address{City: "Paris"}, Tags: []string{"a", "b"}}, dst)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var m map[string]any
+		require.NoError(t, mapper.Copy(&m, user))
+		var back User
+		require.NoError(t, mapper.Copy(&back, m))
+		user.secret = ""
+		assert.Equal(t, user, back)
+	})
+}
+
+type auditFields struct {
+	CreatedBy string
+}
+
+type AuditDTO struct {
+	CreatedBy string
+}
+
+func TestEmbeddedPointerBridging(t *testing.T) {
+	type Entity struct {
+		*EmbeddedModel
+		*auditFields
+		Status string
+	}
+	type ValueEntity struct {
+		EmbeddedModel
+		auditFields
+		Status string
+	}
+	type DTO struct {
+		EmbeddedModel
+		AuditDTO
+		Status string
+	}
+
+	src := Entity{EmbeddedModel: &EmbeddedModel{ID: 1, Name: "a"}, auditFields: &auditFields{CreatedBy: "ops"}, Status: "ok"}
+
+	var value ValueEntity
+	require.NoError(t, mapper.Copy(&value, src))
+	assert.Equal(t, ValueEntity{EmbeddedModel: EmbeddedModel{ID: 1, Name: "a"}, auditFields: auditFields{CreatedBy: "ops"}, Status: "ok"}, value)
+
+	var dto DTO
+	require.NoError(t, mapper.Copy(&dto, src))
+	assert.Equal(t, DTO{EmbeddedModel: EmbeddedModel{ID: 1, Name: "a"}, AuditDTO: AuditDTO{CreatedBy: "ops"}, Status: "ok"}, dto)
+
+	var back Entity
+	require.NoError(t, mapper.Copy(&back, dto))
+	require.NotNil(t, back.EmbeddedModel)
+	assert.Equal(t, EmbeddedModel{ID: 1, Name: "a"}, *back.EmbeddedModel)
+	assert.Nil(t, back.auditFields, "unexported embedded pointers cannot be allocated")
+}
+
+func TestMergeModes(t *testing.T) {
+	type Limits struct {
+		CPU    int
+		Memory int
+	}
+	type Settings struct {
+		Name   string
+		Region string
+		Tags   []string
+		Limits *Limits
+	}
+
+	defaults := Settings{Name: "default", Region: "eu", Tags: []string{"d"}, Limits: &Limits{CPU: 1, Memory: 512}}
+
+	t.Run("skip existing non-zero", func(t *testing.T) {
+		dst := Settings{Name: "mine", Limits: &Limits{Memory: 2048}}
+		require.NoError(t, mapper.Copy(&dst, defaults, mapper.WithMergeMode(mapper.MergeSkipExistingNonZero)))
+		assert.Equal(t, Settings{Name: "mine", Region: "eu", Tags: []string{"d"}, Limits: &Limits{CPU: 1, Memory: 2048}}, dst)
+	})
+
+	t.Run("skip zero source", func(t *testing.T) {
+		dst := defaults
+		dst.Limits = &Limits{CPU: 1, Memory: 512}
+		patch := Settings{Region: "us", Limits: &Limits{CPU: 4}}
+		require.NoError(t, mapper.Copy(&dst, patch, mapper.WithMergeMode(mapper.MergeSkipZeroSource)))
+		assert.Equal(t, Settings{Name: "default", Region: "us", Tags: []string{"d"}, Limits: &Limits{CPU: 4, Memory: 512}}, dst)
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		dst := Settings{Name: "mine"}
+		require.NoError(t, mapper.Copy(&dst, Settings{Region: "eu"}))
+		assert.Equal(t, Settings{Region: "eu"}, dst)
+	})
+}
+
+func TestFieldSplitCombine(t *testing.T) {
+	type Person struct {
+		FullName string
+		Age      int
+	}
+	type PersonDTO struct {
+		FirstName string
+		LastName  string
+		Age       int
+	}
+
+	split := mapper.WithFieldSplit("FullName", []string{"FirstName", "LastName"}, func(src any) ([]any, error) {
+		first, last, _ := strings.Cut(src.(string), " ")
+		return []any{first, last}, nil
+	})
+	combine := mapper.WithFieldCombine([]string{"FirstName", "LastName"}, "FullName", func(srcs []any) (any, error) {
+		return strings.TrimSpace(srcs[0].(string) + " " + srcs[1].(string)), nil
+	})
+
+	var dto PersonDTO
+	require.NoError(t, mapper.Copy(&dto, Person{FullName: "Ada Lovelace", Age: 36}, split, combine))
+	assert.Equal(t, PersonDTO{FirstName: "Ada", LastName: "Lovelace", Age: 36}, dto)
+
+	var person Person
+	require.NoError(t, mapper.Copy(&person, dto, split, combine))
+	assert.Equal(t, Person{FullName: "Ada Lovelace", Age: 36}, person)
+
+	bad := mapper.WithFieldSplit("FullName", []string{"FirstName", "LastName"}, func(any) ([]any, error) {
+		return []any{"only"}, nil
+	})
+	err := mapper.Copy(&dto, Person{FullName: "x"}, bad)
+	assert.ErrorContains(t, err, mapper.ErrTypeMismatch.Error())
+}
+
+func TestForMemberCombine(t *testing.T) {
+	type Location struct {
+		Street string
+		City   string
+		Zip    *string
+	}
+	type Customer struct {
+		Name     string
+		Location Location
+	}
+	type CustomerDTO struct {
+		Name    string
+		Address string
+		City    string
+	}
+
+	zip := "10115"
+	src := Customer{Name: "Ada", Location: Location{Street: "Main St 1", City: "Berlin", Zip: &zip}}
+	opts := []mapper.Option{
+		mapper.ForMember("Address", mapper.Combine(mapper.JoinWith(", "), "Location.Street", "Location.City", "Location.Zip")),
+		mapper.ForMember("City", mapper.MapFrom("Location.City")),
+	}
+
+	var dto CustomerDTO
+	require.NoError(t, mapper.Copy(&dto, src, opts...))
+	assert.Equal(t, CustomerDTO{Name: "Ada", Address: "Main St 1, Berlin, 10115", City: "Berlin"}, dto)
+
+	src.Location.Zip = nil
+	require.NoError(t, mapper.Copy(&dto, src, opts...))
+	assert.Equal(t, "Main St 1, Berlin", dto.Address)
+}
+
+func TestMergePatch(t *testing.T) {
+	type User struct {
+		Name   string
+		Email  string
+		Age    int
+		Active bool
+	}
+	type UserPatch struct {
+		Name   *string
+		Email  mapper.Optional[string]
+		Age    *int
+		Active mapper.Optional[bool]
+	}
+
+	var patch UserPatch
+	require.NoError(t, json.Unmarshal([]byte(`{"Age": 0, "Active": false}`), &patch))
+	assert.True(t, patch.Active.Set)
+	assert.False(t, patch.Email.Set)
+
+	user := User{Name: "Ada", Email: "ada@example.com", Age: 36, Active: true}
+	require.NoError(t, mapper.Copy(&user, patch, mapper.WithMergeMode(mapper.MergePatch)))
+	assert.Equal(t, User{Name: "Ada", Email: "ada@example.com", Age: 0, Active: false}, user)
+
+	patch = UserPatch{Email: mapper.Some("new@example.com")}
+	require.NoError(t, mapper.Copy(&user, patch, mapper.WithMergeMode(mapper.MergePatch)))
+	assert.Equal(t, "new@example.com", user.Email)
+	assert.Equal(t, "Ada", user.Name)
+
+	var back UserPatch
+	require.NoError(t, mapper.Copy(&back, user))
+	assert.Equal(t, mapper.Some(false), back.Active)
+	assert.Equal(t, mapper.Some("new@example.com"), back.Email)
+}
+
+func TestIgnoreFields(t *testing.T) {
+	type Audit struct {
+		CreatedBy string
+		Reason    string
+	}
+	type Account struct {
+		Name      string
+		Password  string
+		SecretKey string
+		Token     string
+		Audit     Audit
+		Extra     map[string]interface{} `mapper:",overflow"`
+	}
+	type AccountDTO struct {
+		Name      string
+		Password  string
+		SecretKey string
+		Token     string `mapper:"-"`
+		Audit     Audit
+		Extra     map[string]interface{} `mapper:",overflow"`
+	}
+
+	src := Account{
+		Name: "ada", Password: "hunter2", SecretKey: "k", Token: "t",
+		Audit: Audit{CreatedBy: "root", Reason: "signup"},
+	}
+
+	var dst AccountDTO
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithIgnoreFields("Password", "Secret*", "Audit.*")))
+	assert.Equal(t, "ada", dst.Name)
+	assert.Empty(t, dst.Password)
+	assert.Empty(t, dst.SecretKey)
+	assert.Empty(t, dst.Token)
+	assert.Equal(t, Audit{}, dst.Audit)
+	assert.NotContains(t, dst.Extra, "Token")
+
+	out := map[string]interface{}{}
+	require.NoError(t, mapper.Copy(&out, src, mapper.WithIgnoreFields("Password")))
+	assert.NotContains(t, out, "Password")
+	assert.Equal(t, "ada", out["Name"])
+}
+
+func TestIgnoreTypes(t *testing.T) {
+	type logger struct{ prefix string }
+	type Service struct {
+		Name string
+		Ctx  context.Context
+		Log  *logger
+	}
+	type ServiceCopy struct {
+		Name string
+		Ctx  context.Context
+		Log  *logger
+	}
+
+	src := Service{Name: "billing", Ctx: context.Background(), Log: &logger{prefix: "billing"}}
+	var dst ServiceCopy
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithIgnoreTypes(
+		reflect.TypeOf((*context.Context)(nil)).Elem(),
+		reflect.TypeOf(logger{}),
+	)))
+	assert.Equal(t, ServiceCopy{Name: "billing"}, dst)
+}
+
+func TestMapperPlan(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string
+	}
+	type AddressDTO struct {
+		Street string
+		Zip    string
+	}
+	type Customer struct {
+		Name     string
+		Address  Address
+		Internal string
+		Price    float64
+	}
+	type CustomerDTO struct {
+		Name    string
+		Address AddressDTO
+		Price   float64
+		Email   string
+	}
+
+	m := mapper.NewMapper(mapper.WithFieldConverter("Price", func(v reflect.Value) (reflect.Value, error) {
+		return v, nil
+	}))
+	plan, err := m.Plan(reflect.TypeOf(&Customer{}), reflect.TypeOf(CustomerDTO{}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []mapper.PlannedField{
+		{Source: "Name", Destination: "Name"},
+		{Source: "Address.Street", Destination: "Address.Street"},
+		{Source: "Price", Destination: "Price", Converter: "field"},
+	}, plan.Fields)
+	assert.Equal(t, []string{"Address.City", "Internal"}, plan.UnmappedSource)
+	assert.Equal(t, []string{"Address.Zip", "Email"}, plan.UnmappedDestination)
+
+	_, err = m.Plan(reflect.TypeOf(0), reflect.TypeOf(CustomerDTO{}))
+	assert.ErrorIs(t, err, mapper.ErrUnsupportedType)
+}
+
+func TestSkipZeroStructs(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type Patch struct {
+		Name    string
+		Address Address
+		Billing Address
+	}
+	type User struct {
+		Name    string
+		Address Address
+		Billing *Address
+	}
+
+	user := User{Name: "Ada", Address: Address{City: "London"}}
+	require.NoError(t, mapper.Copy(&user, Patch{Name: "Ada L."}, mapper.WithSkipZeroStructs(true)))
+	assert.Equal(t, User{Name: "Ada L.", Address: Address{City: "London"}}, user)
+
+	require.NoError(t, mapper.Copy(&user, Patch{Billing: Address{City: "Paris"}}, mapper.WithSkipZeroStructs(true)))
+	require.NotNil(t, user.Billing)
+	assert.Equal(t, "Paris", user.Billing.City)
+	assert.Equal(t, "London", user.Address.City)
+}
+
+type typedNilErr struct{}
+
+func (*typedNilErr) Error() string { return "typed nil" }
+
+func TestTypedNilPolicy(t *testing.T) {
+	type Result struct {
+		Err error
+	}
+	var nilErr *typedNilErr
+	src := Result{Err: nilErr}
+	existing := errors.New("existing")
+
+	var dst Result
+	require.NoError(t, mapper.Copy(&dst, src))
+	assert.True(t, dst.Err == nil)
+
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithTypedNilPolicy(mapper.TypedNilKeep)))
+	assert.False(t, dst.Err == nil)
+	assert.IsType(t, nilErr, dst.Err)
+
+	dst = Result{Err: existing}
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithTypedNilPolicy(mapper.TypedNilSkip)))
+	assert.Equal(t, existing, dst.Err)
+
+	err := mapper.Copy(&dst, src, mapper.WithTypedNilPolicy(mapper.TypedNilError))
+	assert.ErrorContains(t, err, mapper.ErrTypedNil.Error())
+}
+
+func TestMappingErrorsCollectsAll(t *testing.T) {
+	type Src struct {
+		A string
+		B string
+	}
+	type Dst struct {
+		A []int
+		B map[string]int
+	}
+
+	err := mapper.Copy(&Dst{}, Src{A: "a", B: "b"}, mapper.WithFieldConverter("A", func(reflect.Value) (reflect.Value, error) {
+		return reflect.Value{}, mapper.ErrUnsupportedType
+	}), mapper.WithFieldConverter("B", func(reflect.Value) (reflect.Value, error) {
+		return reflect.Value{}, mapper.ErrTypeMismatch
+	}))
+
+	var errs *mapper.MappingErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Len(t, errs.Errors(), 2)
+	assert.ErrorIs(t, err, mapper.ErrUnsupportedType)
+	assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+}
+
+func TestWarnings(t *testing.T) {
+	type Reading struct {
+		Sensor string
+		Value  int64
+		Ratio  float64
+	}
+	type Compact struct {
+		Sensor string
+		Value  int8
+		Ratio  float32
+	}
+
+	var handled []mapper.Warning
+	m := mapper.NewMapper(mapper.WithWarningHandler(func(w mapper.Warning) {
+		handled = append(handled, w)
+	}))
+
+	var dst Compact
+	report, err := m.MapWithReport(&dst, Reading{Sensor: "t1", Value: 300, Ratio: 0.5})
+	require.NoError(t, err)
+	require.Len(t, report.Warnings, 1)
+	assert.Equal(t, mapper.WarningLossyConversion, report.Warnings[0].Kind)
+	assert.Equal(t, "Reading.Value", report.Warnings[0].Path)
+	assert.Equal(t, report.Warnings, handled)
+
+	report, err = m.MapWithReport(&dst, map[string]interface{}{"Sensor": "t2", "Unit": "C"})
+	require.NoError(t, err)
+	require.Len(t, report.Warnings, 1)
+	assert.Equal(t, mapper.WarningUnknownKey, report.Warnings[0].Kind)
+	assert.Equal(t, "t2", dst.Sensor)
+}
+
+func TestCollectionRootPaths(t *testing.T) {
+	type Item struct {
+		Price string
+	}
+	type ItemDTO struct {
+		Price float64
+	}
+
+	parsePrice := mapper.WithFieldConverter("Item.Price", func(v reflect.Value) (reflect.Value, error) {
+		f, err := strconv.ParseFloat(v.String(), 64)
+		return reflect.ValueOf(f), err
+	})
+
+	var items []ItemDTO
+	err := mapper.Copy(&items, []Item{{Price: "1.50"}, {Price: "n/a"}}, parsePrice)
+	var mapErr *mapper.MapError
+	require.ErrorAs(t, err, &mapErr)
+	assert.Equal(t, "Item[1].Price", mapErr.Path)
+	assert.Equal(t, 1.5, items[0].Price)
+
+	var byKey map[string]*ItemDTO
+	err = mapper.Copy(&byKey, map[string]*Item{"k": {Price: "n/a"}}, parsePrice)
+	require.ErrorAs(t, err, &mapErr)
+	assert.Equal(t, `Item["k"].Price`, mapErr.Path)
+	require.NoError(t, mapper.Copy(&byKey, map[string]*Item{"k": {Price: "2"}}, parsePrice))
+	assert.Equal(t, 2.0, byKey["k"].Price)
+
+	// Anonymous roots have no root segment
+	var anon struct{ Price float64 }
+	err = mapper.Copy(&anon, struct{ Price string }{Price: "n/a"},
+		mapper.WithFieldConverter("Price", func(v reflect.Value) (reflect.Value, error) {
+			f, err := strconv.ParseFloat(v.String(), 64)
+			return reflect.ValueOf(f), err
+		}))
+	require.ErrorAs(t, err, &mapErr)
+	assert.Equal(t, "Price", mapErr.Path)
+
+	// Ignore patterns match below collection and anonymous roots too
+	var anonText struct{ Price string }
+	require.NoError(t, mapper.Copy(&anonText, struct{ Price string }{Price: "n/a"}, mapper.WithIgnoreFields("Price")))
+	assert.Empty(t, anonText.Price)
+	items = nil
+	require.NoError(t, mapper.Copy(&items, []Item{{Price: "n/a"}}, mapper.WithIgnoreFields("Item.Price")))
+	assert.Equal(t, []ItemDTO{{}}, items)
+}
+
+func TestMapErrorPath(t *testing.T) {
+	type Item struct {
+		Price string
+	}
+	type Order struct {
+		Items []Item
+	}
+	type ItemDTO struct {
+		Price float64
+	}
+	type OrderDTO struct {
+		Items []ItemDTO
+	}
+
+	src := Order{Items: []Item{{Price: "1.50"}, {Price: "n/a"}}}
+	err := mapper.Copy(&OrderDTO{}, src, mapper.WithFieldConverter("Items.Price", func(v reflect.Value) (reflect.Value, error) {
+		f, err := strconv.ParseFloat(v.String(), 64)
+		return reflect.ValueOf(f), err
+	}))
+
+	var mapErr *mapper.MapError
+	require.ErrorAs(t, err, &mapErr)
+	assert.Equal(t, "Order.Items[1].Price", mapErr.Path)
+	assert.Equal(t, "Price", mapErr.SrcField)
+	assert.Equal(t, "string", mapErr.SrcType)
+	assert.Equal(t, "float64", mapErr.DstType)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
+
+func TestMemberFallbacks(t *testing.T) {
+	type Profile struct {
+		DisplayName *string
+		Username    string
+	}
+	type ProfileDTO struct {
+		Name string
+	}
+
+	opt := mapper.ForMember("Name", mapper.MapFrom("DisplayName").OrFrom("Username").OrValue("anonymous"))
+	display := "Ada L."
+
+	cases := []struct {
+		src  Profile
+		want string
+	}{
+		{Profile{DisplayName: &display, Username: "ada"}, "Ada L."},
+		{Profile{Username: "ada"}, "ada"},
+		{Profile{}, "anonymous"},
+	}
+	for _, tc := range cases {
+		var dst ProfileDTO
+		require.NoError(t, mapper.Copy(&dst, tc.src, opt))
+		assert.Equal(t, tc.want, dst.Name)
+	}
+}
+
+func TestZeroRulesForDefaultsAndFallbacks(t *testing.T) {
+	placeholder := mapper.WithZeroChecker(reflect.TypeOf(""), func(v reflect.Value) bool {
+		return v.String() == "" || v.String() == "-"
+	})
+
+	type Source struct {
+		Nick     string
+		Username string
+	}
+	type Tagged struct {
+		Nick string `mapper:",default=unknown"`
+	}
+	var tagged Tagged
+	require.NoError(t, mapper.Copy(&tagged, Source{Nick: "-"}, placeholder))
+	assert.Equal(t, "unknown", tagged.Nick)
+
+	type Destination struct {
+		Name string
+	}
+	opt := mapper.ForMember("Name", mapper.MapFrom("Nick").OrFrom("Username").OrValue("anonymous"))
+	var dst Destination
+	require.NoError(t, mapper.Copy(&dst, Source{Nick: "-", Username: "ada"}, placeholder, opt))
+	assert.Equal(t, "ada", dst.Name)
+
+	dst = Destination{}
+	require.NoError(t, mapper.Copy(&dst, Source{Nick: "-", Username: "-"}, placeholder, opt))
+	assert.Equal(t, "anonymous", dst.Name)
+}
+
+func TestFieldContext(t *testing.T) {
+	type Address struct {
+		City string
+		Zip  string
+	}
+	type User struct {
+		Addresses []Address
+	}
+	type AddressDTO struct {
+		City string
+		Zip  int
+	}
+	type UserDTO struct {
+		Addresses []AddressDTO
+	}
+
+	var fields []mapper.FieldContext
+	var paths []string
+	err := mapper.Copy(&UserDTO{}, User{Addresses: []Address{{City: "Berlin", Zip: "10115"}, {City: "Paris", Zip: "x"}}},
+		mapper.WithContextConverter(reflect.TypeOf(""), func(ctx context.Context, v reflect.Value) (reflect.Value, error) {
+			if field, ok := mapper.FieldFromContext(ctx); ok {
+				paths = append(paths, field.Path)
+			}
+			return v, nil
+		}),
+		mapper.WithFieldConverter("Addresses.Zip", func(v reflect.Value) (reflect.Value, error) {
+			n, err := strconv.Atoi(v.String())
+			return reflect.ValueOf(n), err
+		}),
+		mapper.WithFieldErrorHandler(func(err error, field mapper.FieldContext) error {
+			fields = append(fields, field)
+			return nil
+		}))
+	require.NoError(t, err)
+
+	require.Len(t, fields, 1)
+	assert.Equal(t, "User.Addresses[1].Zip", fields[0].Path)
+	assert.Equal(t, "Zip", fields[0].SrcField)
+	assert.Equal(t, reflect.TypeOf(0), fields[0].DstType)
+	assert.Equal(t, []string{"User.Addresses[0].City", "User.Addresses[1].City"}, paths)
+}
+
+type tenantKey struct{}
+
+func TestConstructorCtx(t *testing.T) {
+	type LineDTO struct {
+		SKU string
+	}
+	type Line struct {
+		TenantID string
+		SKU      string
+	}
+	type OrderDTO struct {
+		Lines []LineDTO
+	}
+	type Order struct {
+		TenantID string
+		Lines    []Line
+	}
+
+	m := mapper.NewMapper(
+		mapper.WithConstructorCtx(reflect.TypeOf(Order{}), func(ctx context.Context) (any, error) {
+			return &Order{TenantID: ctx.Value(tenantKey{}).(string)}, nil
+		}),
+		mapper.WithConstructorCtx(reflect.TypeOf(Line{}), func(ctx context.Context) (any, error) {
+			return Line{TenantID: ctx.Value(tenantKey{}).(string)}, nil
+		}),
+	)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	var order *Order
+	require.NoError(t, m.MapContext(ctx, &order, OrderDTO{Lines: []LineDTO{{SKU: "a"}, {SKU: "b"}}}))
+	require.NotNil(t, order)
+	assert.Equal(t, "acme", order.TenantID)
+	assert.Equal(t, []Line{{TenantID: "acme", SKU: "a"}, {TenantID: "acme", SKU: "b"}}, order.Lines)
+
+	existing := Order{TenantID: "other"}
+	require.NoError(t, m.MapContext(ctx, &existing, OrderDTO{}))
+	assert.Equal(t, "other", existing.TenantID)
+}
+
+func TestBidirectional(t *testing.T) {
+	type Contact struct {
+		Mail string `dto:"email"`
+	}
+	type Entity struct {
+		FullName  string    `dto:"name"`
+		CreatedAt time.Time `dto:"created"`
+		Contact   Contact   `dto:"contact"`
+	}
+	type ContactDTO struct {
+		Email string
+	}
+	type DTO struct {
+		Name    string
+		Created string
+		Contact ContactDTO
+	}
+
+	timeType, stringType := reflect.TypeOf(time.Time{}), reflect.TypeOf("")
+	users := mapper.NewBidirectional[Entity, DTO](
+		mapper.WithTagName("dto"),
+		mapper.WithCaseSensitive(false),
+		mapper.WithAToB(mapper.WithCustomConverter(timeType, func(v reflect.Value) (reflect.Value, error) {
+			return reflect.ValueOf(v.Interface().(time.Time).Format(time.DateOnly)), nil
+		})),
+		mapper.WithBToA(mapper.WithCustomConverter(stringType, func(v reflect.Value) (reflect.Value, error) {
+			parsed, err := time.Parse(time.DateOnly, v.String())
+			if err != nil {
+				return reflect.Value{}, mapper.ErrSkipConversion
+			}
+			return reflect.ValueOf(parsed), nil
+		})),
+	)
+
+	entity := Entity{FullName: "Ada", CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Contact: Contact{Mail: "ada@example.com"}}
+	dto, err := users.AToB.Map(entity)
+	require.NoError(t, err)
+	assert.Equal(t, DTO{Name: "Ada", Created: "2024-05-01", Contact: ContactDTO{Email: "ada@example.com"}}, dto)
+
+	back, err := users.BToA.Map(dto)
+	require.NoError(t, err)
+	assert.Equal(t, entity, back)
+}
+
+func TestWithClock(t *testing.T) {
+	type Input struct {
+		Title     string
+		CreatedAt time.Time
+	}
+	type Record struct {
+		Title     string
+		CreatedAt time.Time  `mapper:",default=now"`
+		SeenAt    *time.Time `mapper:",default=now"`
+		UpdatedAt time.Time
+	}
+
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var m *mapper.Mapper
+	m = mapper.NewMapper(
+		mapper.WithClock(func() time.Time { return fixed }),
+		mapper.WithAfterMap(func(dst, src any) error {
+			dst.(*Record).UpdatedAt = m.Now()
+			return nil
+		}),
+	)
+
+	var rec Record
+	require.NoError(t, m.Map(&rec, Input{Title: "a"}))
+	assert.Equal(t, fixed, rec.CreatedAt)
+	require.NotNil(t, rec.SeenAt)
+	assert.Equal(t, fixed, *rec.SeenAt)
+	assert.Equal(t, fixed, rec.UpdatedAt)
+
+	created := fixed.Add(-time.Hour)
+	rec = Record{}
+	require.NoError(t, m.Map(&rec, Input{Title: "b", CreatedAt: created}))
+	assert.Equal(t, created, rec.CreatedAt)
+}
+
+func TestProjectionBuilder(t *testing.T) {
+	type User struct {
+		First string
+		Last  string
+		Email string
+	}
+	type UserDTO struct {
+		Email       string
+		DisplayName string
+		Initials    string
+	}
+
+	users, err := mapper.Builder[User, UserDTO]().
+		ForField("DisplayName", func(u User) (string, error) {
+			return u.First + " " + u.Last, nil
+		}).
+		ForField("Initials", func(u User) string {
+			return u.First[:1] + u.Last[:1]
+		}).
+		Build()
+	require.NoError(t, err)
+
+	dto, err := users.Map(User{First: "Ada", Last: "Lovelace", Email: "ada@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, UserDTO{Email: "ada@example.com", DisplayName: "Ada Lovelace", Initials: "AL"}, dto)
+
+	failing, err := mapper.Builder[User, UserDTO]().
+		ForField("DisplayName", func(u User) (string, error) {
+			return "", errors.New("no name")
+		}).
+		Build()
+	require.NoError(t, err)
+	_, err = failing.Map(User{Email: "x"})
+	require.Error(t, err)
+	var mapErr *mapper.MapError
+	require.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "DisplayName", mapErr.DstField)
+
+	_, err = mapper.Builder[User, UserDTO]().ForField("DisplayName", func(s string) string { return s }).Build()
+	assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+}
+
+func TestCollectionHelpers(t *testing.T) {
+	type Row struct {
+		ID   int
+		Name string
+	}
+	type DTO struct {
+		ID   int64
+		Name string
+	}
+
+	rows := make([]Row, 1000)
+	for i := range rows {
+		rows[i] = Row{ID: i, Name: strconv.Itoa(i)}
+	}
+
+	for _, workers := range []int{0, 4} {
+		dtos, err := mapper.MapSlice[Row, DTO](rows, mapper.WithParallelism(workers))
+		require.NoError(t, err)
+		require.Len(t, dtos, len(rows))
+		for i, dto := range dtos {
+			assert.Equal(t, DTO{ID: int64(i), Name: strconv.Itoa(i)}, dto)
+		}
+	}
+
+	nilSlice, err := mapper.MapSlice[Row, DTO](nil)
+	require.NoError(t, err)
+	assert.Nil(t, nilSlice)
+
+	byKey, err := mapper.MapMap[string, Row, DTO](map[string]Row{"a": {ID: 1, Name: "a"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]DTO{"a": {ID: 1, Name: "a"}}, byKey)
+
+	src := make(chan Row)
+	go func() {
+		defer close(src)
+		for _, row := range rows[:3] {
+			src <- row
+		}
+	}()
+	out, errs := mapper.MapChan[Row, DTO](context.Background(), src)
+	var streamed []DTO
+	for dto := range out {
+		streamed = append(streamed, dto)
+	}
+	require.NoError(t, <-errs)
+	assert.Equal(t, []DTO{{ID: 0, Name: "0"}, {ID: 1, Name: "1"}, {ID: 2, Name: "2"}}, streamed)
+
+	failing := mapper.New[Row, DTO](
+		mapper.WithParallelism(4),
+		mapper.WithFieldConverter("Name", func(v reflect.Value) (reflect.Value, error) {
+			if v.String() == "700" || v.String() == "300" {
+				return reflect.Value{}, errors.New("bad name")
+			}
+			return v, nil
+		}),
+	)
+	_, err = failing.MapSlice(rows)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "item 300:")
+}
+
+func TestNoUnsafe(t *testing.T) {
+	type Counters struct {
+		Name     string
+		Requests atomic.Int64 `mapper:",atomic"`
+	}
+	type CountersDTO struct {
+		Name     string
+		Requests int64
+	}
+
+	src := &Counters{Name: "api"}
+	src.Requests.Store(7)
+
+	var dst CountersDTO
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithNoUnsafe(true)))
+	assert.Equal(t, CountersDTO{Name: "api", Requests: 7}, dst)
+
+	err := mapper.Copy(&dst, src, mapper.WithNoUnsafe(true), mapper.WithAllowPrivateFields(true))
+	assert.ErrorIs(t, err, mapper.ErrUnsafeDisabled)
+}
+
+func TestVisibility(t *testing.T) {
+	type Account struct {
+		Name    string
+		Email   string
+		Balance int64 `mapper:",roles=admin"`
+	}
+	type AccountDTO struct {
+		Name    string
+		Email   string `mapper:",roles=admin;owner"`
+		Balance int64
+	}
+
+	account := Account{Name: "ada", Email: "ada@example.com", Balance: 42}
+
+	var public AccountDTO
+	require.NoError(t, mapper.Copy(&public, account))
+	assert.Equal(t, AccountDTO{Name: "ada"}, public)
+
+	var owner AccountDTO
+	require.NoError(t, mapper.Copy(&owner, account, mapper.WithVisibility("owner")))
+	assert.Equal(t, AccountDTO{Name: "ada", Email: "ada@example.com"}, owner)
+
+	m := mapper.NewMapper()
+	var admin AccountDTO
+	require.NoError(t, m.MapContext(mapper.ContextWithVisibility(context.Background(), "admin"), &admin, account))
+	assert.Equal(t, AccountDTO{Name: "ada", Email: "ada@example.com", Balance: 42}, admin)
+
+	var asMap map[string]interface{}
+	require.NoError(t, mapper.Copy(&asMap, account, mapper.WithVisibility("owner")))
+	assert.Equal(t, map[string]interface{}{"Name": "ada", "Email": "ada@example.com"}, asMap)
+}
+
+func TestTranslator(t *testing.T) {
+	type Status int
+	type Order struct {
+		ID     int
+		Status Status `mapper:",i18n=order.status"`
+	}
+	type OrderDTO struct {
+		ID     int
+		Status string
+	}
+
+	catalog := map[string]string{
+		"en/order.status/1": "Shipped",
+		"de/order.status/1": "Versandt",
+	}
+	translate := func(locale, key string, value interface{}) (string, error) {
+		label, ok := catalog[fmt.Sprintf("%s/%s/%v", locale, key, value)]
+		if !ok {
+			return "", fmt.Errorf("no translation for %v", value)
+		}
+		return label, nil
+	}
+
+	m := mapper.NewMapper(mapper.WithTranslator(translate, "en"))
+
+	var dto OrderDTO
+	require.NoError(t, m.Map(&dto, Order{ID: 7, Status: 1}))
+	assert.Equal(t, OrderDTO{ID: 7, Status: "Shipped"}, dto)
+
+	require.NoError(t, m.MapContext(mapper.ContextWithLocale(context.Background(), "de"), &dto, Order{ID: 7, Status: 1}))
+	assert.Equal(t, "Versandt", dto.Status)
+
+	err := m.Map(&dto, Order{ID: 8, Status: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no translation for 2")
+}
+
+func TestMapStreamAndSeq(t *testing.T) {
+	type Event struct{ ID int }
+	type EventDTO struct{ ID int64 }
+
+	in := make(chan Event)
+	go func() {
+		defer close(in)
+		for i := 1; i <= 3; i++ {
+			in <- Event{ID: i}
+		}
+	}()
+	out, errs := mapper.MapStream[Event, EventDTO](mapper.NewMapper(), context.Background(), in)
+	var streamed []EventDTO
+	for dto := range out {
+		streamed = append(streamed, dto)
+	}
+	require.NoError(t, <-errs)
+	assert.Equal(t, []EventDTO{{1}, {2}, {3}}, streamed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out, errs = mapper.MapStream[Event, EventDTO](mapper.NewMapper(), ctx, make(chan Event))
+	for range out {
+	}
+	assert.ErrorIs(t, <-errs, context.Canceled)
+
+	seq := func(yield func(Event) bool) {
+		for i := 1; ; i++ {
+			if !yield(Event{ID: i}) {
+				return
+			}
+		}
+	}
+	var pulled []EventDTO
+	for dto, err := range mapper.MapSeq[Event, EventDTO](seq) {
+		require.NoError(t, err)
+		pulled = append(pulled, dto)
+		if len(pulled) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []EventDTO{{1}, {2}}, pulled)
+}
+
+type validationError struct{ field string }
+
+func (e *validationError) Error() string { return e.field + " is invalid" }
+
+func TestErrorValues(t *testing.T) {
+	type Result struct {
+		ID      int
+		Err     error
+		Cause   error
+		Details *validationError
+	}
+	type ResultDTO struct {
+		ID      int
+		Err     string
+		Cause   error
+		Details string
+	}
+
+	cause := &validationError{field: "email"}
+	src := Result{ID: 1, Err: errors.New("boom"), Cause: cause, Details: cause}
+
+	var dto ResultDTO
+	require.NoError(t, mapper.Copy(&dto, src))
+	assert.Equal(t, "boom", dto.Err)
+	assert.Equal(t, "email is invalid", dto.Details)
+	assert.Same(t, cause, dto.Cause)
+
+	var skipped ResultDTO
+	require.NoError(t, mapper.Copy(&skipped, src, mapper.WithErrorValuePolicy(mapper.ErrorValueSkip)))
+	assert.Equal(t, ResultDTO{ID: 1}, skipped)
+
+	var empty ResultDTO
+	require.NoError(t, mapper.Copy(&empty, Result{ID: 2}))
+	assert.Equal(t, ResultDTO{ID: 2}, empty)
+}
+
+func TestNullableSupport(t *testing.T) {
+	type UserRow struct {
+		Name    sql.NullString
+		Age     sql.NullInt64
+		Score   sql.Null[float64]
+		Created sql.NullTime
+	}
+	type UserDTO struct {
+		Name    *string
+		Age     int
+		Score   *float64
+		Created time.Time
+	}
+
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	row := UserRow{
+		Name:    sql.NullString{String: "ada", Valid: true},
+		Age:     sql.NullInt64{Int64: 36, Valid: true},
+		Created: sql.NullTime{Time: created, Valid: true},
+	}
+
+	var dto UserDTO
+	require.NoError(t, mapper.Copy(&dto, row, mapper.WithNullableSupport(true)))
+	require.NotNil(t, dto.Name)
+	assert.Equal(t, "ada", *dto.Name)
+	assert.Equal(t, 36, dto.Age)
+	assert.Nil(t, dto.Score)
+	assert.Equal(t, created, dto.Created)
+
+	var back UserRow
+	require.NoError(t, mapper.Copy(&back, dto, mapper.WithNullableSupport(true)))
+	assert.Equal(t, row, back)
+}
+
+func TestChannelSnapshot(t *testing.T) {
+	type Job struct{ ID int }
+	type JobDTO struct{ ID int64 }
+	type Worker struct{ Queue chan Job }
+	type WorkerDebug struct{ Queue []JobDTO }
+
+	worker := Worker{Queue: make(chan Job, 10)}
+	for i := 1; i <= 5; i++ {
+		worker.Queue <- Job{ID: i}
+	}
+
+	var untouched WorkerDebug
+	require.NoError(t, mapper.Copy(&untouched, worker))
+	assert.Nil(t, untouched.Queue)
+	assert.Equal(t, 5, len(worker.Queue))
+
+	var debug WorkerDebug
+	require.NoError(t, mapper.Copy(&debug, worker, mapper.WithChannelSnapshot(3)))
+	assert.Equal(t, []JobDTO{{1}, {2}, {3}}, debug.Queue)
+	assert.Equal(t, 2, len(worker.Queue))
+
+	empty := Worker{Queue: make(chan Job)}
+	require.NoError(t, mapper.Copy(&debug, empty, mapper.WithChannelSnapshot(3)))
+	assert.Empty(t, debug.Queue)
+}
+
+func TestStringInterning(t *testing.T) {
+	type Row struct {
+		Status  string
+		Comment string
+	}
+
+	rows := make([]Row, 100)
+	for i := range rows {
+		// Build distinct instances of the same status
+		rows[i] = Row{Status: strings.Repeat("shipped", 1+i%2)[:7], Comment: strings.Repeat("long comment ", 1+i%2)[:12]}
+	}
+	require.NotSame(t, unsafe.StringData(rows[0].Status), unsafe.StringData(rows[1].Status))
+
+	out, err := mapper.MapSlice[Row, Row](rows, mapper.WithStringInterning(8))
+	require.NoError(t, err)
+	for _, row := range out {
+		assert.Equal(t, "shipped", row.Status)
+		assert.Same(t, unsafe.StringData(out[0].Status), unsafe.StringData(row.Status))
+	}
+	assert.NotSame(t, unsafe.StringData(out[0].Comment), unsafe.StringData(out[1].Comment))
+}
+
+type textLevel int
+
+func (l textLevel) MarshalText() ([]byte, error) {
+	return []byte([]string{"debug", "info", "warn"}[l]), nil
+}
+
+func (l *textLevel) UnmarshalText(text []byte) error {
+	for i, name := range []string{"debug", "info", "warn"} {
+		if name == string(text) {
+			*l = textLevel(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown level %q", text)
+}
+
+func TestTextMarshaling(t *testing.T) {
+	type Host struct {
+		Addr  net.IP
+		Level textLevel
+		Peer  *textLevel
+	}
+	type HostDTO struct {
+		Addr  string
+		Level string
+		Peer  string
+	}
+
+	peer := textLevel(2)
+	host := Host{Addr: net.ParseIP("10.0.0.1"), Level: 1, Peer: &peer}
+
+	var dto HostDTO
+	require.NoError(t, mapper.Copy(&dto, host, mapper.WithTextMarshaling(true)))
+	assert.Equal(t, HostDTO{Addr: "10.0.0.1", Level: "info", Peer: "warn"}, dto)
+
+	var back Host
+	require.NoError(t, mapper.Copy(&back, dto, mapper.WithTextMarshaling(true)))
+	assert.True(t, host.Addr.Equal(back.Addr))
+	assert.Equal(t, textLevel(1), back.Level)
+	require.NotNil(t, back.Peer)
+	assert.Equal(t, peer, *back.Peer)
+
+	err := mapper.Copy(&back, HostDTO{Level: "loud"}, mapper.WithTextMarshaling(true))
+	assert.ErrorContains(t, err, `unknown level "loud"`)
+}
+
+type GenAddress struct {
+	City string
+}
+
+type GenUser struct {
+	ID      int32
+	Name    string
+	Tags    []string
+	Address *GenAddress
+	Score   float64
+}
+
+type GenAddressDTO struct {
+	City string
+}
+
+type GenUserDTO struct {
+	ID      int64
+	Name    string
+	Tags    []string
+	Address *GenAddressDTO
+	Score   string
+}
+
+func TestGeneratePlanSource(t *testing.T) {
+	m := mapper.NewMapper(mapper.WithCustomConverter(reflect.TypeOf(float64(0)), func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(strconv.FormatFloat(v.Float(), 'f', 2, 64)), nil
+	}))
+
+	src, err := m.GeneratePlanSource(reflect.TypeOf(GenUser{}), reflect.TypeOf(&GenUserDTO{}))
+	require.NoError(t, err)
+
+	assert.Contains(t, src, "func MapGenUserToGenUserDTO(src gomap_test.GenUser) (gomap_test.GenUserDTO, error) {")
+	assert.Contains(t, src, "dst.ID = int64(src.ID)")
+	assert.Contains(t, src, "dst.Name = src.Name")
+	assert.Contains(t, src, "copy(dst.Tags, src.Tags)")
+	assert.Contains(t, src, "MapGenAddressToGenAddressDTO((*src.Address))")
+	assert.Contains(t, src, "func MapGenAddressToGenAddressDTO(src gomap_test.GenAddress) (gomap_test.GenAddressDTO, error) {")
+	assert.Contains(t, src, "// Score: custom conversion runs at runtime only")
+
+	_, err = m.GeneratePlanSource(reflect.TypeOf(0), reflect.TypeOf(GenUserDTO{}))
+	assert.ErrorIs(t, err, mapper.ErrUnsupportedType)
+}
+
+// TestGeneratedPlanSourceMatchesMap checks generated source against
+// shipping_gen_test.go, and runs that file's functions against Map.
+// Regenerate it with GOMAP_UPDATE_GENERATED=1 go test ./test.
+func TestGeneratedPlanSourceMatchesMap(t *testing.T) {
+	m := mapper.NewMapper()
+	src, err := m.GeneratePlanSource(reflect.TypeOf(shipping.Order{}), reflect.TypeOf(shipping.OrderRecord{}))
+	require.NoError(t, err)
+
+	generated := "// Code generated by TestGeneratedPlanSourceMatchesMap. DO NOT EDIT.\n\n" +
+		"package gomap_test\n\nimport \"github.com/fbarikzehi/gomap/test/internal/shipping\"\n\n" + strings.TrimSuffix(src, "\n")
+	if os.Getenv("GOMAP_UPDATE_GENERATED") != "" {
+		require.NoError(t, os.WriteFile("shipping_gen_test.go", []byte(generated), 0o644))
+	}
+	checkedIn, err := os.ReadFile("shipping_gen_test.go")
+	require.NoError(t, err)
+	require.Equal(t, generated, string(checkedIn), "regenerate with GOMAP_UPDATE_GENERATED=1")
+
+	order := shipping.Order{
+		ID:       7,
+		Customer: &shipping.Customer{Name: "Ada", Tags: []string{"vip"}},
+		Items:    []*shipping.Item{{SKU: "A-1", Qty: 2}, nil},
+		Grid:     [][]int{{1, 2}, nil},
+		Labels:   map[string][]string{"gift": {"wrap"}},
+		Ship:     shipping.Address{City: "London", Lines: []string{"1 Main St"}},
+	}
+	var mapped shipping.OrderRecord
+	require.NoError(t, m.Map(&mapped, order))
+	static, err := MapOrderToOrderRecord(order)
+	require.NoError(t, err)
+	assert.Equal(t, mapped, static)
+
+	// Like Map, the generated code shares no storage with the source
+	order.Customer.Name = "Grace"
+	order.Customer.Tags[0] = "new"
+	order.Items[0].Qty = 5
+	order.Grid[0][0] = 9
+	order.Labels["gift"][0] = "card"
+	order.Ship.Lines[0] = "2 High St"
+	assert.Equal(t, mapped, static)
+
+	shared, err := mapper.NewMapper(mapper.WithDeepCopy(false)).
+		GeneratePlanSource(reflect.TypeOf(shipping.Order{}), reflect.TypeOf(shipping.OrderRecord{}))
+	require.NoError(t, err)
+	assert.Contains(t, shared, "dst.Customer = src.Customer // shared with the source")
+}
+
+func TestJSONBridge(t *testing.T) {
+	type Attributes struct {
+		Color string `json:"color"`
+		Size  int    `json:"size"`
+	}
+	type ProductRow struct {
+		ID         int
+		Attributes json.RawMessage
+		Extra      []byte
+	}
+	type ProductDTO struct {
+		ID         int
+		Attributes *Attributes
+		Extra      map[string]interface{}
+	}
+
+	row := ProductRow{ID: 1, Attributes: json.RawMessage(`{"color":"red","size":3}`), Extra: []byte(`{"tag":"new"}`)}
+
+	var dto ProductDTO
+	require.NoError(t, mapper.Copy(&dto, row, mapper.WithJSONBridge(true)))
+	assert.Equal(t, &Attributes{Color: "red", Size: 3}, dto.Attributes)
+	assert.Equal(t, map[string]interface{}{"tag": "new"}, dto.Extra)
+
+	var back ProductRow
+	require.NoError(t, mapper.Copy(&back, dto, mapper.WithJSONBridge(true)))
+	assert.JSONEq(t, string(row.Attributes), string(back.Attributes))
+	assert.JSONEq(t, string(row.Extra), string(back.Extra))
+
+	err := mapper.Copy(&dto, ProductRow{Attributes: json.RawMessage(`{`)}, mapper.WithJSONBridge(true))
+	assert.Error(t, err)
+}
+
+type orderStatus int
+
+func TestEnumMapping(t *testing.T) {
+	type Order struct {
+		ID     int
+		Status orderStatus
+	}
+	type OrderDTO struct {
+		ID     int
+		Status string
+	}
+
+	statuses := mapper.WithEnumMapping(reflect.TypeOf(orderStatus(0)), reflect.TypeOf(""),
+		map[any]any{0: "pending", 1: "processing", 2: "completed"})
+
+	var dto OrderDTO
+	require.NoError(t, mapper.Copy(&dto, Order{ID: 7, Status: 2}, statuses))
+	assert.Equal(t, OrderDTO{ID: 7, Status: "completed"}, dto)
+
+	var back Order
+	require.NoError(t, mapper.Copy(&back, OrderDTO{ID: 7, Status: "processing"}, statuses))
+	assert.Equal(t, Order{ID: 7, Status: 1}, back)
+
+	err := mapper.Copy(&dto, Order{Status: 9}, statuses)
+	assert.ErrorIs(t, err, mapper.ErrUnknownEnumValue)
+
+	dto = OrderDTO{Status: "stale"}
+	require.NoError(t, mapper.Copy(&dto, Order{Status: 9}, statuses, mapper.WithEnumUnknownPolicy(mapper.EnumUnknownZero)))
+	assert.Equal(t, "", dto.Status)
+
+	type Row struct{ Status orderStatus }
+	type RowDTO struct{ Status int64 }
+	codes := mapper.WithEnumMapping(reflect.TypeOf(orderStatus(0)), reflect.TypeOf(int64(0)), map[any]any{1: 100})
+	var row RowDTO
+	require.NoError(t, mapper.Copy(&row, Row{Status: 5}, codes, mapper.WithEnumUnknownPolicy(mapper.EnumUnknownPassthrough)))
+	assert.Equal(t, int64(5), row.Status)
+	require.NoError(t, mapper.Copy(&row, Row{Status: 1}, codes))
+	assert.Equal(t, int64(100), row.Status)
+}
+
+func TestFreeze(t *testing.T) {
+	type Source struct{ FullName string }
+	type Destination struct {
+		Name  string
+		Label string
+	}
+
+	m := mapper.NewMapper()
+	m.Field("FullName").To("Name")
+	require.NoError(t, m.Register(mapper.WithFieldMapping(map[string]string{"FullName": "Label"})))
+	assert.False(t, m.Frozen())
+
+	var dst Destination
+	require.NoError(t, m.Map(&dst, Source{FullName: "Ada"}))
+	assert.Equal(t, "Ada", dst.Label)
+
+	m.Freeze()
+	assert.True(t, m.Frozen())
+	assert.ErrorIs(t, m.Register(mapper.WithCaseSensitive(false)), mapper.ErrFrozen)
+	assert.PanicsWithValue(t, mapper.ErrFrozen, func() { m.Field("FullName").To("Name") })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var dst Destination
+			assert.NoError(t, m.Map(&dst, Source{FullName: "Ada"}))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestErrorCategories(t *testing.T) {
+	type Source struct {
+		Count string
+		Kind  int
+		Note  string
+	}
+	type Destination struct {
+		Count int
+		Kind  []int
+		Note  string
+	}
+
+	errNote := errors.New("bad note")
+	err := mapper.Copy(&Destination{}, Source{Count: "x", Kind: 1, Note: "n"},
+		mapper.WithStringConversion(true),
+		mapper.WithFieldConverter("Note", func(v reflect.Value) (reflect.Value, error) {
+			return reflect.Value{}, errNote
+		}),
+	)
+	require.Error(t, err)
+
+	var conv *mapper.ConversionError
+	require.True(t, errors.As(err, &conv))
+	assert.Equal(t, "Source.Count", conv.Path())
+
+	var converter *mapper.ConverterError
+	require.True(t, errors.As(err, &converter))
+	assert.Equal(t, "Source.Note", converter.Path())
+	assert.ErrorIs(t, converter, errNote)
+
+	var mapErr *mapper.MapError
+	require.True(t, errors.As(converter, &mapErr))
+	assert.Equal(t, "Note", mapErr.DstField)
+
+	var categorized mapper.CategorizedError = conv
+	assert.NotEmpty(t, categorized.Path())
+
+	type Node struct {
+		Next *Node
+	}
+	type Holder struct{ Root Node }
+	deep := Holder{Root: Node{Next: &Node{Next: &Node{Next: &Node{}}}}}
+	err = mapper.Copy(&Holder{}, deep, mapper.WithMaxDepth(2))
+	var limit *mapper.LimitError
+	require.True(t, errors.As(err, &limit))
+	assert.ErrorIs(t, limit, mapper.ErrMaxDepthExceeded)
+
+	type Grid struct{ Cells [][]int }
+	type Flat struct{ Cells []int }
+	err = mapper.Copy(&Flat{}, Grid{Cells: [][]int{{1}}})
+	var match *mapper.MatchError
+	require.True(t, errors.As(err, &match))
+	assert.ErrorIs(t, match, mapper.ErrTypeMismatch)
+}
+
+type validatedItem struct {
+	SKU string
+	Qty int
+}
+
+func (i *validatedItem) Validate() error {
+	if i.Qty <= 0 {
+		return fmt.Errorf("quantity of %s must be positive", i.SKU)
+	}
+	return nil
+}
+
+type validatedOrder struct {
+	ID    string
+	Items []validatedItem
+}
+
+func (o *validatedOrder) Validate() error {
+	if o.ID == "" {
+		return errors.New("id is required")
+	}
+	return nil
+}
+
+func TestValidation(t *testing.T) {
+	type Item struct {
+		SKU string
+		Qty int
+	}
+	type Order struct {
+		ID    string
+		Items []Item
+	}
+
+	var dst validatedOrder
+	require.NoError(t, mapper.Copy(&dst, Order{ID: "o1", Items: []Item{{"a", 1}}}))
+
+	errRoot := errors.New("root rejected")
+	err := mapper.Copy(&dst, Order{Items: []Item{{"a", 1}, {"b", 0}}},
+		mapper.WithValidator(func(dst any) error {
+			assert.IsType(t, &validatedOrder{}, dst)
+			return errRoot
+		}))
+	var errs *mapper.MappingErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs.Errors(), 3)
+
+	var item, order, root *mapper.ValidationError
+	require.True(t, errors.As(errs.Errors()[0], &item))
+	assert.Equal(t, "Order.Items[1]", item.Path())
+	assert.EqualError(t, item.Unwrap().(*mapper.MapError).Err, "quantity of b must be positive")
+	require.True(t, errors.As(errs.Errors()[1], &order))
+	assert.Equal(t, "Order", order.Path())
+	require.True(t, errors.As(errs.Errors()[2], &root))
+	assert.ErrorIs(t, root, errRoot)
+}
+
+func TestTagOptions(t *testing.T) {
+	type Source struct {
+		Name  string
+		Count int
+		Note  string
+		Tags  []string
+	}
+	type Destination struct {
+		Name    string        `mapper:"Name,required"`
+		Count   int           `mapper:",default=10"`
+		Note    string        `mapper:",omitempty"`
+		Tags    []string      `mapper:",omitempty"`
+		Title   string        `mapper:",default='Hello, world'"`
+		Timeout time.Duration `mapper:",default=30s"`
+		Ratio   *float64      `mapper:",default=0.5"`
+	}
+
+	dst := Destination{Note: "keep", Tags: []string{"old"}}
+	require.NoError(t, mapper.Copy(&dst, Source{Name: "a"}))
+	assert.Equal(t, "a", dst.Name)
+	assert.Equal(t, 10, dst.Count)
+	assert.Equal(t, "keep", dst.Note)
+	assert.Equal(t, []string{"old"}, dst.Tags)
+	assert.Equal(t, "Hello, world", dst.Title)
+	assert.Equal(t, 30*time.Second, dst.Timeout)
+	require.NotNil(t, dst.Ratio)
+	assert.Equal(t, 0.5, *dst.Ratio)
+
+	dst = Destination{}
+	require.NoError(t, mapper.Copy(&dst, Source{Name: "a", Count: 3, Note: "n"}))
+	assert.Equal(t, 3, dst.Count)
+	assert.Equal(t, "n", dst.Note)
+
+	err := mapper.Copy(&Destination{}, Source{Count: 3})
+	assert.ErrorIs(t, err, mapper.ErrRequiredField)
+	var conv *mapper.ConversionError
+	require.True(t, errors.As(err, &conv))
+	assert.Equal(t, "Source.Name", conv.Path())
+
+	type Partial struct {
+		Count int
+	}
+	err = mapper.Copy(&Destination{}, Partial{Count: 1})
+	assert.ErrorIs(t, err, mapper.ErrRequiredField)
+	assert.ErrorContains(t, err, "no source for Name")
+
+	type BadDefault struct {
+		Count int `mapper:",default=ten"`
+	}
+	assert.ErrorIs(t, mapper.Copy(&BadDefault{}, Partial{}), mapper.ErrTypeMismatch)
+}
+
+type permission uint8
+
+const (
+	permRead permission = 1 << iota
+	permWrite
+	permDelete
+)
+
+func TestFlags(t *testing.T) {
+	type User struct {
+		Permissions permission
+	}
+	type UserDTO struct {
+		Permissions []string
+	}
+	flags := mapper.WithFlags(reflect.TypeOf(permission(0)), map[string]any{
+		"read": permRead, "write": permWrite, "delete": permDelete, "all": 7,
+	})
+
+	var dto UserDTO
+	require.NoError(t, mapper.Copy(&dto, User{Permissions: permRead | permDelete}, flags))
+	assert.Equal(t, []string{"read", "delete"}, dto.Permissions)
+
+	require.NoError(t, mapper.Copy(&dto, User{Permissions: permRead | permWrite | permDelete}, flags))
+	assert.Equal(t, []string{"read", "write", "delete", "all"}, dto.Permissions)
+
+	var user User
+	require.NoError(t, mapper.Copy(&user, UserDTO{Permissions: []string{"write", "delete"}}, flags))
+	assert.Equal(t, permWrite|permDelete, user.Permissions)
+
+	err := mapper.Copy(&user, UserDTO{Permissions: []string{"write", "admin"}}, flags)
+	assert.ErrorIs(t, err, mapper.ErrUnknownEnumValue)
+	assert.ErrorIs(t, mapper.Copy(&dto, User{Permissions: 8}, flags), mapper.ErrUnknownEnumValue)
+
+	require.NoError(t, mapper.Copy(&user, UserDTO{Permissions: []string{"read", "admin"}}, flags,
+		mapper.WithEnumUnknownPolicy(mapper.EnumUnknownZero)))
+	assert.Equal(t, permRead, user.Permissions)
+}
+
+func TestNamedConverter(t *testing.T) {
+	type Product struct {
+		Price int64
+		Cost  int64
+	}
+	type ProductDTO struct {
+		Price float64 `mapper:",converter=centsToDollars"`
+		Cost  int64
+	}
+	centsToDollars := mapper.WithNamedConverter("centsToDollars", func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(float64(v.Int()) / 100), nil
+	})
+
+	var dto ProductDTO
+	require.NoError(t, mapper.Copy(&dto, Product{Price: 1999, Cost: 1200}, centsToDollars))
+	assert.Equal(t, ProductDTO{Price: 19.99, Cost: 1200}, dto)
+
+	m := mapper.NewMapper(centsToDollars)
+	plan, err := m.Plan(reflect.TypeOf(Product{}), reflect.TypeOf(ProductDTO{}))
+	require.NoError(t, err)
+	assert.Equal(t, "named", plan.Fields[0].Converter)
+	assert.Equal(t, "", plan.Fields[1].Converter)
+
+	err = mapper.Copy(&dto, Product{Price: 1})
+	assert.ErrorIs(t, err, mapper.ErrUnsupportedType)
+	assert.ErrorContains(t, err, `converter "centsToDollars" is not registered`)
+}
+
+func TestDeepCopyUntilDepth(t *testing.T) {
+	type Product struct {
+		Name string
+	}
+	type Item struct {
+		Product *Product
+		Tags    []string
+	}
+	type Order struct {
+		Items []Item
+		Meta  map[string]string
+	}
+	src := Order{
+		Items: []Item{{Product: &Product{Name: "p"}, Tags: []string{"a"}}},
+		Meta:  map[string]string{"k": "v"},
+	}
+
+	var shared Order
+	require.NoError(t, mapper.Copy(&shared, src, mapper.WithDeepCopyUntilDepth(1)))
+	assert.Equal(t, src, shared)
+	assert.Same(t, &src.Items[0], &shared.Items[0])
+	src.Meta["k"] = "changed"
+	assert.Equal(t, "changed", shared.Meta["k"])
+
+	var partial Order
+	require.NoError(t, mapper.Copy(&partial, src, mapper.WithDeepCopyUntilDepth(3)))
+	assert.NotSame(t, &src.Items[0], &partial.Items[0])
+	assert.Same(t, src.Items[0].Product, partial.Items[0].Product)
+	src.Meta["k"] = "again"
+	assert.Equal(t, "changed", partial.Meta["k"])
+
+	var deep Order
+	require.NoError(t, mapper.Copy(&deep, src))
+	assert.NotSame(t, src.Items[0].Product, deep.Items[0].Product)
+}
+
+func TestConditions(t *testing.T) {
+	type Item struct {
+		Price int
+	}
+	type User struct {
+		Email       string
+		EmailPublic bool
+		SSN         string
+		Items       []Item
+	}
+	type UserDTO struct {
+		Email string
+		SSN   string
+		Items []Item
+	}
+	opts := []mapper.Option{
+		mapper.WithCondition("Email", func(src any) bool {
+			return src.(User).EmailPublic
+		}),
+		mapper.WithFieldCondition("User.Items.Price", func(v reflect.Value) bool {
+			return v.Int() >= 0
+		}),
+		mapper.WithFieldCondition("SSN", func(v reflect.Value) bool { return true }),
+		mapper.WithFieldCondition("SSN", func(v reflect.Value) bool { return false }),
+	}
+
+	var dto UserDTO
+	src := User{Email: "a@b.c", SSN: "123", Items: []Item{{Price: 5}, {Price: -1}}}
+	require.NoError(t, mapper.Copy(&dto, src, opts...))
+	assert.Equal(t, UserDTO{Items: []Item{{Price: 5}, {Price: 0}}}, dto)
+
+	src.EmailPublic = true
+	require.NoError(t, mapper.Copy(&dto, src, opts...))
+	assert.Equal(t, "a@b.c", dto.Email)
+	assert.Empty(t, dto.SSN)
+}
+
+func TestPruneEmpty(t *testing.T) {
+	type Geo struct {
+		Lat, Lng float64
+	}
+	type Address struct {
+		City string
+		Geo  *Geo
+	}
+	type Profile struct {
+		Home  *Address
+		Work  *Address
+		Tags  []string
+		Attrs map[string]string
+	}
+	src := Profile{
+		Home:  &Address{City: "Oslo", Geo: &Geo{}},
+		Work:  &Address{Geo: &Geo{}},
+		Tags:  []string{},
+		Attrs: map[string]string{},
+	}
+
+	var dst Profile
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithPruneEmpty(true)))
+	require.NotNil(t, dst.Home)
+	assert.Equal(t, "Oslo", dst.Home.City)
+	assert.Nil(t, dst.Home.Geo)
+	assert.Nil(t, dst.Work)
+	assert.NotNil(t, dst.Tags)
+	assert.NotNil(t, dst.Attrs)
+
+	dst = Profile{}
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithPruneEmpty(true),
+		mapper.WithEmptyCollectionPolicy(mapper.EmptyCollectionNil)))
+	assert.Nil(t, dst.Tags)
+	assert.Nil(t, dst.Attrs)
+
+	dst = Profile{}
+	require.NoError(t, mapper.Copy(&dst, src))
+	assert.NotNil(t, dst.Work)
+}
+
+func TestRegistry(t *testing.T) {
+	type User struct {
+		Name  string
+		Email string `mapper:",roles=admin"`
+	}
+
+	reg := mapper.NewRegistry(mapper.WithCaseSensitive(false))
+	reg.Register("api")
+	reg.Register("internal", mapper.WithVisibility("admin"))
+	assert.Equal(t, []string{"api", "internal"}, reg.Profiles())
+
+	src := User{Name: "Ada", Email: "ada@example.com"}
+	var api, internal map[string]interface{}
+	require.NoError(t, reg.Map("api", &api, src))
+	require.NoError(t, reg.Map("internal", &internal, src))
+	assert.NotContains(t, api, "Email")
+	assert.Equal(t, "ada@example.com", internal["Email"])
+
+	m, ok := reg.Mapper("api")
+	require.True(t, ok)
+	var dst User
+	require.NoError(t, m.Map(&dst, map[string]interface{}{"NAME": "Alan"}))
+	assert.Equal(t, "Alan", dst.Name)
+
+	assert.ErrorIs(t, reg.Map("missing", &dst, src), mapper.ErrUnknownProfile)
+
+	var zero mapper.Registry
+	zero.Register("p")
+	assert.NoError(t, zero.Map("p", &dst, src))
+}
+
+func TestJSONNumber(t *testing.T) {
+	type Payment struct {
+		Amount   float64
+		Fee      float32
+		Count    int64
+		Big      *big.Int
+		Exact    *big.Float
+		Discount *float64
+	}
+	type PaymentDTO struct {
+		Amount   json.Number
+		Fee      json.Number
+		Count    json.Number
+		Big      json.Number
+		Exact    string `mapper:",numeric"`
+		Discount string `mapper:",numeric"`
+	}
+	discount := 0.3
+	src := Payment{
+		Amount:   19.99,
+		Fee:      0.1,
+		Count:    -42,
+		Big:      new(big.Int).Lsh(big.NewInt(1), 70),
+		Exact:    big.NewFloat(12.5),
+		Discount: &discount,
+	}
+
+	var dto PaymentDTO
+	require.NoError(t, mapper.Copy(&dto, src))
+	assert.Equal(t, PaymentDTO{
+		Amount:   "19.99",
+		Fee:      "0.1",
+		Count:    "-42",
+		Big:      "1180591620717411303424",
+		Exact:    "12.5",
+		Discount: "0.3",
+	}, dto)
+
+	type Totals struct {
+		Amount float64
+		Count  int64
+	}
+	var totals Totals
+	require.NoError(t, mapper.Copy(&totals, PaymentDTO{Amount: "19.99", Count: "7"}))
+	assert.Equal(t, Totals{Amount: 19.99, Count: 7}, totals)
+	assert.ErrorIs(t, mapper.Copy(&totals, PaymentDTO{Count: "7.5"}), mapper.ErrTypeMismatch)
+
+	assert.ErrorIs(t, mapper.Copy(&dto, Payment{Amount: math.Inf(1)}), mapper.ErrTypeMismatch)
+}
+
+func TestTee(t *testing.T) {
+	type User struct {
+		ID   int
+		Name string
+	}
+	type UserV1 struct {
+		ID       int
+		FullName string
+	}
+	type UserV2 struct {
+		Name  string
+		Email string `mapper:",required"`
+	}
+
+	var v1 UserV1
+	var v2 UserV2
+	require.NoError(t, mapper.NewMapper(
+		mapper.WithFieldMapping(map[string]string{"Name": "FullName"}),
+	).Tee(User{ID: 1, Name: "Ada"}, &v1))
+	assert.Equal(t, UserV1{ID: 1, FullName: "Ada"}, v1)
+
+	err := mapper.Tee(User{ID: 2, Name: "Alan"}, &v1, &v2, UserV1{})
+	var errs *mapper.MappingErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs.Errors(), 2)
+	assert.ErrorContains(t, errs.Errors()[0], "destination 1:")
+	assert.ErrorContains(t, errs.Errors()[1], "destination 2:")
+	assert.ErrorIs(t, err, mapper.ErrInvalidDestination)
+	assert.Equal(t, 2, v1.ID)
+	assert.Equal(t, "Alan", v2.Name)
+}
+
+func TestTeeConsistentSource(t *testing.T) {
+	type tier string
+	type Account struct {
+		Tier    tier
+		Balance int
+	}
+	type AccountV1 struct {
+		Tier    string
+		Balance int
+	}
+	type AccountV2 struct {
+		Tier    string
+		Balance int64
+	}
+
+	src := &Account{Tier: "gold", Balance: 100}
+	m := mapper.NewMapper(mapper.WithCustomConverter(reflect.TypeOf(tier("")), func(v reflect.Value) (reflect.Value, error) {
+		// Simulates a concurrent update landing between destinations
+		src.Balance += 50
+		return reflect.ValueOf(strings.ToUpper(v.String())), nil
+	}))
+
+	var v1 AccountV1
+	var v2 AccountV2
+	require.NoError(t, m.Tee(src, &v1, &v2))
+	assert.Equal(t, AccountV1{Tier: "GOLD", Balance: 100}, v1)
+	assert.Equal(t, AccountV2{Tier: "GOLD", Balance: 100}, v2)
+	assert.Equal(t, 200, src.Balance)
+
+	assert.ErrorIs(t, m.Tee(nil, &v1), mapper.ErrNilPointer)
+}
+
+func TestSetDefault(t *testing.T) {
+	type account struct {
+		ID     int
+		Secret string
+	}
+
+	mapper.SetDefault(mapper.WithIgnoreFields("Secret"))
+	defer mapper.SetDefault()
+	assert.True(t, mapper.Default().Frozen())
+
+	var dst account
+	require.NoError(t, mapper.Copy(&dst, account{ID: 1, Secret: "s3cr3t"}))
+	assert.Equal(t, account{ID: 1}, dst)
+
+	// Per-call options are applied on top of the default ones
+	dst = account{}
+	require.NoError(t, mapper.Copy(&dst, account{ID: 2, Secret: "s3cr3t"}, mapper.WithMaxDepth(4)))
+	assert.Equal(t, account{ID: 2}, dst)
+
+	mapper.SetDefault()
+	dst = account{}
+	require.NoError(t, mapper.Copy(&dst, account{ID: 3, Secret: "s3cr3t"}))
+	assert.Equal(t, account{ID: 3, Secret: "s3cr3t"}, dst)
+}
+
+func TestCycleHandling(t *testing.T) {
+	a := &cycleNode{Name: "a"}
+	a.Next = &cycleNode{Name: "b", Next: a}
+
+	var dst cycleNode
+	require.NoError(t, mapper.Copy(&dst, a, mapper.WithCycleHandling(mapper.CycleReuse)))
+	assert.Same(t, &dst, dst.Next.Next)
+
+	dst = cycleNode{Next: &cycleNode{Name: "stale"}}
+	require.NoError(t, mapper.Copy(&dst, &cycleNode{Name: "a", Next: &cycleNode{Name: "b", Next: a}},
+		mapper.WithCycleHandling(mapper.CycleNilOut)))
+	assert.Equal(t, "b", dst.Next.Name)
+	assert.Equal(t, "a", dst.Next.Next.Name)
+	assert.Equal(t, "b", dst.Next.Next.Next.Name)
+	assert.Nil(t, dst.Next.Next.Next.Next)
+
+	// Shared references keep their aliasing in the destination
+	type Pair struct {
+		Home, Work *TestAddress
+		Tags       map[string]int
+		Again      map[string]int
+	}
+	shared := &TestAddress{City: "NY"}
+	tags := map[string]int{"x": 1}
+	var pair Pair
+	require.NoError(t, mapper.Copy(&pair, Pair{Home: shared, Work: shared, Tags: tags, Again: tags},
+		mapper.WithCycleHandling(mapper.CycleReuse)))
+	assert.Same(t, pair.Home, pair.Work)
+	assert.NotSame(t, shared, pair.Home)
+	pair.Tags["y"] = 2
+	assert.Equal(t, 2, pair.Again["y"])
+	assert.NotContains(t, tags, "y")
+
+	pair = Pair{}
+	require.NoError(t, mapper.Copy(&pair, Pair{Home: shared, Work: shared}))
+	assert.NotSame(t, pair.Home, pair.Work)
+}
+
+type roundTripOrder struct {
+	ID       int
+	Total    float64
+	Notes    string
+	PlacedAt time.Time
+	Tags     map[string]int
+}
+
+type roundTripOrderDTO struct {
+	ID       int
+	Total    int64
+	PlacedAt time.Time
+	Tags     map[string]int
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	sample := roundTripOrder{
+		ID:       7,
+		Total:    19.99,
+		Notes:    "leave at the door",
+		PlacedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Tags:     map[string]int{"gift": 1},
+	}
+
+	report, err := mapper.CheckRoundTrip[roundTripOrder, roundTripOrderDTO](sample)
+	require.NoError(t, err)
+	assert.False(t, report.Lossless())
+	assert.Equal(t, []string{"roundTripOrder.Total", "roundTripOrder.Notes"}, report.Paths())
+	assert.Equal(t, 19.99, report.Losses[0].Original)
+	assert.Equal(t, 19.0, report.Losses[0].RoundTrip)
+	assert.Equal(t, `roundTripOrder.Notes: leave at the door became `, report.Losses[1].String())
+
+	report, err = mapper.CheckRoundTrip[roundTripOrder, roundTripOrder](sample)
+	require.NoError(t, err)
+	assert.True(t, report.Lossless())
+
+	_, err = mapper.CheckRoundTrip[roundTripOrder, []int](sample)
+	assert.Error(t, err)
+}
+
+func TestShallowCopy(t *testing.T) {
+	type Doc struct {
+		Tags   []string
+		Meta   map[string]string
+		Author *TestAddress
+	}
+	type TaggedDoc struct {
+		Tags   []string `mapper:",shallow"`
+		Meta   map[string]string
+		Author *TestAddress
+	}
+	src := Doc{Tags: []string{"a"}, Meta: map[string]string{"k": "v"}, Author: &TestAddress{City: "NY"}}
+
+	var deep Doc
+	require.NoError(t, mapper.Copy(&deep, src))
+	assert.NotSame(t, src.Author, deep.Author)
+	deep.Tags[0] = "changed"
+	assert.Equal(t, "a", src.Tags[0])
+
+	var shallow Doc
+	require.NoError(t, mapper.Copy(&shallow, src, mapper.WithDeepCopy(false)))
+	assert.Same(t, src.Author, shallow.Author)
+	shallow.Tags[0] = "b"
+	shallow.Meta["k"] = "w"
+	assert.Equal(t, "b", src.Tags[0])
+	assert.Equal(t, "w", src.Meta["k"])
+
+	var tagged TaggedDoc
+	require.NoError(t, mapper.Copy(&tagged, src))
+	tagged.Tags[0] = "c"
+	assert.Equal(t, "c", src.Tags[0])
+	assert.NotSame(t, src.Author, tagged.Author)
+	tagged.Meta["k"] = "x"
+	assert.Equal(t, "w", src.Meta["k"])
+}
+
+func TestMaxSliceCapacity(t *testing.T) {
+	type Batch struct {
+		Items []int
+	}
+	src := Batch{Items: []int{1, 2, 3, 4}}
+
+	var dst Batch
+	err := mapper.Copy(&dst, src, mapper.WithMaxSliceCapacity(3))
+	require.ErrorIs(t, err, mapper.ErrSliceTooLarge)
+	var limitErr *mapper.LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, "Batch.Items", limitErr.MapError.Path)
+	assert.Nil(t, dst.Items)
+
+	require.NoError(t, mapper.Copy(&dst, src,
+		mapper.WithMaxSliceCapacity(3),
+		mapper.WithSliceOverflowPolicy(mapper.SliceOverflowTruncate)))
+	assert.Equal(t, []int{1, 2, 3}, dst.Items)
+
+	dst = Batch{}
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithMaxSliceCapacity(4)))
+	assert.Equal(t, src.Items, dst.Items)
+}
+
+type shipStatus int
+
+func (s shipStatus) String() string {
+	return [...]string{"pending", "shipped"}[s]
+}
+
+type trackingID struct{ carrier, code string }
+
+func (id *trackingID) String() string { return id.carrier + ":" + id.code }
+
+func TestStringerFallback(t *testing.T) {
+	type Shipment struct {
+		Status   shipStatus
+		Tracking trackingID
+		Label    string
+	}
+	type ShipmentView struct {
+		Status   string
+		Tracking string
+		Label    string
+	}
+	src := &Shipment{Status: 1, Tracking: trackingID{"ups", "1Z"}, Label: "fragile"}
+
+	var view ShipmentView
+	require.NoError(t, mapper.Copy(&view, src, mapper.WithStringerFallback(true)))
+	assert.Equal(t, ShipmentView{Status: "shipped", Tracking: "ups:1Z", Label: "fragile"}, view)
+
+	view = ShipmentView{}
+	require.NoError(t, mapper.Copy(&view, src))
+	assert.NotEqual(t, "shipped", view.Status)
+	assert.Empty(t, view.Tracking)
+}
+
+func TestAllowPrivateFields(t *testing.T) {
+	if !mapper.UnsafeEnabled {
+		t.Skip("private field access needs package unsafe")
+	}
+
+	src := ledger.NewEntry(7, 1250, "refund")
+
+	var copied ledger.Entry
+	require.NoError(t, mapper.Copy(&copied, src, mapper.WithAllowPrivateFields(true)))
+	assert.Equal(t, 7, copied.ID())
+	assert.Equal(t, int64(1250), copied.Amount())
+	assert.Equal(t, "refund", copied.Memo)
+
+	var exported ledger.Entry
+	require.NoError(t, mapper.Copy(&exported, &src))
+	assert.Equal(t, ledger.NewEntry(0, 0, "refund"), exported)
+
+	type entryRecord struct {
+		id   int
+		Memo string
+	}
+	var record entryRecord
+	require.NoError(t, mapper.Copy(&record, src, mapper.WithAllowPrivateFields(true)))
+	assert.Equal(t, entryRecord{id: 7, Memo: "refund"}, record)
+}
+
+func TestKeepRaw(t *testing.T) {
+	type Signup struct {
+		Email   string
+		Age     *int
+		Country string `mapper:",keepraw=RawCountry"`
+	}
+	type SignupDTO struct {
+		Email      string `mapper:",converter=lower,keepraw=RawEmail"`
+		RawEmail   string
+		Age        int `mapper:",keepraw=RawAge"`
+		RawAge     any
+		Country    string
+		RawCountry string
+	}
+	lower := func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(strings.ToLower(strings.TrimSpace(v.String()))), nil
+	}
+	upper := func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(strings.ToUpper(v.String())), nil
+	}
+	age := 42
+	src := Signup{Email: " Ada@Example.COM", Age: &age, Country: "de"}
+
+	var dst SignupDTO
+	require.NoError(t, mapper.Copy(&dst, src,
+		mapper.WithNamedConverter("lower", lower),
+		mapper.WithFieldConverter("Country", upper)))
+	assert.Equal(t, "ada@example.com", dst.Email)
+	assert.Equal(t, " Ada@Example.COM", dst.RawEmail)
+	assert.Equal(t, 42, dst.Age)
+	assert.Equal(t, 42, dst.RawAge)
+	assert.Equal(t, "DE", dst.Country)
+	assert.Equal(t, "de", dst.RawCountry)
+
+	type BadDTO struct {
+		Email string `mapper:",keepraw=Missing"`
+	}
+	var bad BadDTO
+	assert.ErrorIs(t, mapper.Copy(&bad, src), mapper.ErrUnsupportedType)
+}
+
+func TestManifest(t *testing.T) {
+	newRegistry := func(caseSensitive bool) *mapper.Registry {
+		reg := mapper.NewRegistry(mapper.WithCaseSensitive(caseSensitive))
+		reg.Register("api", mapper.WithIgnoreFields("Email"),
+			mapper.WithCustomConverter(reflect.TypeOf(time.Time{}), func(v reflect.Value) (reflect.Value, error) { return v, nil }))
+		reg.Register("storage")
+		return reg
+	}
+	pair := mapper.Pair[GenUser, GenUserDTO]()
+
+	approved, err := newRegistry(true).Manifest(pair)
+	require.NoError(t, err)
+	require.Len(t, approved.Profiles, 2)
+	assert.Equal(t, "api", approved.Profiles[0].Name)
+	assert.Equal(t, "[Email]", approved.Profiles[0].Settings["IgnoreFields"])
+	assert.Equal(t, "{time.Time}", approved.Profiles[0].Settings["CustomConverters"])
+	require.Len(t, approved.Profiles[0].Plans, 1)
+	assert.True(t, strings.HasPrefix(approved.Hash, "sha256:"))
+
+	// Approved manifests round-trip through JSON
+	data, err := json.Marshal(approved)
+	require.NoError(t, err)
+	var loaded mapper.Manifest
+	require.NoError(t, json.Unmarshal(data, &loaded))
+
+	running, err := newRegistry(true).Manifest(pair)
+	require.NoError(t, err)
+	assert.Equal(t, approved.Hash, running.Hash)
+	require.NoError(t, running.Verify(&loaded))
+
+	drifted, err := newRegistry(false).Manifest(pair)
+	require.NoError(t, err)
+	err = drifted.Verify(&loaded)
+	require.ErrorIs(t, err, mapper.ErrManifestMismatch)
+	assert.ErrorContains(t, err, `profile "api": CaseSensitive is "", approved "true"`)
+
+	loaded.Profiles[1].Settings["MaxDepth"] = "1000"
+	assert.ErrorContains(t, running.Verify(&loaded), "approved manifest does not match its hash")
+}
+
+func TestJSONTagMatching(t *testing.T) {
+	type Src struct {
+		Name  string `json:"full_name,omitempty"`
+		Email string `json:"email_address"`
+		Age   int    `json:"-"`
+	}
+	type Dst struct {
+		FullName string `json:"full_name"`
+		Mail     string `json:"EMAIL_ADDRESS,omitempty"`
+		Age      int
+	}
+	src := Src{Name: "Ada", Email: "ada@example.com", Age: 36}
+
+	var dst Dst
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithJSONTag(true)))
+	assert.Equal(t, Dst{FullName: "Ada", Age: 36}, dst)
+
+	dst = Dst{}
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithJSONTag(true), mapper.WithCaseSensitive(false)))
+	assert.Equal(t, Dst{FullName: "Ada", Mail: "ada@example.com", Age: 36}, dst)
+
+	dst = Dst{}
+	require.NoError(t, mapper.Copy(&dst, src))
+	assert.Equal(t, Dst{Age: 36}, dst)
+}
+
+func TestYAMLTagMatching(t *testing.T) {
+	type Src struct {
+		Timeout string            `yaml:"request_timeout,omitempty"`
+		Hosts   []string          `yaml:"hosts,flow"`
+		Labels  map[string]string `yaml:"labels" json:"tags"`
+		Debug   bool              `yaml:"-"`
+	}
+	type Dst struct {
+		RequestTimeout string            `yaml:"request_timeout"`
+		Servers        []string          `yaml:"hosts"`
+		Tags           map[string]string `yaml:"tags"`
+		Debug          bool
+	}
+	src := Src{
+		Timeout: "5s",
+		Hosts:   []string{"a", "b"},
+		Labels:  map[string]string{"env": "prod"},
+		Debug:   true,
+	}
+
+	var dst Dst
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithYAMLTag(true)))
+	assert.Equal(t, Dst{RequestTimeout: "5s", Servers: []string{"a", "b"}, Debug: true}, dst)
+
+	dst = Dst{}
+	require.NoError(t, mapper.Copy(&dst, src, mapper.WithYAMLTag(true), mapper.WithJSONTag(true)))
+	assert.Equal(t, Dst{
+		RequestTimeout: "5s",
+		Servers:        []string{"a", "b"},
+		Tags:           map[string]string{"env": "prod"},
+		Debug:          true,
+	}, dst)
+
+	dst = Dst{}
+	require.NoError(t, mapper.Copy(&dst, src))
+	assert.Equal(t, Dst{Debug: true}, dst)
+}
+
+func TestNameAffixes(t *testing.T) {
+	type UserEntity struct {
+		DBUserName string
+		DBEmail    string
+		DBID       int
+		IDField    string
+	}
+	type UserDTO struct {
+		UserName   string
+		Email      string
+		DBID       int
+		IDFieldDTO string
+	}
+	entity := UserEntity{DBUserName: "ada", DBEmail: "ada@example.com", DBID: 7, IDField: "x1"}
+
+	var dto UserDTO
+	require.NoError(t, mapper.Copy(&dto, entity, mapper.WithSourcePrefix("DB"), mapper.WithDestSuffix("DTO")))
+	assert.Equal(t, UserDTO{UserName: "ada", Email: "ada@example.com", DBID: 7, IDFieldDTO: "x1"}, dto)
+
+	dto = UserDTO{}
+	require.NoError(t, mapper.Copy(&dto, entity))
+	assert.Equal(t, UserDTO{DBID: 7}, dto)
+
+	users := mapper.NewBidirectional[UserEntity, UserDTO](mapper.WithSourcePrefix("DB"), mapper.WithDestSuffix("DTO"))
+	back, err := users.BToA.Map(UserDTO{UserName: "grace", Email: "grace@example.com", DBID: 9, IDFieldDTO: "x2"})
+	require.NoError(t, err)
+	assert.Equal(t, UserEntity{DBUserName: "grace", DBEmail: "grace@example.com", DBID: 9, IDField: "x2"}, back)
+
+	type lowerEntity struct{ DbUsername string }
+	var lower UserDTO
+	require.NoError(t, mapper.Copy(&lower, lowerEntity{DbUsername: "linus"}, mapper.WithSourcePrefix("Db"), mapper.WithCaseSensitive(false)))
+	assert.Equal(t, "linus", lower.UserName)
+}
+
+func TestBSONTagMatching(t *testing.T) {
+	type Document struct {
+		ID      string `bson:"_id,omitempty"`
+		Name    string `bson:"display_name" json:"name"`
+		Profile string `bson:",inline"`
+	}
+	type User struct {
+		UserID  string `bson:"_id"`
+		Display string `bson:"display_name"`
+		Name    string `json:"name"`
+		Profile string
+	}
+	doc := Document{ID: "u1", Name: "Ada", Profile: "admin"}
+
+	var user User
+	require.NoError(t, mapper.Copy(&user, doc, mapper.WithBSONTag(true)))
+	assert.Equal(t, User{UserID: "u1", Display: "Ada", Profile: "admin"}, user)
+
+	// JSON names take precedence over BSON names
+	user = User{}
+	require.NoError(t, mapper.Copy(&user, doc, mapper.WithBSONTag(true), mapper.WithJSONTag(true)))
+	assert.Equal(t, User{UserID: "u1", Name: "Ada", Profile: "admin"}, user)
+}
+
+func TestMatchByDestination(t *testing.T) {
+	type Customer struct {
+		Name  string
+		Email string
+	}
+	type Order struct {
+		ID          string
+		AmountCents int64
+		Customer    Customer
+		Notes       string
+	}
+	type OrderView struct {
+		ID           string
+		Total        int64  `mapper:"AmountCents"`
+		CustomerName string `mapper:"Customer.Name"`
+		Email        string `mapper:"Customer.Email"`
+		Notes        string `mapper:"-"`
+		Status       string
+	}
+	order := Order{
+		ID:          "o-1",
+		AmountCents: 1250,
+		Customer:    Customer{Name: "Ada", Email: "ada@example.com"},
+		Notes:       "internal",
+	}
+
+	var view OrderView
+	require.NoError(t, mapper.Copy(&view, order, mapper.WithMatchBy(mapper.MatchByDestination)))
+	assert.Equal(t, OrderView{ID: "o-1", Total: 1250, CustomerName: "Ada", Email: "ada@example.com"}, view)
+
+	// Source-driven matching ignores destination tags naming source fields
+	view = OrderView{}
+	require.NoError(t, mapper.Copy(&view, order))
+	assert.Equal(t, OrderView{ID: "o-1"}, view)
+
+	m := mapper.NewMapper(mapper.WithMatchBy(mapper.MatchByDestination), mapper.WithCaseSensitive(false))
+	plan, err := m.Plan(reflect.TypeOf(Order{}), reflect.TypeOf(OrderView{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Customer", "Notes"}, plan.UnmappedSource)
+	assert.Equal(t, []string{"Status"}, plan.UnmappedDestination)
+
+	type lowerView struct {
+		Id string
+	}
+	var lower lowerView
+	require.NoError(t, m.Map(&lower, order))
+	assert.Equal(t, "o-1", lower.Id)
+}
+
+func TestCycleReuseTemporaries(t *testing.T) {
+	shared := &cycleNode{Name: "p"}
+
+	// Map values are built in a reused temporary slot
+	var nodes map[string]*cycleNode
+	require.NoError(t, mapper.Copy(&nodes, map[string]*cycleNode{"a": shared, "b": shared},
+		mapper.WithCycleHandling(mapper.CycleReuse)))
+	require.NotNil(t, nodes["a"])
+	require.NotNil(t, nodes["b"])
+	assert.Same(t, nodes["a"], nodes["b"])
+	assert.NotSame(t, shared, nodes["a"])
+
+	// Interface values are built in a temporary as well
+	type Holder struct {
+		First, Second interface{}
+	}
+	var holder Holder
+	require.NoError(t, mapper.Copy(&holder, Holder{First: shared, Second: shared},
+		mapper.WithCycleHandling(mapper.CycleReuse)))
+	first, ok := holder.First.(*cycleNode)
+	require.True(t, ok)
+	second, ok := holder.Second.(*cycleNode)
+	require.True(t, ok)
+	assert.Same(t, first, second)
+	assert.Equal(t, "p", second.Name)
+	assert.NotSame(t, shared, first)
+}
+
+func TestAtomicValueFields(t *testing.T) {
+	type Settings struct {
+		Current atomic.Value `mapper:",atomic"`
+	}
+	type SettingsDTO struct {
+		Current interface{}
+	}
+
+	// An unset atomic.Value loads as nil and is left unset
+	var mirror Settings
+	require.NoError(t, mapper.Copy(&mirror, &Settings{}))
+	assert.Nil(t, mirror.Current.Load())
+
+	var dto SettingsDTO
+	require.NoError(t, mapper.Copy(&dto, &Settings{}))
+	assert.Nil(t, dto.Current)
+
+	src := &Settings{}
+	src.Current.Store("v2")
+	require.NoError(t, mapper.Copy(&mirror, src))
+	assert.Equal(t, "v2", mirror.Current.Load())
+
+	// Storing a value of another type fails instead of panicking
+	var held Settings
+	held.Current.Store(1)
+	err := mapper.Copy(&held, SettingsDTO{Current: "v3"})
+	var mapErr *mapper.MapError
+	require.ErrorAs(t, err, &mapErr)
+	assert.ErrorIs(t, err, mapper.ErrTypeMismatch)
+	assert.Equal(t, 1, held.Current.Load())
+}
+
+func TestConverterOutputOfSourceType(t *testing.T) {
+	type Email string
+	type Signup struct {
+		Email string
+		Name  string
+	}
+	type Account struct {
+		Email Email
+		Name  string
+	}
+	normalize := func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(strings.ToLower(strings.TrimSpace(v.String()))), nil
+	}
+
+	var account Account
+	require.NoError(t, mapper.Copy(&account, Signup{Email: "  Ada@Example.COM ", Name: "Ada"},
+		mapper.WithCustomConverter(reflect.TypeOf(""), normalize)))
+	assert.Equal(t, Account{Email: "ada@example.com", Name: "ada"}, account)
+}
@@ -0,0 +1,80 @@
+package gomap_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fbarikzehi/gomap/mapper"
+	"github.com/fbarikzehi/gomap/mapper/mappertest"
+)
+
+// failureRecorder records the failures reported through testing.TB.
+type failureRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *failureRecorder) Helper() {}
+
+func (r *failureRecorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+type signup struct {
+	ID    int
+	Email string
+}
+
+type signupDTO struct {
+	ID    int
+	Email string
+}
+
+func TestMappingInvariants(t *testing.T) {
+	lower := func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(strings.ToLower(v.String())), nil
+	}
+	signups := mapper.New[signup, signupDTO](mapper.WithFieldConverter("Email", lower))
+
+	idPreserved := mappertest.Preserved("ID preserved",
+		func(s signup) int { return s.ID },
+		func(d signupDTO) int { return d.ID })
+	emailLowered := mappertest.Invariant[signup, signupDTO]{
+		Name:  "email lowercased",
+		Holds: func(s signup, d signupDTO) bool { return d.Email == strings.ToLower(s.Email) },
+	}
+	emailUnchanged := mappertest.Preserved("email unchanged",
+		func(s signup) string { return s.Email },
+		func(d signupDTO) string { return d.Email })
+
+	// Mixed-case emails, so that lowercasing always changes them
+	cfg := &quick.Config{
+		MaxCount: 50,
+		Values: func(args []reflect.Value, r *rand.Rand) {
+			args[0] = reflect.ValueOf(signup{ID: r.Int(), Email: "User" + strconv.Itoa(r.Intn(1000)) + "@Example.com"})
+		},
+	}
+	mappertest.Check(t, signups, cfg, idPreserved, emailLowered)
+
+	rec := &failureRecorder{TB: t}
+	mappertest.Check(rec, signups, cfg, idPreserved, emailUnchanged)
+	require.Len(t, rec.failures, 1)
+	assert.Contains(t, rec.failures[0], "email unchanged does not hold for")
+
+	failing := mapper.New[signup, signupDTO](mapper.WithFieldConverter("Email", func(v reflect.Value) (reflect.Value, error) {
+		return v, fmt.Errorf("boom")
+	}))
+	rec = &failureRecorder{TB: t}
+	mappertest.Check(rec, failing, nil, idPreserved)
+	require.Len(t, rec.failures, 1)
+	assert.Contains(t, rec.failures[0], "ID preserved: mapping")
+	assert.Contains(t, rec.failures[0], "boom")
+}
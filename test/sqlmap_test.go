@@ -0,0 +1,105 @@
+package gomap_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fbarikzehi/gomap/mapper"
+	"github.com/fbarikzehi/gomap/mapper/sqlmap"
+)
+
+// rowsDriver is a database/sql driver serving a fixed result set for every
+// query.
+type rowsDriver struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (d *rowsDriver) Open(string) (driver.Conn, error) { return &rowsConn{d}, nil }
+
+type rowsConn struct{ d *rowsDriver }
+
+func (c *rowsConn) Prepare(string) (driver.Stmt, error) { return &rowsStmt{c.d}, nil }
+func (c *rowsConn) Close() error                        { return nil }
+func (c *rowsConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type rowsStmt struct{ d *rowsDriver }
+
+func (s *rowsStmt) Close() error                               { return nil }
+func (s *rowsStmt) NumInput() int                              { return 0 }
+func (s *rowsStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s *rowsStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &rowsCursor{d: s.d}, nil
+}
+
+type rowsCursor struct {
+	d    *rowsDriver
+	next int
+}
+
+func (r *rowsCursor) Columns() []string { return r.d.columns }
+func (r *rowsCursor) Close() error      { return nil }
+func (r *rowsCursor) Next(dest []driver.Value) error {
+	if r.next >= len(r.d.rows) {
+		return io.EOF
+	}
+	copy(dest, r.d.rows[r.next])
+	r.next++
+	return nil
+}
+
+func snakeToCamel(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			b.WriteRune(unicode.ToUpper(rune(part[0])))
+			b.WriteString(part[1:])
+		}
+	}
+	return b.String()
+}
+
+func TestScanRows(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sql.Register("sqlmap-test", &rowsDriver{
+		columns: []string{"id", "full_name", "email", "created_at", "unused"},
+		rows: [][]driver.Value{
+			{int64(1), []byte("Ada Lovelace"), "ada@example.com", created, "x"},
+			{int64(2), []byte("Alan Turing"), nil, created, "y"},
+		},
+	})
+	db, err := sql.Open("sqlmap-test", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	type User struct {
+		ID        int
+		FullName  string
+		Email     sql.NullString
+		CreatedAt time.Time
+	}
+
+	rows, err := db.Query("SELECT")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var users []User
+	require.NoError(t, sqlmap.ScanRows(rows, &users,
+		mapper.WithFieldNameMapper(snakeToCamel),
+		mapper.WithNullableSupport(true),
+	))
+	assert.Equal(t, []User{
+		{ID: 1, FullName: "Ada Lovelace", Email: sql.NullString{String: "ada@example.com", Valid: true}, CreatedAt: created},
+		{ID: 2, FullName: "Alan Turing", CreatedAt: created},
+	}, users)
+
+	assert.ErrorIs(t, sqlmap.ScanRows[User](nil, &users), mapper.ErrNilPointer)
+}